@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// /events ke topics
+const (
+	TopicProgress = "progress" // file transfers ke TransferEvent (start, progress, complete, error, corrupt)
+	TopicConnect  = "connect"  // WebRTC peers ke connection state changes
+)
+
+var knownTopics = map[string]bool{TopicProgress: true, TopicConnect: true}
+
+const (
+	eventPingInterval = 30 * time.Second
+	eventPongWait     = 2 * eventPingInterval // itni der pong na aaye toh connection dead maante hai
+	eventWriteWait    = 10 * time.Second
+	eventBufferSize   = 64 // slow subscriber ke liye itne events buffer hote hai, baaki drop
+)
+
+// Event /events WebSocket par jaane wala ek message hai
+type Event struct {
+	Topic string      `json:"topic"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// ConnectionEvent TopicConnect ka data hai
+type ConnectionEvent struct {
+	PeerID string `json:"peer_id"`
+	State  string `json:"state"`
+}
+
+// EventHub client ke events saare /events subscribers tak pahunchata hai
+type EventHub struct {
+	mu   sync.Mutex
+	subs map[chan Event]map[string]bool // subscriber channel -> topics (nil = saare topics)
+}
+
+func NewEventHub() *EventHub {
+	return &EventHub{subs: make(map[chan Event]map[string]bool)}
+}
+
+// Subscribe naya subscriber banata hai; topics empty ho toh saare topics milte hai.
+// Returned func subscription band karta hai.
+func (h *EventHub) Subscribe(topics []string) (<-chan Event, func()) {
+	var filter map[string]bool
+	if len(topics) > 0 {
+		filter = make(map[string]bool, len(topics))
+		for _, t := range topics {
+			filter[t] = true
+		}
+	}
+	ch := make(chan Event, eventBufferSize)
+	h.mu.Lock()
+	h.subs[ch] = filter
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish event ko us topic ke saare subscribers ko bhejta hai. Kabhi block nahi karta,
+// subscriber ka buffer full ho toh uske liye event drop ho jaata hai.
+func (h *EventHub) Publish(topic string, data interface{}) {
+	ev := Event{Topic: topic, Time: time.Now(), Data: data}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch, filter := range h.subs {
+		if filter != nil && !filter[topic] {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+var eventUpgrader = websocket.Upgrader{}
+
+// EventsHandler `GET /events?topics=progress,connect` serve karta hai: WebSocket par events JSON mein stream hote hai.
+// Har 30 second par ping jaata hai; client disconnect ho ya request ka context cancel ho toh connection close frame ke saath band hota hai.
+func EventsHandler(hub *EventHub) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var topics []string
+		if v := r.URL.Query().Get("topics"); v != "" {
+			for _, t := range strings.Split(v, ",") {
+				t = strings.TrimSpace(t)
+				if !knownTopics[t] {
+					http.Error(w, "unknown topic: "+t, http.StatusBadRequest)
+					return
+				}
+				topics = append(topics, t)
+			}
+		}
+
+		conn, err := eventUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade khud error response likh deta hai
+			logger.Debug("Event feed upgrade failed", "error", err)
+			return
+		}
+		defer conn.Close()
+
+		events, unsubscribe := hub.Subscribe(topics)
+		defer unsubscribe()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go readEventControl(conn, cancel)
+
+		ping := time.NewTicker(eventPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(eventWriteWait))
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				conn.SetWriteDeadline(time.Now().Add(eventWriteWait))
+				if err := conn.WriteJSON(ev); err != nil {
+					logger.Debug("Event feed write failed", "error", err)
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventWriteWait)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// client se aane wale frames padhta hai taaki pong aur close frames process ho; client chala jaye toh cancel karta hai
+func readEventControl(conn *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(eventPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventPongWait))
+	})
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"torrentium/db"
+)
+
+// FilePageFetcher tracker se files ka ek page (1-based) aur total count laata hai
+type FilePageFetcher func(ctx context.Context, page, perPage int) ([]db.File, int, error)
+
+// TagSearcher tracker se ek tag wali saari files laata hai
+type TagSearcher func(ctx context.Context, tag string) ([]db.File, error)
+
+// FilesHandler `GET /files?page=1&per_page=20` serve karta hai. Response JSON mein files aur total hote hai,
+// aur `Link` header mein first/prev/next/last pages ke URLs (RFC 8288).
+// `tag` query parameter ho (jaise `/files?tag=video`) toh sirf us tag wali files aati hai, wahi pagination ke saath.
+func FilesHandler(fetch FilePageFetcher, search TagSearcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		page, err := queryInt(r.URL.Query(), "page", 1)
+		if err != nil || page < 1 {
+			http.Error(w, "invalid page", http.StatusBadRequest)
+			return
+		}
+		perPage, err := queryInt(r.URL.Query(), "per_page", 20)
+		if err != nil || perPage < 1 || perPage > 100 {
+			http.Error(w, "invalid per_page (1-100)", http.StatusBadRequest)
+			return
+		}
+
+		var files []db.File
+		var total int
+		if tag := r.URL.Query().Get("tag"); tag != "" {
+			files, total, err = tagPage(r.Context(), search, tag, page, perPage)
+		} else {
+			files, total, err = fetch(r.Context(), page, perPage)
+		}
+		if err != nil {
+			logger.Warn("Failed to fetch file page", "page", page, "error", err)
+			http.Error(w, "failed to fetch files", http.StatusBadGateway)
+			return
+		}
+		if files == nil {
+			files = []db.File{}
+		}
+
+		lastPage := (total + perPage - 1) / perPage
+		if lastPage < 1 {
+			lastPage = 1
+		}
+		if link := linkHeader(r.URL, page, perPage, lastPage); link != "" {
+			w.Header().Set("Link", link)
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"files":    files,
+			"total":    total,
+			"page":     page,
+			"per_page": perPage,
+		})
+	})
+}
+
+// tag search ke saare results mein se ek page kaat kar deta hai
+func tagPage(ctx context.Context, search TagSearcher, tag string, page, perPage int) ([]db.File, int, error) {
+	files, err := search(ctx, tag)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(files)
+	start := (page - 1) * perPage
+	if start >= total {
+		return nil, total, nil
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	return files[start:end], total, nil
+}
+
+// query parameter ko int mein parse karta hai, na ho toh default
+func queryInt(q url.Values, key string, def int) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func linkHeader(u *url.URL, page, perPage, lastPage int) string {
+	pageURL := func(p int) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return u.Path + "?" + q.Encode()
+	}
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(page-1)))
+	}
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+	return strings.Join(links, ", ")
+}
+
+// value ko JSON mein encode karke response likhta hai
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("Failed to write JSON response", "error", err)
+	}
+}
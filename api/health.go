@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"torrentium/db"
+)
+
+// DefaultAtRiskThreshold itni der se offline seeders wali files at-risk maani jaati hai, agar request kuch aur na kahe
+const DefaultAtRiskThreshold = 24 * time.Hour
+
+// AtRiskFetcher tracker se woh files laata hai jinke saare seeders threshold se zyada der se offline hai
+type AtRiskFetcher func(ctx context.Context, threshold time.Duration) ([]db.AtRiskFile, error)
+
+// AtRiskFilesHandler `GET /health/at-risk-files?threshold=24h` serve karta hai. threshold Go duration format mein hai.
+func AtRiskFilesHandler(fetch AtRiskFetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		threshold := DefaultAtRiskThreshold
+		if v := r.URL.Query().Get("threshold"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < time.Second {
+				http.Error(w, "threshold must be a duration like 24h", http.StatusBadRequest)
+				return
+			}
+			threshold = d
+		}
+
+		files, err := fetch(r.Context(), threshold)
+		if err != nil {
+			http.Error(w, "failed to fetch at-risk files", http.StatusBadGateway)
+			return
+		}
+		if files == nil {
+			files = []db.AtRiskFile{}
+		}
+		writeJSON(w, http.StatusOK, files)
+	})
+}
+
+// healthCheckTimeout tak check pura na ho toh dependency unhealthy maani jaati hai
+const healthCheckTimeout = 5 * time.Second
+
+// HealthChecker koi dependency (jaise database) zinda hai ya nahi check karta hai
+type HealthChecker func(ctx context.Context) error
+
+// HealthStatus /healthz ka response hai: "ok", ya "unavailable" aur error message
+type HealthStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthzHandler `GET /healthz` serve karta hai: check pass ho toh 200 aur {"status":"ok"}, warna 503 aur error message
+func HealthzHandler(check HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+		if err := check(ctx); err != nil {
+			logger.Warn("Health check failed", "error", err)
+			writeJSON(w, http.StatusServiceUnavailable, HealthStatus{Status: "unavailable", Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, HealthStatus{Status: "ok"})
+	})
+}
+
+// HostStatus /readyz mein libp2p host ki halat hai
+type HostStatus struct {
+	PeerID         string   `json:"peer_id"`
+	ListenAddrs    []string `json:"listen_addrs"`
+	ConnectedPeers int      `json:"connected_peers"`
+}
+
+// WebRTCStatus /readyz mein WebRTC peers ki ginti hai, connection state ke hisaab se
+type WebRTCStatus struct {
+	Peers        int            `json:"peers"`
+	States       map[string]int `json:"states"`
+	Reconnecting int            `json:"reconnecting"`
+}
+
+// ReadinessReport /readyz ka response hai. Ready false ho toh Status "unavailable" aur Error mein wajah hoti hai.
+type ReadinessReport struct {
+	Status string       `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	Host   HostStatus   `json:"host"`
+	WebRTC WebRTCStatus `json:"webrtc"`
+}
+
+// ReadinessFetcher node ki readiness report banata hai; error ka matlab node abhi traffic lene layak nahi hai
+type ReadinessFetcher func() (ReadinessReport, error)
+
+// ReadyzHandler `GET /readyz` serve karta hai: libp2p host aur WebRTC peers ki halat, ready ho toh 200 warna 503
+func ReadyzHandler(fetch ReadinessFetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report, err := fetch()
+		if report.Host.ListenAddrs == nil {
+			report.Host.ListenAddrs = []string{}
+		}
+		if report.WebRTC.States == nil {
+			report.WebRTC.States = map[string]int{}
+		}
+		if err != nil {
+			report.Status, report.Error = "unavailable", err.Error()
+			writeJSON(w, http.StatusServiceUnavailable, report)
+			return
+		}
+		report.Status = "ok"
+		writeJSON(w, http.StatusOK, report)
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus metrics jo /metrics par expose hote hai. Client inhe transfers aur connection state changes par update karta hai.
+var (
+	BytesUploaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "torrentium_bytes_uploaded_total",
+		Help: "Total bytes of file data sent to peers.",
+	})
+	BytesDownloaded = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "torrentium_bytes_downloaded_total",
+		Help: "Total bytes of file data received from peers.",
+	})
+	ActiveConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "torrentium_active_connections",
+		Help: "Number of WebRTC peer connections currently in the connected state.",
+	})
+	FilesAnnounced = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "torrentium_files_announced",
+		Help: "Number of files this node has announced to the tracker.",
+	})
+	WebRTCConnectionState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "torrentium_webrtc_connection_state",
+		Help: "Number of live WebRTC peer connections in each connection state.",
+	}, []string{"state"})
+)
+
+// metrics ka apna registry, taaki sirf torrentium aur Go runtime metrics expose ho
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(
+		BytesUploaded,
+		BytesDownloaded,
+		ActiveConnections,
+		FilesAnnounced,
+		WebRTCConnectionState,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// MetricsHandler Prometheus text format mein saare metrics serve karta hai
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"torrentium/logging"
+)
+
+// api package ka logger
+var logger = logging.For("api")
+
+// Server client ka local HTTP API server hai (abhi /metrics), naye endpoints Handle se register hote hai
+type Server struct {
+	addr string
+	mux  *http.ServeMux
+}
+
+// NewServer diye gaye address (jaise ":9090") ke liye server banata hai aur /metrics register karta hai
+func NewServer(addr string) *Server {
+	s := &Server{addr: addr, mux: http.NewServeMux()}
+	s.mux.Handle("/metrics", MetricsHandler())
+	return s
+}
+
+// Handle server par ek naya endpoint register karta hai, ListenAndServe se pehle call karna hai
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ListenAndServe server start karta hai aur band hone tak block karta hai
+func (s *Server) ListenAndServe() error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Info("API server listening", "addr", s.addr)
+	return srv.ListenAndServe()
+}
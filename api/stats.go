@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"torrentium/db"
+)
+
+const (
+	defaultSeedersLimit = 10
+	maxSeedersLimit     = 100
+)
+
+// SeederFetcher tracker se top seeders laata hai
+type SeederFetcher func(ctx context.Context, limit int) ([]db.SeederRecord, error)
+
+// TopSeedersHandler `GET /stats/top-seeders?limit=10` serve karta hai: sabse zyada upload karne wale peers.
+// limit 1 se 100 tak hota hai.
+func TopSeedersHandler(fetch SeederFetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		limit, err := queryInt(r.URL.Query(), "limit", defaultSeedersLimit)
+		if err != nil || limit < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if limit > maxSeedersLimit {
+			limit = maxSeedersLimit
+		}
+
+		seeders, err := fetch(r.Context(), limit)
+		if err != nil {
+			http.Error(w, "failed to fetch top seeders", http.StatusBadGateway)
+			return
+		}
+		if seeders == nil {
+			seeders = []db.SeederRecord{}
+		}
+		writeJSON(w, http.StatusOK, seeders)
+	})
+}
+
+// NetworkStatsFetcher tracker se network ke totals laata hai
+type NetworkStatsFetcher func(ctx context.Context) (*db.NetworkStats, error)
+
+// NetworkStatsHandler `GET /stats` serve karta hai: files, peers aur transferred bytes ke totals
+func NetworkStatsHandler(fetch NetworkStatsFetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		stats, err := fetch(r.Context())
+		if err != nil {
+			http.Error(w, "failed to fetch network stats", http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// PeerStatus ek connected peer ki info hai jo /status mein dikhti hai
+type PeerStatus struct {
+	ID        string                             `json:"id"`
+	Transport string                             `json:"transport"` // "webrtc" ya "quic"
+	State     string                             `json:"state"`
+	ICEState  string                             `json:"ice_state,omitempty"`      // ICE connection state, sirf WebRTC peers ke liye
+	Gathering string                             `json:"ice_gathering,omitempty"`  // ICE candidate gathering state, sirf WebRTC peers ke liye
+	LocalAddr string                             `json:"ice_local_addr,omitempty"` // ICE ka local host address (ip:port), sirf WebRTC peers ke liye
+	Stats     *torrentiumWebRTC.ConnectionStats  `json:"stats,omitempty"`          // sirf WebRTC peers ke liye
+	Channel   *torrentiumWebRTC.DataChannelStats `json:"data_channel,omitempty"`   // binary data channel ke counters, sirf WebRTC peers ke liye
+	Transfers []torrentiumWebRTC.TransferInfo    `json:"transfers,omitempty"`      // chalte hue transfers, sirf WebRTC peers ke liye
+}
+
+// StatusReport node ka current status hai
+type StatusReport struct {
+	PeerID string       `json:"peer_id"`
+	Peers  []PeerStatus `json:"peers"`
+}
+
+// StatusFetcher client ka current status banata hai
+type StatusFetcher func() StatusReport
+
+// StatusHandler `GET /status` serve karta hai: node ka peer ID aur har peer ka connection state aur stats
+func StatusHandler(fetch StatusFetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report := fetch()
+		if report.Peers == nil {
+			report.Peers = []PeerStatus{}
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
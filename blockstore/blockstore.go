@@ -0,0 +1,177 @@
+package blockstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// BlockSize ek block ka size hai, torrent pieces jitna (256 KiB)
+const BlockSize = 256 * 1024
+
+// ErrNotFound tab aata hai jab store mein diye gaye hash ka block nahi hai
+var ErrNotFound = errors.New("block not found")
+
+// Store disk par ek simple content-addressable block store hai. Har block apne SHA-256 hash ke naam
+// se `<dir>/<pehle 2 hex chars>/<poora hex hash>` par save hota hai, isliye same data sirf ek baar store hota hai.
+type Store struct {
+	dir string
+}
+
+// Open diye gaye directory mein store kholta hai, directory na ho toh bana deta hai
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create block store %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(hash [32]byte) string {
+	h := hex.EncodeToString(hash[:])
+	return filepath.Join(s.dir, h[:2], h)
+}
+
+// Put block ko store karta hai. Hash data ke SHA-256 se match na kare toh error; block pehle se ho toh kuch nahi karta.
+func (s *Store) Put(hash [32]byte, data []byte) error {
+	if sha256.Sum256(data) != hash {
+		return fmt.Errorf("block hash mismatch for %x", hash[:8])
+	}
+	if s.Has(hash) {
+		return nil
+	}
+	p := s.path(hash)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	// temp file mein likh kar rename karte hai taaki aadha likha block kabhi dikhe nahi
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get block ka data return karta hai aur padhte waqt hash dobara verify karta hai
+func (s *Store) Get(hash [32]byte) ([]byte, error) {
+	data, err := os.ReadFile(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if sha256.Sum256(data) != hash {
+		return nil, fmt.Errorf("block %x is corrupted on disk", hash[:8])
+	}
+	return data, nil
+}
+
+// Has batata hai ki block store mein hai ya nahi
+func (s *Store) Has(hash [32]byte) bool {
+	_, err := os.Stat(s.path(hash))
+	return err == nil
+}
+
+// Writer aane wale data ko BlockSize ke blocks mein todta hai, har block store mein Put karta hai
+// aur phir dst mein likhta hai. Blocks() se file ke blocks ki ordered list milti hai.
+type Writer struct {
+	store  *Store
+	dst    io.WriteCloser
+	buf    []byte
+	hashes [][32]byte
+}
+
+// NewWriter dst ke upar ek block-storing writer banata hai
+func NewWriter(store *Store, dst io.WriteCloser) *Writer {
+	return &Writer{store: store, dst: dst, buf: make([]byte, 0, BlockSize)}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := BlockSize - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(w.buf) == BlockSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// buffer ke block ko hash karke store aur dst dono mein likhta hai
+func (w *Writer) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	hash := sha256.Sum256(w.buf)
+	if err := w.store.Put(hash, w.buf); err != nil {
+		return err
+	}
+	if _, err := w.dst.Write(w.buf); err != nil {
+		return err
+	}
+	w.hashes = append(w.hashes, hash)
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close aakhri (chhota) block flush karke dst close karta hai
+func (w *Writer) Close() error {
+	err := w.flush()
+	if cerr := w.dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Blocks ab tak likhe gaye blocks ke hashes order mein return karta hai
+func (w *Writer) Blocks() [][32]byte {
+	return w.hashes
+}
+
+// Reader blocks ki list ko store se padh kar ek continuous stream ki tarah deta hai
+type Reader struct {
+	store  *Store
+	hashes [][32]byte
+	cur    []byte
+}
+
+// NewReader diye gaye hashes ke blocks ko order mein padhne wala reader banata hai
+func NewReader(store *Store, hashes [][32]byte) *Reader {
+	return &Reader{store: store, hashes: hashes}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.cur) == 0 {
+		if len(r.hashes) == 0 {
+			return 0, io.EOF
+		}
+		data, err := r.store.Get(r.hashes[0])
+		if err != nil {
+			return 0, err
+		}
+		r.cur, r.hashes = data, r.hashes[1:]
+	}
+	n := copy(p, r.cur)
+	r.cur = r.cur[n:]
+	return n, nil
+}
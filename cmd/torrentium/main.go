@@ -0,0 +1,42 @@
+// torrentium chalte hue client (cmd/webrtc, --control-socket ya --daemon ke saath) ko ek command bhejta hai
+// aur uska output print karta hai, jaise `torrentium add foo.txt` ya `torrentium list`.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"torrentium/control"
+)
+
+func main() {
+	socket := flag.String("socket", control.DefaultSocketPath(), "control socket of the running client")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: torrentium [--socket <path>] <command> [args...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var resp *control.Response
+	var err error
+	if flag.Arg(0) == "pipe" {
+		// pipe ka data is process ka stdin hai
+		resp, err = control.SendStream(*socket, flag.Arg(0), flag.Args()[1:], os.Stdin)
+	} else {
+		resp, err = control.Send(*socket, flag.Arg(0), flag.Args()[1:])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(resp.Output)
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+}
@@ -3,19 +3,33 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
+	"time"
 
+	"torrentium/api"
+	"torrentium/config"
 	"torrentium/db"
+	"torrentium/db/memory"
+	"torrentium/logging"
 	"torrentium/p2p"
+	"torrentium/pidfile"
 	"torrentium/tracker"
 
 	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5"
 	"github.com/joho/godotenv"
 )
 
+// tracker binary ka logger, level aur format --log-level/--log-format flags se set hote hai
+var logger = logging.For("tracker")
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins for now
@@ -62,73 +76,244 @@ func (cm *ConnectionManager) SendToConnection(peerID string, msg p2p.Message) er
 }
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Unable to access .env file")
+	configPath := flag.String("config", "", "path to config file (default ~/.torrentium/config.yaml)")
+	logLevel := flag.String("log-level", "info", "log level: debug, info, warn or error")
+	logFormat := flag.String("log-format", "text", "log format: text or json")
+	pidFile := flag.String("pid-file", "", "write the process ID to this file and refuse to start if it names a running process")
+	noDB := flag.Bool("no-db", false, "keep the catalog in memory instead of Postgres; data is lost on exit (for testing only, not for production use)")
+	cleanupOrphans := flag.Bool("cleanup-orphans", false, "delete files whose seeders have all been offline for over 7 days, then exit")
+	flag.Parse()
+
+	// `config init` template config file likh kar exit kar deta hai
+	if handled, err := config.RunSubcommand(flag.Args(), *configPath); handled {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Initialize database
-	db.InitDB()
+	// .env pehle load karte hai taaki uske variables bhi config file ko override kar sake
+	envErr := godotenv.Load()
 
-	// Clear stale peer statuses
-	repo := db.NewRepository(db.DB)
-	if err := repo.MarkAllPeersOffline(context.Background()); err != nil {
-		log.Printf("Warning: Could not mark all peers offline on startup: %v", err)
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+		os.Exit(2)
+	}
+	// sirf explicitly diye gaye flags config ko override karte hai
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		case "log-format":
+			cfg.LogFormat = *logFormat
+		}
+	})
+
+	l, err := logging.New(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging flags: %v\n", err)
+		os.Exit(2)
+	}
+	logging.Configure(l)
+
+	if envErr != nil {
+		logger.Warn("Could not load .env file, proceeding with config and system environment variables", "error", envErr)
+	}
+
+	// do trackers ek hi database par na chale
+	if *pidFile != "" {
+		if err := pidfile.Write(*pidFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer pidfile.Remove(*pidFile)
+	}
+
+	// SIGINT/SIGTERM par yeh context cancel hota hai, jisse chal rahi database queries bhi ruk jaati hai
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var repo tracker.Repository
+	if *noDB {
+		logger.Warn("Running without a database: the catalog lives in memory and is lost on exit. For testing only, not for production use")
+		repo = memory.NewRepository()
+	} else {
+		// Initialize database (DATABASE_URL env config file ki database_url ko override karta hai)
+		dsn := cfg.DatabaseURL
+		if dsn == "" {
+			dsn = dsnFromEnv()
+		}
+		// Docker Compose mein Postgres tracker ke baad ready hota hai, isliye connection backoff ke saath retry hota hai
+		connectCtx, cancelConnect := context.WithTimeout(ctx, dbConnectTimeout)
+		pool, err := db.InitDBWithRetry(connectCtx, dsn, dbConnectAttempts, dbConnectBackoff)
+		cancelConnect()
+		if err != nil {
+			logger.Error("Failed to initialize database", "error", err)
+			os.Exit(1)
+		}
+		defer pool.Close()
+		repo = db.NewRepository(pool)
+	}
+
+	// operator ka one-shot cleanup: chal rahe tracker ke peers ko offline mark kiye bina sirf orphans hatata hai
+	if *cleanupOrphans {
+		removed, err := cleanupOrphanedFiles(ctx, tracker.NewTracker(repo))
+		if err != nil {
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %d orphaned file(s).\n", removed)
+		return
 	}
-	log.Println("-> Cleared stale online peer statuses.")
 
-	// Get WebSocket listen address
-	wsAddr := os.Getenv("TRACKER_WS_ADDR")
-	if wsAddr == "" {
-		wsAddr = ":8080" // Default WebSocket port
+	// Clear stale peer statuses
+	if err := repo.MarkAllPeersOffline(ctx); err != nil {
+		logger.Warn("Could not mark all peers offline on startup", "error", err)
 	}
+	logger.Info("Cleared stale online peer statuses")
+
+	// WebSocket listen address config file, TRACKER_WS_ADDR env ya default (:8080) se aata hai
+	wsAddr := cfg.TrackerAddr
 
 	// Create tracker instance
-	t := tracker.NewTracker()
-	log.Println("-> Tracker Initialized")
+	t := tracker.NewTracker(repo)
+	logger.Info("Tracker initialized")
+
+	// offline peers ki files tracker mein na padi rahe
+	go orphanCleanupLoop(ctx, t, orphanCleanupInterval)
 
 	// Create connection manager
 	cm := NewConnectionManager()
 
+	// Docker/Kubernetes liveness probe: database tak connection check
+	http.Handle("/healthz", api.HealthzHandler(repo.HealthCheck))
+
 	// Setup WebSocket handler
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		handleWebSocketConnection(w, r, t, cm)
+		handleWebSocketConnection(ctx, w, r, t, cm)
 	})
 
-	log.Printf("-> WebSocket tracker listening on %s", wsAddr)
-	log.Fatal(http.ListenAndServe(wsAddr, nil))
+	srv := &http.Server{Addr: wsAddr}
+	go func() {
+		<-ctx.Done()
+		logger.Info("Shutting down tracker")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("WebSocket tracker listening", "addr", wsAddr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("HTTP server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// startup par database connection ke retries: attempts, pehla backoff (har baar double) aur kul time limit
+const (
+	dbConnectAttempts = 10
+	dbConnectBackoff  = 500 * time.Millisecond
+	dbConnectTimeout  = 2 * time.Minute
+)
+
+// kitni der mein ek baar bina online peer wali files database se hatani hai
+const orphanCleanupInterval = 10 * time.Minute
+
+// seeders itni der se offline ho tabhi file hatti hai. Yeh api.DefaultAtRiskThreshold (24h) se kaafi lamba hai,
+// taaki operators ko at-risk list mein file dikhe aur dobara seed karne ka time mile.
+const orphanGracePeriod = 7 * 24 * time.Hour
+
+// orphanCleanupLoop har interval par orphaned files hatata hai, ctx cancel hone par ruk jaata hai
+func orphanCleanupLoop(ctx context.Context, t *tracker.Tracker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanupOrphanedFiles(ctx, t)
+		}
+	}
+}
+
+// orphaned files hata kar result log karta hai
+func cleanupOrphanedFiles(ctx context.Context, t *tracker.Tracker) (int64, error) {
+	removed, err := t.CleanupOrphanedFiles(ctx, orphanGracePeriod)
+	if err != nil {
+		logger.Error("CleanupOrphanedFiles failed", "error", err)
+		return 0, err
+	}
+	logger.Info("Cleaned up orphaned files", "removed", removed, "grace", orphanGracePeriod)
+	return removed, nil
+}
+
+// purane DB_HOST/DB_PORT/DB_USER/DB_PASSWORD/DB_NAME variables se DSN banata hai, koi missing ho toh empty string
+func dsnFromEnv() string {
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+	user := os.Getenv("DB_USER")
+	password := os.Getenv("DB_PASSWORD")
+	dbname := os.Getenv("DB_NAME")
+	if user == "" || password == "" || host == "" || port == "" || dbname == "" {
+		return ""
+	}
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", user, password, host, port, dbname)
 }
 
-func handleWebSocketConnection(w http.ResponseWriter, r *http.Request, t *tracker.Tracker, cm *ConnectionManager) {
+func handleWebSocketConnection(ctx context.Context, w http.ResponseWriter, r *http.Request, t *tracker.Tracker, cm *ConnectionManager) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		logger.Error("WebSocket upgrade failed", "error", err)
 		return
 	}
 	defer conn.Close()
 
-	log.Println("New WebSocket connection established")
+	logger.Info("New WebSocket connection established", "remote", r.RemoteAddr)
 
 	var connectedPeerID string // Track which peer this connection belongs to
+	var relayedBytes int64     // is peer ke current relay transfer ke bytes, last chunk par stats mein jaate hai
+
+	// payload ka peer ID tabhi maana jaata hai jab peer HANDSHAKE mein is nonce par us ID ki key se sign kare
+	nonce, err := tracker.NewHandshakeNonce()
+	if err != nil {
+		logger.Error("Failed to generate handshake nonce", "error", err)
+		return
+	}
+	noncePayload, _ := json.Marshal(p2p.NoncePayload{Nonce: nonce})
+	if err := conn.WriteJSON(p2p.Message{Command: "NONCE", Payload: noncePayload}); err != nil {
+		logger.Warn("WebSocket write error", "error", err)
+		return
+	}
 
 	// Handle the connection
 	for {
 		var msg p2p.Message
 		err := conn.ReadJSON(&msg)
 		if err != nil {
-			log.Printf("WebSocket read error: %v", err)
+			logger.Warn("WebSocket read error", "error", err)
 			break
 		}
 
-		log.Printf("Received message: Command=%s", msg.Command)
+		logger.Debug("Received message", "command", msg.Command)
 
 		// Handle file chunks specially - forward them to the requester
 		if msg.Command == "FILE_CHUNK" {
-			handleFileChunk(msg, cm)
+			n, last := handleFileChunk(msg, cm)
+			relayedBytes += n
+			if last && connectedPeerID != "" {
+				if err := t.RecordUpload(ctx, connectedPeerID, relayedBytes); err != nil {
+					logger.Warn("Failed to record relayed upload", "peer", connectedPeerID, "error", err)
+				}
+				relayedBytes = 0
+			}
 			continue
 		}
 
-		response := handleTrackerMessage(msg, t, cm)
-		log.Printf("Sending response: Command=%s", response.Command)
+		response := handleTrackerMessage(ctx, msg, t, cm, connectedPeerID, nonce)
+		response.RequestID = msg.RequestID
+		logger.Debug("Sending response", "command", response.Command)
 
 		// Track the peer ID after successful handshake
 		if msg.Command == "HANDSHAKE" && response.Command == "WELCOME" {
@@ -136,35 +321,36 @@ func handleWebSocketConnection(w http.ResponseWriter, r *http.Request, t *tracke
 			if err := json.Unmarshal(msg.Payload, &payload); err == nil {
 				connectedPeerID = payload.PeerID
 				cm.AddConnection(connectedPeerID, conn)
-				log.Printf("Tracked connection for peer: %s", connectedPeerID)
+				logger.Info("Tracked connection for peer", "peer", connectedPeerID)
 			}
 		}
 
 		if err := conn.WriteJSON(response); err != nil {
-			log.Printf("WebSocket write error: %v", err)
+			logger.Warn("WebSocket write error", "error", err)
 			break
 		}
 	}
 
 	// Mark peer as offline when connection closes
 	if connectedPeerID != "" {
-		log.Printf("Marking peer %s as offline due to connection close", connectedPeerID)
+		logger.Info("Marking peer offline due to connection close", "peer", connectedPeerID)
 		cm.RemoveConnection(connectedPeerID)
-		t.RemovePeer(connectedPeerID)
+		t.RemovePeer(ctx, connectedPeerID)
 	}
 
-	log.Println("WebSocket connection closed")
+	logger.Info("WebSocket connection closed")
 }
 
-// handleFileChunk forwards file chunks to the requesting peer
-func handleFileChunk(msg p2p.Message, cm *ConnectionManager) {
+// handleFileChunk forwards file chunks to the requesting peer.
+// Chunk ke bytes aur yeh last chunk hai ya nahi return karta hai, upload stats ke liye.
+func handleFileChunk(msg p2p.Message, cm *ConnectionManager) (int64, bool) {
 	var chunkPayload p2p.FileTransferPayload
 	if err := json.Unmarshal(msg.Payload, &chunkPayload); err != nil {
-		log.Printf("Error unmarshaling file chunk: %v", err)
-		return
+		logger.Error("Error unmarshaling file chunk", "error", err)
+		return 0, false
 	}
 
-	log.Printf("Received file chunk %d for FileID %s, forwarding to requester", chunkPayload.ChunkIndex, chunkPayload.FileID)
+	logger.Debug("Forwarding file chunk to requester", "index", chunkPayload.ChunkIndex, "file_id", chunkPayload.FileID)
 
 	// Forward chunk to the requester peer
 	// For simplicity, we'll broadcast to all connections and let the client filter
@@ -173,37 +359,51 @@ func handleFileChunk(msg p2p.Message, cm *ConnectionManager) {
 	for peerID, conn := range cm.connections {
 		if peerID != "" { // Don't send back to sender
 			if err := conn.WriteJSON(msg); err != nil {
-				log.Printf("Failed to forward chunk to peer %s: %v", peerID, err)
+				logger.Warn("Failed to forward chunk to peer", "peer", peerID, "error", err)
 			}
 		}
 	}
 	cm.mu.RUnlock()
+	return int64(len(chunkPayload.ChunkData)), chunkPayload.IsLast
 }
 
-func handleTrackerMessage(msg p2p.Message, t *tracker.Tracker, cm *ConnectionManager) p2p.Message {
-	log.Printf("Processing command: %s", msg.Command)
+// connectedPeerID is connection ka handshake se verified peer hai (handshake se pehle khaali).
+// Jo commands kisi peer ki taraf se state badalte hai woh payload ke PeerID par nahi, isi par authorize karte hai.
+// nonce connection khulte hi bheja gaya challenge hai jis par HANDSHAKE ka signature check hota hai.
+func handleTrackerMessage(ctx context.Context, msg p2p.Message, t *tracker.Tracker, cm *ConnectionManager, connectedPeerID string, nonce []byte) p2p.Message {
+	logger.Debug("Processing command", "command", msg.Command)
 	switch msg.Command {
 	case "HANDSHAKE":
 		var payload p2p.HandshakePayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-			log.Printf("Handshake unmarshal error: %v", err)
+			logger.Warn("Handshake unmarshal error", "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid handshake payload"`)}
 		}
 
-		log.Printf("Handshake from peer: %s (ID: %s)", payload.Name, payload.PeerID)
+		logger.Info("Handshake from peer", "name", payload.Name, "peer", payload.PeerID)
+		for _, ip := range []string{payload.IPv4, payload.IPv6} {
+			if err := db.ValidatePeerRecord(payload.PeerID, "", ip); err != nil {
+				logger.Warn("Rejecting handshake with invalid peer record", "peer", payload.PeerID, "error", err)
+				return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid peer ID or IP address"`)}
+			}
+		}
+		if err := tracker.VerifyHandshake(nonce, payload.PeerID, payload.Signature); err != nil {
+			logger.Warn("Rejecting handshake without proof of peer ID", "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid handshake signature"`)}
+		}
 		// Add peer to tracker
-		if err := t.AddPeer(payload.PeerID, payload.Name, "ws-connection"); err != nil {
-			log.Printf("AddPeer error: %v", err)
+		if err := t.AddPeer(ctx, payload.PeerID, payload.Name, payload.IPv4, payload.IPv6, payload.TCPPort); err != nil {
+			logger.Error("AddPeer failed", "peer", payload.PeerID, "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to add peer"`)}
 		}
 
-		log.Printf("Peer %s added successfully", payload.Name)
+		logger.Info("Peer added successfully", "name", payload.Name)
 		return p2p.Message{Command: "WELCOME", Payload: json.RawMessage(`"Connected to tracker"`)}
 
 	case "LIST_PEERS":
-		peers, err := t.GetConnectedPeersDetails(context.Background())
+		peers, err := t.GetConnectedPeersDetails(ctx)
 		if err != nil {
-			log.Printf("GetConnectedPeersDetails error: %v", err)
+			logger.Error("GetConnectedPeersDetails failed", "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get peers"`)}
 		}
 		peersJSON, _ := json.Marshal(peers)
@@ -212,41 +412,156 @@ func handleTrackerMessage(msg p2p.Message, t *tracker.Tracker, cm *ConnectionMan
 	case "ANNOUNCE_FILE":
 		var payload p2p.AnnounceFilePayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-			log.Printf("ANNOUNCE_FILE unmarshal error: %v", err)
+			logger.Warn("ANNOUNCE_FILE unmarshal error", "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid announce payload"`)}
 		}
 
-		log.Printf("Announcing file: %s, hash: %s, size: %d, peer: %s",
-			payload.Filename, payload.FileHash, payload.FileSize, payload.PeerID)
+		logger.Info("Announcing file", "file", payload.Filename, "hash", payload.FileHash,
+			"size", payload.FileSize, "peer", payload.PeerID)
 
 		// Process file announcement
-		fileID, err := t.AnnounceFile(payload.FileHash, payload.Filename, payload.FileSize, payload.PeerID)
+		fileID, err := t.AnnounceFile(ctx, payload.FileHash, payload.InfoHash, payload.Filename, payload.FileSize, payload.PeerID, payload.Signature)
+		if errors.Is(err, tracker.ErrInvalidSignature) {
+			logger.Warn("Rejected announcement with invalid signature", "hash", payload.FileHash, "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid announcement signature"`)}
+		}
 		if err != nil {
-			log.Printf("AnnounceFile error: %v", err)
+			logger.Error("AnnounceFile failed", "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to announce file"`)}
 		}
 
-		log.Printf("File announced successfully with ID: %s", fileID)
+		logger.Info("File announced successfully", "file_id", fileID)
+		if len(payload.Pieces) > 0 && payload.PieceLength > 0 {
+			// pieces index fail ho toh bhi announce valid hai, bas downloader pieces verify nahi kar payega
+			if err := t.SetFilePieces(ctx, payload.FileHash, payload.FileSize, payload.PieceLength, payload.Pieces); err != nil {
+				logger.Warn("SetFilePieces failed", "hash", payload.FileHash, "error", err)
+			}
+		}
 		ackPayload, _ := json.Marshal(p2p.AnnounceAckPayload{FileID: fileID})
 		return p2p.Message{Command: "ACK", Payload: ackPayload}
 
-	case "LIST_FILES":
-		log.Printf("Listing files requested")
-		files := t.ListFiles()
-		log.Printf("Found %d files in database", len(files))
-		for i, file := range files {
-			log.Printf("File %d: ID=%s, Name=%s, Hash=%s", i+1, file.ID, file.Filename, file.FileHash)
+	case "REPORT_PEER_OFFLINE":
+		var payload p2p.GetPeerByIDPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn("REPORT_PEER_OFFLINE unmarshal error", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid peer offline payload"`)}
+		}
+
+		marked, err := t.MarkPeerOffline(ctx, payload.PeerID)
+		if err != nil {
+			logger.Error("MarkPeerOffline failed", "peer", payload.PeerID, "error", err)
+		} else {
+			logger.Info("Peer reported offline", "peer", payload.PeerID, "marked", marked)
+		}
+		ackPayload, _ := json.Marshal(p2p.PeerOfflineAckPayload{PeerID: payload.PeerID, Marked: marked})
+		return p2p.Message{Command: "PEER_OFFLINE_ACK", Payload: ackPayload}
+
+	case "REMOVE_FILE":
+		var payload p2p.RemoveFilePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn("REMOVE_FILE unmarshal error", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid remove file payload"`)}
+		}
+		// peer sirf apna announcement hata sakta hai, kisi aur ka nahi
+		if connectedPeerID == "" || payload.PeerID != connectedPeerID {
+			logger.Warn("Rejected REMOVE_FILE for another peer", "peer", payload.PeerID, "connected_peer", connectedPeerID, "hash", payload.FileHash)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Can only remove your own announcement"`)}
+		}
+
+		remaining, err := t.RemoveFile(ctx, payload.FileHash, connectedPeerID)
+		if err != nil {
+			logger.Warn("RemoveFile failed", "hash", payload.FileHash, "peer", connectedPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to remove file"`)}
+		}
+
+		logger.Info("File announcement removed", "hash", payload.FileHash, "peer", connectedPeerID, "remaining_peers", remaining)
+		ackPayload, _ := json.Marshal(p2p.RemoveFileAckPayload{RemainingPeers: remaining})
+		return p2p.Message{Command: "FILE_REMOVED", Payload: ackPayload}
+
+	case "LIST_FILES_PAGE":
+		var payload p2p.ListFilesPagePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid list files page payload"`)}
+		}
+		if payload.Page < 1 {
+			payload.Page = 1
+		}
+		if payload.PerPage < 1 || payload.PerPage > 100 {
+			payload.PerPage = 20
+		}
+
+		files, total, err := t.ListFilesPage(ctx, payload.Page, payload.PerPage)
+		if err != nil {
+			logger.Error("ListFilesPage failed", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to list files"`)}
+		}
+		if files == nil {
+			files = []db.File{}
+		}
+		pageJSON, _ := json.Marshal(p2p.FilePagePayload{Files: files, Total: total, Page: payload.Page, PerPage: payload.PerPage})
+		return p2p.Message{Command: "FILE_PAGE", Payload: pageJSON}
+
+	case "GET_PIECE_HASH":
+		var payload p2p.GetPieceHashPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid piece hash payload"`)}
+		}
+
+		hash, err := t.GetPieceHash(ctx, payload.FileHash, payload.PieceIndex)
+		if err != nil {
+			logger.Debug("GetPieceHash failed", "hash", payload.FileHash, "piece", payload.PieceIndex, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Piece not found"`)}
+		}
+		hashJSON, _ := json.Marshal(p2p.PieceHashPayload{
+			FileHash:    payload.FileHash,
+			PieceIndex:  payload.PieceIndex,
+			PieceHash:   hash,
+			PieceLength: t.GetPieceLength(ctx, payload.FileHash),
+		})
+		return p2p.Message{Command: "PIECE_HASH", Payload: hashJSON}
+
+	case "GET_MISSING_PIECES":
+		var payload p2p.GetMissingPiecesPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid missing pieces payload"`)}
+		}
+
+		missing, err := t.GetMissingPieces(ctx, payload.FileHash, payload.Have)
+		if err != nil {
+			logger.Error("GetMissingPieces failed", "hash", payload.FileHash, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get missing pieces"`)}
 		}
+		if missing == nil {
+			missing = []int{}
+		}
+		missingJSON, _ := json.Marshal(p2p.MissingPiecesPayload{FileHash: payload.FileHash, Missing: missing})
+		return p2p.Message{Command: "MISSING_PIECES", Payload: missingJSON}
+
+	case "LIST_FILES":
+		files := t.ListFiles(ctx)
+		logger.Debug("Listing files", "count", len(files))
 		filesJSON, _ := json.Marshal(files)
 		return p2p.Message{Command: "FILE_LIST", Payload: filesJSON}
 
+	case "EXPORT_CATALOG":
+		entries, err := t.ListAllFiles(ctx)
+		if err != nil {
+			logger.Error("ListAllFiles failed", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to export catalog"`)}
+		}
+		if entries == nil {
+			entries = []db.CatalogEntry{}
+		}
+		catalogJSON, _ := json.Marshal(entries)
+		return p2p.Message{Command: "CATALOG", Payload: catalogJSON}
+
 	case "GET_PEERS_FOR_FILE":
 		var payload p2p.GetPeersPayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid get peers payload"`)}
 		}
 
-		peers := t.GetPeersForFile(payload.FileID)
+		peers := t.GetPeersForFile(ctx, payload.FileID)
 		peersJSON, _ := json.Marshal(peers)
 		return p2p.Message{Command: "PEER_LIST", Payload: peersJSON}
 
@@ -256,7 +571,7 @@ func handleTrackerMessage(msg p2p.Message, t *tracker.Tracker, cm *ConnectionMan
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid get peer info payload"`)}
 		}
 
-		peer := t.GetPeerInfo(payload.PeerDBID)
+		peer := t.GetPeerInfo(ctx, payload.PeerDBID)
 		if peer == nil {
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Peer not found"`)}
 		}
@@ -264,36 +579,430 @@ func handleTrackerMessage(msg p2p.Message, t *tracker.Tracker, cm *ConnectionMan
 		peerJSON, _ := json.Marshal(peer)
 		return p2p.Message{Command: "PEER_INFO", Payload: peerJSON}
 
+	case "GET_PEER_BY_ID":
+		var payload p2p.GetPeerByIDPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid get peer payload"`)}
+		}
+
+		peer, err := t.GetPeerByID(ctx, payload.PeerID)
+		if err != nil {
+			logger.Warn("GetPeerByID failed", "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Peer not found"`)}
+		}
+
+		peerJSON, _ := json.Marshal(peer)
+		return p2p.Message{Command: "PEER_INFO", Payload: peerJSON}
+
+	case "LIST_PEER_FILES":
+		var payload p2p.GetPeerByIDPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid list peer files payload"`)}
+		}
+
+		files, err := t.GetFilesByPeer(ctx, payload.PeerID)
+		if err != nil {
+			logger.Error("GetFilesByPeer failed", "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to list peer files"`)}
+		}
+
+		filesJSON, _ := json.Marshal(files)
+		return p2p.Message{Command: "PEER_FILE_LIST", Payload: filesJSON}
+
+	case "TAG_FILE":
+		var payload p2p.TagFilePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid tag payload"`)}
+		}
+		// tags sirf woh peer badal sakta hai jo file share kar raha hai
+		if connectedPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Handshake required"`)}
+		}
+		has, err := t.PeerHasFile(ctx, payload.FileHash, connectedPeerID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("PeerHasFile failed", "hash", payload.FileHash, "peer", connectedPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to update tag"`)}
+		}
+		if !has {
+			logger.Warn("Rejected TAG_FILE from a peer not sharing the file", "hash", payload.FileHash, "peer", connectedPeerID, "tag", payload.Tag)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Can only tag files you share"`)}
+		}
+
+		if payload.Remove {
+			err = t.RemoveTag(ctx, payload.FileHash, payload.Tag)
+		} else {
+			err = t.AddTag(ctx, payload.FileHash, payload.Tag)
+		}
+		if err != nil {
+			logger.Warn("Updating file tag failed", "hash", payload.FileHash, "tag", payload.Tag, "remove", payload.Remove, "error", err)
+			errJSON, _ := json.Marshal(err.Error())
+			return p2p.Message{Command: "ERROR", Payload: errJSON}
+		}
+		return p2p.Message{Command: "TAG_UPDATED", Payload: msg.Payload}
+
+	case "SEARCH_TAG":
+		var payload p2p.SearchTagPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid search tag payload"`)}
+		}
+
+		files, err := t.SearchByTag(ctx, payload.Tag)
+		if err != nil {
+			logger.Warn("SearchByTag failed", "tag", payload.Tag, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to search files by tag"`)}
+		}
+		if files == nil {
+			files = []db.File{}
+		}
+		filesJSON, _ := json.Marshal(files)
+		return p2p.Message{Command: "TAGGED_FILES", Payload: filesJSON}
+
+	case "CHECK_PEER_FILE":
+		var payload p2p.CheckPeerFilePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid check peer file payload"`)}
+		}
+		file, err := t.GetFileByID(ctx, payload.FileID)
+		if err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"File not found"`)}
+		}
+		has, err := t.PeerHasFile(ctx, file.FileHash, payload.PeerID)
+		if err != nil {
+			logger.Error("PeerHasFile failed", "hash", file.FileHash, "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to check peer file"`)}
+		}
+		statusJSON, _ := json.Marshal(p2p.PeerFileStatusPayload{FileHash: file.FileHash, Filename: file.Filename, HasFile: has})
+		return p2p.Message{Command: "PEER_FILE_STATUS", Payload: statusJSON}
+
+	case "GET_VERIFIED_PIECES":
+		var payload p2p.PieceCachePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid piece cache payload"`)}
+		}
+		// cache sirf connection ke apne peer ka, payload ka PeerID kisi aur ka ho sakta hai
+		if connectedPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Handshake required"`)}
+		}
+		pieces, err := t.GetVerifiedPieces(ctx, connectedPeerID, payload.FileHash, payload.ModTime)
+		if err != nil {
+			logger.Error("GetVerifiedPieces failed", "hash", payload.FileHash, "peer", connectedPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get verified pieces"`)}
+		}
+		if pieces == nil {
+			pieces = []int{}
+		}
+		piecesJSON, _ := json.Marshal(pieces)
+		return p2p.Message{Command: "VERIFIED_PIECES", Payload: piecesJSON}
+
+	case "MARK_PIECES_VERIFIED":
+		// client response ka wait nahi karta, isliye fail hone par bhi ERROR nahi bhejte (woh kisi aur request ka jawab ban jaata)
+		var payload p2p.PieceCachePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn("Invalid MARK_PIECES_VERIFIED payload", "error", err)
+			return p2p.Message{Command: "PIECES_MARKED"}
+		}
+		// dusre peer ke cache mein pieces verified likhne se woh corrupt pieces dobara hash nahi karta
+		if connectedPeerID == "" {
+			logger.Warn("Ignoring MARK_PIECES_VERIFIED before handshake")
+			return p2p.Message{Command: "PIECES_MARKED"}
+		}
+		if err := t.MarkPiecesVerified(ctx, connectedPeerID, payload.FileHash, payload.ModTime, payload.Pieces); err != nil {
+			logger.Warn("MarkPiecesVerified failed", "hash", payload.FileHash, "peer", connectedPeerID, "error", err)
+		}
+		return p2p.Message{Command: "PIECES_MARKED"}
+
+	case "ADD_GOSSIP_FILES":
+		var payload p2p.GossipFilesPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid gossip files payload"`)}
+		}
+		// files connection ke apne peer ki list mein jaati hai
+		if connectedPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Handshake required"`)}
+		}
+		files := make([]db.GossipFile, 0, min(len(payload.Files), tracker.MaxGossipFilesPerPeer))
+		for _, rec := range payload.Files {
+			if len(files) == tracker.MaxGossipFilesPerPeer {
+				break
+			}
+			if rec.Hash == "" || len(rec.Hash) > 128 || rec.Name == "" || len(rec.Name) > 255 || rec.Size < 0 {
+				continue
+			}
+			files = append(files, db.GossipFile{FileHash: rec.Hash, Filename: rec.Name, FileSize: rec.Size, OriginPeerID: rec.OriginPeerID})
+		}
+		added, err := t.AddGossipFiles(ctx, connectedPeerID, files)
+		if err != nil {
+			logger.Warn("AddGossipFiles failed", "peer", connectedPeerID, "files", len(files), "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to save gossip files"`)}
+		}
+		addedJSON, _ := json.Marshal(p2p.GossipFilesPayload{Added: added})
+		return p2p.Message{Command: "GOSSIP_FILES_ADDED", Payload: addedJSON}
+
+	case "GET_GOSSIP_FILES":
+		if connectedPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Handshake required"`)}
+		}
+		files, err := t.ListGossipFiles(ctx, connectedPeerID)
+		if err != nil {
+			logger.Error("ListGossipFiles failed", "peer", connectedPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to list gossip files"`)}
+		}
+		records := make([]p2p.FileRecord, 0, len(files))
+		for _, f := range files {
+			records = append(records, p2p.FileRecord{Hash: f.FileHash, Name: f.Filename, Size: f.FileSize, OriginPeerID: f.OriginPeerID})
+		}
+		listJSON, _ := json.Marshal(p2p.GossipFilesPayload{Files: records})
+		return p2p.Message{Command: "GOSSIP_FILE_LIST", Payload: listJSON}
+
+	case "REPORT_UPLOAD":
+		// reporter response ka wait nahi karta, isliye fail hone par bhi ERROR nahi bhejte (woh kisi aur request ka jawab ban jaata)
+		var payload p2p.ReportUploadPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn("Invalid REPORT_UPLOAD payload", "error", err)
+			return p2p.Message{Command: "UPLOAD_RECORDED"}
+		}
+		// stats sirf connection ke apne peer ke naam par, payload ka PeerID kisi aur ka ho sakta hai
+		if connectedPeerID == "" {
+			logger.Warn("Ignoring REPORT_UPLOAD before handshake")
+			return p2p.Message{Command: "UPLOAD_RECORDED"}
+		}
+		if err := t.RecordUpload(ctx, connectedPeerID, payload.Bytes); err != nil {
+			logger.Warn("RecordUpload failed", "peer", connectedPeerID, "error", err)
+		}
+		return p2p.Message{Command: "UPLOAD_RECORDED"}
+
+	case "REPORT_TRANSFER":
+		// reporter response ka wait nahi karta, isliye fail hone par bhi ERROR nahi bhejte (woh kisi aur request ka jawab ban jaata)
+		var payload p2p.ReportTransferPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			logger.Warn("Invalid REPORT_TRANSFER payload", "error", err)
+			return p2p.Message{Command: "TRANSFER_RECORDED"}
+		}
+		// history sirf connection ke apne peer ke naam par, payload ka PeerID kisi aur ka ho sakta hai
+		if connectedPeerID == "" {
+			logger.Warn("Ignoring REPORT_TRANSFER before handshake")
+			return p2p.Message{Command: "TRANSFER_RECORDED"}
+		}
+		metadata := map[string]interface{}{"filename": payload.Filename, "bytes": payload.Bytes, "outcome": payload.Outcome, "remote_peer_id": payload.RemotePeerID}
+		if err := t.RecordTransfer(ctx, connectedPeerID, payload.Outcome == "complete", metadata); err != nil {
+			logger.Warn("RecordTransfer failed", "peer", connectedPeerID, "error", err)
+		}
+		return p2p.Message{Command: "TRANSFER_RECORDED"}
+
+	case "GET_PEER_HISTORY":
+		var payload p2p.PeerHistoryPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.PeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid peer history payload"`)}
+		}
+		if payload.Limit < 1 || payload.Limit > 100 {
+			payload.Limit = 20
+		}
+
+		history, err := t.GetPeerHistory(ctx, payload.PeerID, payload.Limit)
+		if err != nil {
+			logger.Error("GetPeerHistory failed", "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get peer history"`)}
+		}
+		if history == nil {
+			history = []db.PeerHistoryRecord{}
+		}
+		historyJSON, _ := json.Marshal(history)
+		return p2p.Message{Command: "PEER_HISTORY", Payload: historyJSON}
+
+	case "TOP_SEEDERS":
+		var payload p2p.TopSeedersPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid top seeders payload"`)}
+		}
+		if payload.Limit < 1 || payload.Limit > 100 {
+			payload.Limit = 10
+		}
+
+		seeders, err := t.GetTopSeeders(ctx, payload.Limit)
+		if err != nil {
+			logger.Error("GetTopSeeders failed", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get top seeders"`)}
+		}
+		if seeders == nil {
+			seeders = []db.SeederRecord{}
+		}
+		seedersJSON, _ := json.Marshal(seeders)
+		return p2p.Message{Command: "TOP_SEEDERS_LIST", Payload: seedersJSON}
+
+	case "NETWORK_STATS":
+		stats, err := t.StatsSnapshot(ctx)
+		if err != nil {
+			logger.Error("StatsSnapshot failed", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get network stats"`)}
+		}
+		statsJSON, _ := json.Marshal(stats)
+		return p2p.Message{Command: "NETWORK_STATS_RESULT", Payload: statsJSON}
+
+	case "AT_RISK_FILES":
+		var payload p2p.AtRiskFilesPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.ThresholdSeconds <= 0 {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid at-risk files payload"`)}
+		}
+
+		files, err := t.GetFilesNeedingReseed(ctx, time.Duration(payload.ThresholdSeconds)*time.Second)
+		if err != nil {
+			logger.Error("GetFilesNeedingReseed failed", "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to get at-risk files"`)}
+		}
+		if files == nil {
+			files = []db.AtRiskFile{}
+		}
+		filesJSON, _ := json.Marshal(files)
+		return p2p.Message{Command: "AT_RISK_LIST", Payload: filesJSON}
+
+	case "BAN_PEER", "UNBAN_PEER":
+		var payload p2p.BanPeerPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.PeerID == "" || payload.BannedPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid ban payload"`)}
+		}
+		// ban list sirf connection ke apne peer ki badli ja sakti hai
+		if connectedPeerID == "" || payload.PeerID != connectedPeerID {
+			logger.Warn("Rejected ban change for another peer", "peer", payload.PeerID, "connected_peer", connectedPeerID)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Can only change your own ban list"`)}
+		}
+
+		if msg.Command == "UNBAN_PEER" {
+			if err := t.UnbanPeer(ctx, connectedPeerID, payload.BannedPeerID); err != nil {
+				logger.Warn("UnbanPeer failed", "peer", connectedPeerID, "banned_peer", payload.BannedPeerID, "error", err)
+				return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Peer is not banned"`)}
+			}
+			logger.Info("Peer unbanned", "peer", connectedPeerID, "banned_peer", payload.BannedPeerID)
+			return p2p.Message{Command: "PEER_UNBANNED"}
+		}
+
+		duration := time.Duration(payload.DurationSeconds) * time.Second
+		if err := t.BanPeer(ctx, connectedPeerID, payload.BannedPeerID, payload.Reason, duration); err != nil {
+			logger.Error("BanPeer failed", "peer", connectedPeerID, "banned_peer", payload.BannedPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to ban peer"`)}
+		}
+		logger.Info("Peer banned", "peer", connectedPeerID, "banned_peer", payload.BannedPeerID, "duration", duration, "reason", payload.Reason)
+		return p2p.Message{Command: "PEER_BANNED"}
+
+	case "LIST_BANS":
+		var payload p2p.GetPeerByIDPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid list bans payload"`)}
+		}
+
+		bans, err := t.ListBans(ctx, payload.PeerID)
+		if err != nil {
+			logger.Error("ListBans failed", "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to list bans"`)}
+		}
+		if bans == nil {
+			bans = []db.PeerBan{}
+		}
+		bansJSON, _ := json.Marshal(bans)
+		return p2p.Message{Command: "BAN_LIST", Payload: bansJSON}
+
+	case "ALLOW_PEER", "DENY_PEER":
+		var payload p2p.AccessControlPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.PeerID == "" || payload.FileHash == "" || payload.TargetPeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid access control payload"`)}
+		}
+		// allow list sirf file ka (signature se verified) seeder badal sakta hai, aur woh isi connection ka peer hona chahiye
+		if connectedPeerID == "" || payload.PeerID != connectedPeerID {
+			logger.Warn("Rejected access control change for another peer", "peer", payload.PeerID, "connected_peer", connectedPeerID, "hash", payload.FileHash)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Only a seeder of this file can change its access list"`)}
+		}
+		if ok, err := t.VerifyFileAnnouncement(ctx, payload.FileHash, connectedPeerID); !ok {
+			logger.Warn("Rejected access control change from non-seeder", "peer", connectedPeerID, "hash", payload.FileHash, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Only a seeder of this file can change its access list"`)}
+		}
+
+		if msg.Command == "DENY_PEER" {
+			if err := t.DenyPeer(ctx, payload.FileHash, payload.TargetPeerID); err != nil {
+				logger.Warn("DenyPeer failed", "hash", payload.FileHash, "target", payload.TargetPeerID, "error", err)
+				return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Peer is not in the allow list"`)}
+			}
+			logger.Info("Peer removed from allow list", "hash", payload.FileHash, "target", payload.TargetPeerID, "by", connectedPeerID)
+			return p2p.Message{Command: "PEER_DENIED"}
+		}
+
+		if err := t.AllowPeer(ctx, payload.FileHash, payload.TargetPeerID); err != nil {
+			logger.Error("AllowPeer failed", "hash", payload.FileHash, "target", payload.TargetPeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to allow peer"`)}
+		}
+		logger.Info("Peer added to allow list", "hash", payload.FileHash, "target", payload.TargetPeerID, "by", connectedPeerID)
+		return p2p.Message{Command: "PEER_ALLOWED"}
+
+	case "CHECK_ACCESS":
+		var payload p2p.CheckAccessPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil || payload.PeerID == "" {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid check access payload"`)}
+		}
+		file, err := t.GetFileByID(ctx, payload.FileID)
+		if err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"File not found"`)}
+		}
+		allowed, err := t.IsAllowed(ctx, file.FileHash, payload.PeerID)
+		if err != nil {
+			logger.Error("IsAllowed failed", "hash", file.FileHash, "peer", payload.PeerID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to check access"`)}
+		}
+		checkedJSON, _ := json.Marshal(p2p.AccessCheckedPayload{Allowed: allowed})
+		return p2p.Message{Command: "ACCESS_CHECKED", Payload: checkedJSON}
+
+	case "GET_FILE_BY_NAME":
+		var payload p2p.GetFileByNamePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid get file payload"`)}
+		}
+
+		file, err := t.GetFileByName(ctx, payload.Filename)
+		if err != nil {
+			logger.Warn("GetFileByName failed", "file", payload.Filename, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"File not found"`)}
+		}
+
+		fileJSON, _ := json.Marshal(file)
+		return p2p.Message{Command: "FILE_INFO", Payload: fileJSON}
+
 	case "REQUEST_FILE":
 		var payload p2p.RequestFilePayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-			log.Printf("REQUEST_FILE unmarshal error: %v", err)
+			logger.Warn("REQUEST_FILE unmarshal error", "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Invalid request file payload"`)}
 		}
 
-		log.Printf("File request: FileID=%s, RequesterPeerID=%s", payload.FileID, payload.RequesterPeerID)
+		logger.Info("File request", "file_id", payload.FileID, "requester", payload.RequesterPeerID)
 
 		// Find peers who have this file
-		peers := t.GetPeersForFile(payload.FileID)
+		peers := t.GetPeersForFile(ctx, payload.FileID)
 		if len(peers) == 0 {
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"No peers found for this file"`)}
 		}
 
-		// For now, request from the first available peer
-		selectedPeer := peers[0]
-
-		// Get peer info to find their peer_id
-		peerInfo, err := t.GetPeerInfoByDBID(context.Background(), selectedPeer.PeerID)
+		file, err := t.GetFileByID(ctx, payload.FileID)
 		if err != nil {
-			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Could not get peer info"`)}
+			logger.Warn("GetFileByID failed", "file_id", payload.FileID, "error", err)
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"File not found"`)}
 		}
 
-		// Check if the peer is connected via WebSocket
-		if !t.IsPeerConnected(peerInfo.PeerID) {
-			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Peer is not online"`)}
+		// pehla connected peer jiska announcement signature valid hai, baaki peers skip hote hai
+		var peerInfo *db.Peer
+		for _, candidate := range peers {
+			info, err := t.GetPeerInfoByDBID(ctx, candidate.PeerID)
+			if err != nil || !t.IsPeerConnected(info.PeerID) {
+				continue
+			}
+			if ok, err := t.VerifyFileAnnouncement(ctx, file.FileHash, info.PeerID); !ok {
+				logger.Warn("Skipping peer with unverified announcement", "peer", info.PeerID, "hash", file.FileHash, "error", err)
+				continue
+			}
+			peerInfo = info
+			break
+		}
+		if peerInfo == nil {
+			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"No online peer with a valid announcement for this file"`)}
 		}
 
-		log.Printf("Requesting file %s from peer %s for requester %s", payload.FileID, peerInfo.PeerID, payload.RequesterPeerID)
+		logger.Info("Requesting file from peer", "file_id", payload.FileID, "peer", peerInfo.PeerID, "requester", payload.RequesterPeerID)
 
 		// Send file request to the peer who has the file
 		fileRequestMsg := p2p.Message{
@@ -303,11 +1012,27 @@ func handleTrackerMessage(msg p2p.Message, t *tracker.Tracker, cm *ConnectionMan
 
 		// Send request to the file owner
 		if err := cm.SendToConnection(peerInfo.PeerID, fileRequestMsg); err != nil {
-			log.Printf("Failed to send file request to peer %s: %v", peerInfo.PeerID, err)
+			logger.Error("Failed to send file request to peer", "peer", peerInfo.PeerID, "error", err)
 			return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to contact peer"`)}
 		}
 
-		return p2p.Message{Command: "FILE_REQUEST_INITIATED", Payload: json.RawMessage(`"File request sent to peer"`)}
+		// downloader ko file hash aur piece size bhejte hai taaki woh pieces verify kar sake
+		initiated := p2p.FileRequestInitiatedPayload{
+			FileID:      payload.FileID,
+			FileHash:    file.FileHash,
+			PieceLength: t.GetPieceLength(ctx, file.FileHash),
+		}
+		if peerInfo.TCPPort > 0 {
+			initiated.SeederIP = peerInfo.IPAddress
+			if initiated.SeederIP == "" {
+				initiated.SeederIP = peerInfo.IPv6
+			}
+			if initiated.SeederIP != "" {
+				initiated.SeederTCPPort = peerInfo.TCPPort
+			}
+		}
+		initiatedJSON, _ := json.Marshal(initiated)
+		return p2p.Message{Command: "FILE_REQUEST_INITIATED", Payload: initiatedJSON}
 
 	default:
 		return p2p.Message{Command: "ERROR", Payload: json.RawMessage(`"Unknown command"`)}
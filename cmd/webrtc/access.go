@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// `allow <filename> <peer_id>` aur `deny <filename> <peer_id>` commands: apni share ki hui file ki allow list badalte hai.
+// Allow list khaali ho toh file sab ke liye khuli hai; pehla allow ke baad sirf listed peers use download kar sakte hai.
+// List tracker par file hash ke hisab se store hoti hai, isliye file ke saare seeders ise follow karte hai.
+func (c *Client) setFileAccess(out io.Writer, filename, idStr string, allow bool) error {
+	command := "ALLOW_PEER"
+	if !allow {
+		command = "DENY_PEER"
+	}
+	filename = filepath.Base(filename)
+	id, err := peer.Decode(idStr)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+
+	resp, err := c.trackerRequest("GET_FILE_BY_NAME", p2p.GetFileByNamePayload{Filename: filename})
+	if err != nil {
+		return err
+	}
+	var record db.File
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		return fmt.Errorf("failed to parse file info: %w", err)
+	}
+
+	if _, err := c.trackerRequest(command, p2p.AccessControlPayload{
+		PeerID:       c.host.ID().String(),
+		FileHash:     record.FileHash,
+		TargetPeerID: id.String(),
+	}); err != nil {
+		return err
+	}
+	if allow {
+		fmt.Fprintf(out, "%s can now download %s.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), filename)
+	} else {
+		fmt.Fprintf(out, "Removed %s from the allow list of %s.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), filename)
+	}
+	return nil
+}
+
+// tracker se poochta hai ki transport ka remote peer fileID download kar sakta hai ya nahi.
+// Yeh access control hai, isliye peer ID pata na ho ya tracker jawab na de toh request mana hoti hai.
+func (c *Client) requesterAllowed(p FileTransport, fileID uuid.UUID) bool {
+	return c.peerAllowed(transportPeerID(p), fileID)
+}
+
+// requesterAllowed jaisa hi, par seedha peer ID se (TCP fallback ke liye jahan transport nahi hota)
+func (c *Client) peerAllowed(id peer.ID, fileID uuid.UUID) bool {
+	if id == "" {
+		return false
+	}
+	resp, err := c.trackerRequest("CHECK_ACCESS", p2p.CheckAccessPayload{FileID: fileID, PeerID: id.String()})
+	if err != nil {
+		logger.Warn("Could not check file access, refusing request", "peer", id, "file_id", fileID, "error", err)
+		return false
+	}
+	// response RequestID se isi request ka hai; phir bhi ACCESS_CHECKED ke alawa kuch aaye toh allow nahi maante
+	if resp.Command != "ACCESS_CHECKED" {
+		logger.Warn("Unexpected access check response, refusing request", "peer", id, "command", resp.Command)
+		return false
+	}
+	var checked p2p.AccessCheckedPayload
+	if err := json.Unmarshal(resp.Payload, &checked); err != nil {
+		logger.Warn("Failed to parse access check, refusing request", "peer", id, "error", err)
+		return false
+	}
+	return checked.Allowed
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pion/webrtc/v3"
+
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+const (
+	// naya connection bante hi itni der baad apni files announce karte hai, taaki bitfields aur signaling pehle ho jaye
+	announceDelay = 5 * time.Second
+	// ek peer ko isse jaldi dobara announce gossip nahi bheja jaata
+	announceThrottle = time.Second
+)
+
+// `announce` command: tracker par apni announced files laakar GOSSIP_FILES mein saare connected peers ko bhejta hai,
+// taaki restart ke baad peers ko pata chale ki hum wapas aa gaye hai
+func (c *Client) announceLocalFiles(out io.Writer) error {
+	msg, err := c.localCatalogMessage()
+	if err != nil {
+		return err
+	}
+	if len(msg.Files) == 0 {
+		fmt.Fprintln(out, "You are not seeding any files.")
+		return nil
+	}
+
+	c.peersMux.RLock()
+	peers := make(map[peer.ID]*torrentiumWebRTC.WebRTCPeer, len(c.webRTCPeers))
+	for id, p := range c.webRTCPeers {
+		peers[id] = p
+	}
+	c.peersMux.RUnlock()
+
+	notified := 0
+	for id, p := range peers {
+		if c.sendAnnounce(id, p, msg) {
+			notified++
+		}
+	}
+	fmt.Fprintf(out, "Announced %d file(s) to %d peer(s).\n", len(msg.Files), notified)
+	return nil
+}
+
+// connection Connected hone ke announceDelay baad us peer ko apni files announce karta hai
+func (c *Client) announceFilesOnConnect(id peer.ID, p *torrentiumWebRTC.WebRTCPeer) {
+	p.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		if s != webrtc.PeerConnectionStateConnected {
+			return
+		}
+		time.AfterFunc(announceDelay, func() {
+			msg, err := c.localCatalogMessage()
+			if err != nil {
+				logger.Warn("Could not load local files for announce", "peer", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), "error", err)
+				return
+			}
+			if len(msg.Files) > 0 {
+				c.sendAnnounce(id, p, msg)
+			}
+		})
+	})
+}
+
+// tracker se is node ki announced files laakar GOSSIP_FILES message banata hai
+func (c *Client) localCatalogMessage() (p2p.ChannelMessage, error) {
+	resp, err := c.trackerRequest("LIST_PEER_FILES", p2p.GetPeerByIDPayload{PeerID: c.host.ID().String()})
+	if err != nil {
+		return p2p.ChannelMessage{}, err
+	}
+	var files []db.File
+	if err := json.Unmarshal(resp.Payload, &files); err != nil {
+		return p2p.ChannelMessage{}, fmt.Errorf("failed to parse file list: %w", err)
+	}
+
+	records := make([]p2p.FileRecord, 0, len(files))
+	for _, f := range files {
+		records = append(records, p2p.FileRecord{Hash: f.FileHash, Name: f.Filename, Size: f.FileSize, OriginPeerID: c.host.ID().String(), FileID: f.ID.String()})
+	}
+	return p2p.ChannelMessage{Command: "GOSSIP_FILES", Files: records}, nil
+}
+
+// ek peer ko announce gossip bhejta hai. Pichla announce announceThrottle se pehle gaya ho toh utna ruk kar bhejta hai.
+func (c *Client) sendAnnounce(id peer.ID, p *torrentiumWebRTC.WebRTCPeer, msg p2p.ChannelMessage) bool {
+	if !p.IsConnected() {
+		return false
+	}
+
+	c.peersMux.Lock()
+	next := c.announcedAt[id].Add(announceThrottle)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	c.announcedAt[id] = next
+	c.peersMux.Unlock()
+	time.Sleep(time.Until(next))
+
+	if err := p.Send(msg); err != nil {
+		logger.Warn("Error sending file announce", "peer", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), "error", err)
+		return false
+	}
+	logger.Info("Announced local files to peer", "peer", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), "files", len(msg.Files))
+	return true
+}
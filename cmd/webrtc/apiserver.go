@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"torrentium/api"
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// API server (/metrics, /files, /status, /stats, /stats/top-seeders, /health/at-risk-files, /readyz, /events) start karta hai, port 0 ho toh kuch nahi karta
+func (c *Client) startAPIServer(port int) {
+	if port <= 0 {
+		return
+	}
+	srv := api.NewServer(fmt.Sprintf(":%d", port))
+	srv.Handle("/files", api.FilesHandler(c.fetchFilePage, c.fetchFilesByTag))
+	srv.Handle("/status", api.StatusHandler(c.collectStatus))
+	srv.Handle("/stats", api.NetworkStatsHandler(c.fetchNetworkStats))
+	srv.Handle("/stats/top-seeders", api.TopSeedersHandler(c.fetchTopSeeders))
+	srv.Handle("/health/at-risk-files", api.AtRiskFilesHandler(c.fetchAtRiskFiles))
+	srv.Handle("/readyz", api.ReadyzHandler(c.collectReadiness))
+	srv.Handle("/events", api.EventsHandler(c.events))
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			logger.Error("API server stopped", "error", err)
+		}
+	}()
+}
+
+// tracker se files ka ek page laata hai, API ke /files endpoint ke liye
+func (c *Client) fetchFilePage(ctx context.Context, page, perPage int) ([]db.File, int, error) {
+	resp, err := c.trackerRequestContext(ctx, "LIST_FILES_PAGE", p2p.ListFilesPagePayload{Page: page, PerPage: perPage})
+	if err != nil {
+		return nil, 0, err
+	}
+	var result p2p.FilePagePayload
+	if err := json.Unmarshal(resp.Payload, &result); err != nil {
+		return nil, 0, err
+	}
+	return result.Files, result.Total, nil
+}
+
+// /readyz ke liye libp2p host aur WebRTC peers ki halat; host kisi address par listen na kar raha ho toh node ready nahi
+func (c *Client) collectReadiness() (api.ReadinessReport, error) {
+	var report api.ReadinessReport
+	report.Host.PeerID = c.host.ID().String()
+	for _, addr := range c.host.Network().ListenAddresses() {
+		report.Host.ListenAddrs = append(report.Host.ListenAddrs, addr.String())
+	}
+	report.Host.ConnectedPeers = len(c.host.Network().Peers())
+
+	report.WebRTC.States = make(map[string]int)
+	c.peersMux.RLock()
+	report.WebRTC.Peers = len(c.webRTCPeers)
+	for _, p := range c.webRTCPeers {
+		report.WebRTC.States[p.State().String()]++
+	}
+	report.WebRTC.Reconnecting = len(c.reconnecting)
+	c.peersMux.RUnlock()
+
+	if len(report.Host.ListenAddrs) == 0 {
+		return report, errors.New("libp2p host is not listening on any address")
+	}
+	return report, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"torrentium/api"
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// tracker se woh files laata hai jinke saare seeders threshold se zyada der se offline hai
+func (c *Client) fetchAtRiskFiles(ctx context.Context, threshold time.Duration) ([]db.AtRiskFile, error) {
+	resp, err := c.trackerRequestContext(ctx, "AT_RISK_FILES", p2p.AtRiskFilesPayload{ThresholdSeconds: int64(threshold / time.Second)})
+	if err != nil {
+		return nil, err
+	}
+	var files []db.AtRiskFile
+	if err := json.Unmarshal(resp.Payload, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// `at-risk [threshold]` command: woh files print karta hai jo jaldi unavailable ho sakti hai (default threshold 24h)
+func (c *Client) showAtRiskFiles(out io.Writer, args []string) error {
+	threshold := api.DefaultAtRiskThreshold
+	if len(args) > 1 {
+		return fmt.Errorf("usage: at-risk [threshold]")
+	}
+	if len(args) == 1 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d < time.Second {
+			return fmt.Errorf("invalid threshold %q (use e.g. 24h or 90m)", args[0])
+		}
+		threshold = d
+	}
+
+	files, err := c.fetchAtRiskFiles(context.Background(), threshold)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(out, "No files whose seeders have all been offline for more than %s.\n", threshold)
+		return nil
+	}
+	fmt.Fprintf(out, "\nFiles at risk (all seeders offline for more than %s):\n", threshold)
+	for _, f := range files {
+		fmt.Fprintf(out, "  %s (%s)\n      Hash: %s | Seeders: %d | Last seen: %s\n",
+			f.Filename, torrentiumWebRTC.FormatFileSizeIEC(f.FileSize), f.FileHash, f.Seeders, f.LastSeen.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// `ban <peer_id> <duration> [reason]` command: peer ko ban list mein daalta hai aur connected ho toh disconnect kar deta hai.
+// Duration Go format mein hai (jaise 30m, 24h); "permanent" ya "0" se ban kabhi expire nahi hota.
+// Ban tracker par store hota hai, isliye restart ke baad bhi rehta hai.
+func (c *Client) banPeer(out io.Writer, args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: ban <peer_id> <duration|permanent> [reason]")
+	}
+	id, err := peer.Decode(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if id == c.host.ID() {
+		return errors.New("cannot ban yourself")
+	}
+	var duration time.Duration
+	if args[1] != "permanent" && args[1] != "0" {
+		if duration, err = time.ParseDuration(args[1]); err != nil || duration <= 0 {
+			return fmt.Errorf("invalid duration %q (use e.g. 30m, 24h or permanent)", args[1])
+		}
+	}
+	reason := strings.Join(args[2:], " ")
+
+	if _, err := c.trackerRequest("BAN_PEER", p2p.BanPeerPayload{
+		PeerID:          c.host.ID().String(),
+		BannedPeerID:    id.String(),
+		Reason:          reason,
+		DurationSeconds: int64(duration / time.Second),
+	}); err != nil {
+		return err
+	}
+
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	c.peerManager.Ban(id.String(), until)
+
+	// ban ke baad purana connection bhi band karte hai; connected na ho toh disconnectPeer sirf error deta hai
+	if err := c.disconnectPeer(out, id.String()); err != nil {
+		logger.Debug("Banned peer was not connected", "peer", id, "error", err)
+	}
+	if duration > 0 {
+		fmt.Fprintf(out, "Banned %s for %s.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), duration)
+	} else {
+		fmt.Fprintf(out, "Banned %s permanently.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	}
+	return nil
+}
+
+// `unban <peer_id>` command: peer ko ban list se hata deta hai
+func (c *Client) unbanPeer(out io.Writer, idStr string) error {
+	id, err := peer.Decode(idStr)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if _, err := c.trackerRequest("UNBAN_PEER", p2p.BanPeerPayload{
+		PeerID:       c.host.ID().String(),
+		BannedPeerID: id.String(),
+	}); err != nil {
+		return err
+	}
+	c.peerManager.Unban(id.String())
+	fmt.Fprintf(out, "Unbanned %s.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	return nil
+}
+
+// startup par tracker se apni ban list laata hai taaki signaling handler banned peers ko turant reject kare
+func (c *Client) loadBans() {
+	resp, err := c.trackerRequest("LIST_BANS", p2p.GetPeerByIDPayload{PeerID: c.host.ID().String()})
+	if err != nil {
+		logger.Warn("Could not load peer bans from tracker", "error", err)
+		return
+	}
+	var bans []db.PeerBan
+	if err := json.Unmarshal(resp.Payload, &bans); err != nil {
+		logger.Warn("Could not parse peer bans", "error", err)
+		return
+	}
+	for _, b := range bans {
+		var until time.Time
+		if b.ExpiresAt != nil {
+			until = *b.ExpiresAt
+		}
+		c.peerManager.Ban(b.BannedPeerID, until)
+	}
+	if len(bans) > 0 {
+		logger.Info("Loaded peer bans", "count", len(bans))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pion/webrtc/v3"
+
+	"torrentium/p2p"
+	"torrentium/torrentfile"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// naye WebRTC peer ko apni har local file ka bitfield bhejta hai. Hum seeder hai, isliye saare pieces set hote hai.
+// Messages outbox mein ruk jaate hai jab tak data channel open nahi hota.
+func (c *Client) sendBitfields(p *torrentiumWebRTC.WebRTCPeer) {
+	c.filesMux.RLock()
+	records := make([]p2p.FileRecord, 0, len(c.localFiles))
+	for _, rec := range c.localFiles {
+		records = append(records, rec)
+	}
+	c.filesMux.RUnlock()
+
+	for _, rec := range records {
+		have := make([]bool, torrentfile.PieceCount(rec.Size, c.pieceLength))
+		for i := range have {
+			have[i] = true
+		}
+		if err := p.SendBitfield(rec.Hash, have); err != nil {
+			logger.Warn("Failed to send bitfield", "file", rec.Name, "error", err)
+		}
+	}
+}
+
+// har baar connection Connected state mein aane par (pehli baar, ICE recovery ya reconnect ke baad) peer ko
+// apne bitfields bhejta hai, taaki remote ka download manager woh pieces na maange jo humare paas nahi hai.
+// Initiator ka peer yahan aane se pehle hi connected hota hai, isliye us case mein turant bhej dete hai.
+func (c *Client) announcePiecesOnConnect(p *torrentiumWebRTC.WebRTCPeer) {
+	p.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		if s == webrtc.PeerConnectionStateConnected {
+			go c.sendBitfields(p)
+		}
+	})
+	if p.IsConnected() {
+		go c.sendBitfields(p)
+	}
+}
+
+// kisi transport ka remote libp2p peer ID, pata na ho toh empty
+func transportPeerID(p FileTransport) peer.ID {
+	switch t := p.(type) {
+	case *torrentiumWebRTC.WebRTCPeer:
+		return t.RemotePeerID()
+	case interface{ RemotePeerID() string }:
+		id, _ := peer.Decode(t.RemotePeerID())
+		return id
+	}
+	return ""
+}
+
+// peer ka bheja bitfield store karta hai
+func (c *Client) handleBitfield(p FileTransport, msg torrentiumWebRTC.BitfieldCommand) {
+	id := transportPeerID(p)
+	if id == "" || msg.FileHash == "" {
+		logger.Warn("Ignoring bitfield from unknown peer or without file hash")
+		return
+	}
+	have := torrentiumWebRTC.DecodeBitfield(msg.Bitfield, msg.Pieces)
+
+	c.peersMux.Lock()
+	if c.bitfields[id] == nil {
+		c.bitfields[id] = make(map[string][]bool)
+	}
+	c.bitfields[id][msg.FileHash] = have
+	c.peersMux.Unlock()
+	logger.Debug("Received bitfield", "peer", id, "hash", msg.FileHash, "pieces", len(have))
+}
+
+// woh connected peers jinke bitfield ke hisab se file ka yeh piece unke paas hai
+func (c *Client) peersWithPiece(fileHash string, idx int) []peer.ID {
+	c.peersMux.RLock()
+	defer c.peersMux.RUnlock()
+
+	var ids []peer.ID
+	for id, files := range c.bitfields {
+		if have := files[fileHash]; idx < len(have) && have[idx] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// peer chala jaaye toh uske bitfields bhi bhool jaate hai
+func (c *Client) forgetBitfields(id peer.ID) {
+	c.peersMux.Lock()
+	delete(c.bitfields, id)
+	c.peersMux.Unlock()
+}
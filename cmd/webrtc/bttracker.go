@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+
+	"torrentium/tracker"
+)
+
+// add ki hui file ko --bt-tracker-url wale BitTorrent (BEP 3) tracker par bhi announce karta hai, taaki woh
+// existing BitTorrent infrastructure mein dikhe. Hum poori file seed kar rahe hai, isliye left 0 hai. Port TCP fallback
+// server ka jaata hai; woh band ho toh announce nahi hota kyunki swarm ko dene ke liye koi port nahi hai.
+func (c *Client) announceToBTTracker(filePath string, infoHash [20]byte) {
+	name := filepath.Base(filePath)
+	if c.tcpPort <= 0 {
+		logger.Warn("Skipping BitTorrent tracker announce, TCP server is disabled", "file", name)
+		return
+	}
+	peers, err := tracker.Announce(context.Background(), c.btTrackerURL, infoHash, c.btPeerID, c.tcpPort, tracker.AnnounceStarted, 0, 0, 0)
+	if err != nil {
+		logger.Warn("BitTorrent tracker announce failed", "file", name, "tracker", c.btTrackerURL, "error", err)
+		return
+	}
+	logger.Info("Announced to BitTorrent tracker", "file", name, "tracker", c.btTrackerURL, "swarm_peers", len(peers))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// startUpload p par ek upload ke liye cancellable context deta hai, taaki receiver ka CANCEL use rok sake.
+// Upload khatam hone par done call karna zaroori hai.
+func (c *Client) startUpload(p FileTransport) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.downloadsMux.Lock()
+	c.uploads[p] = cancel
+	c.downloadsMux.Unlock()
+	return ctx, func() {
+		c.downloadsMux.Lock()
+		delete(c.uploads, p)
+		c.downloadsMux.Unlock()
+		cancel()
+	}
+}
+
+// receiver ne CANCEL bheja: p par chal raha upload rok dete hai
+func (c *Client) handleCancel(p FileTransport, cmd torrentiumWebRTC.CancelCommand) {
+	c.downloadsMux.RLock()
+	cancel, ok := c.uploads[p]
+	c.downloadsMux.RUnlock()
+	if !ok {
+		logger.Debug("Peer cancelled a transfer that is not running", "file", cmd.Filename, "reason", cmd.Reason)
+		return
+	}
+	logger.Warn("Peer cancelled the transfer", "file", cmd.Filename, "reason", cmd.Reason)
+	cancel()
+}
+
+// FILE_START ke size ko --max-file-size limit aur receive directory ki free space se check karta hai.
+// File limit se badi ho ya jagah na ho toh sender ko CANCEL bhej kar false return karta hai, aur caller file nahi banata.
+func (c *Client) acceptIncomingSize(p FileTransport, filename string, size int64) bool {
+	err := p.CheckReceiveSpace(size)
+	if err == nil {
+		return true
+	}
+	reason, ok := cancelReason(err)
+	if !ok {
+		// free space pata nahi chala, transfer ko rokne ki wajah nahi hai
+		logger.Warn("Could not check free disk space", "name", filename, "error", err)
+		return true
+	}
+	logger.Warn("Rejected incoming file", "name", filename, "size", size, "error", err)
+	if err := p.SendCancel(filename, reason); err != nil {
+		logger.Warn("Failed to send cancel", "name", filename, "error", err)
+	}
+	return false
+}
+
+// size limit ya disk space ke error ka CANCEL reason; koi aur error ho toh false
+func cancelReason(err error) (string, bool) {
+	switch {
+	case errors.Is(err, torrentiumWebRTC.ErrFileTooLarge):
+		return torrentiumWebRTC.CancelReasonFileTooLarge, true
+	case errors.Is(err, torrentiumWebRTC.ErrInsufficientSpace):
+		return torrentiumWebRTC.CancelReasonInsufficientSpace, true
+	}
+	return "", false
+}
+
+// unknownSizeReceive size 0 wale transfer (directory archive ya stream) ka writer hai. FILE_START par size check nahi ho
+// sakta, isliye neeche ka writer likhe gaye bytes ginta hai; limit ya free space paar hote hi uska error aate hi
+// sender ko ek baar CANCEL jaata hai aur transfer "error" event ke saath khatam hota hai.
+type unknownSizeReceive struct {
+	io.WriteCloser
+	c         *Client
+	p         FileTransport
+	name      string
+	cancelled bool
+}
+
+func (w *unknownSizeReceive) Write(b []byte) (int, error) {
+	n, err := w.WriteCloser.Write(b)
+	if err == nil || w.cancelled {
+		return n, err
+	}
+	if reason, ok := cancelReason(err); ok {
+		w.cancelled = true
+		logger.Warn("Stopped incoming transfer", "peer", transportPeerID(w.p), "name", w.name, "error", err)
+		if err := w.p.SendCancel(w.name, reason); err != nil {
+			logger.Warn("Failed to send cancel", "name", w.name, "error", err)
+		}
+		w.c.emitTransferEvent(torrentiumWebRTC.TransferEvent{Type: torrentiumWebRTC.TransferFailed, PeerID: transportPeerID(w.p).String(), Filename: w.name, Cause: err})
+	}
+	return n, err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// kitni der mein ek baar peerstore se expired peers hatane hai
+const peerstoreGCInterval = time.Hour
+
+// connectPeer user ke diye multiaddr par libp2p connection banata hai aur phir WebRTC connection setup karta hai.
+// User ne khud is peer se connect kiya hai, isliye iske addresses permanently store hote hai.
+func (c *Client) connectPeer(out io.Writer, addr string) error {
+	maddr, err := ma.NewMultiaddr(addr)
+	if err != nil {
+		return fmt.Errorf("invalid multiaddr: %w", err)
+	}
+	info, err := peer.AddrInfoFromP2pAddr(maddr)
+	if err != nil {
+		return fmt.Errorf("multiaddr must include /p2p/<peer_id>: %w", err)
+	}
+
+	c.host.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+	return c.dialPeer(out, *info)
+}
+
+// dialPeer libp2p connection banata hai aur phir WebRTC signaling karta hai; WebRTC fail ho toh QUIC fallback try hota hai
+func (c *Client) dialPeer(out io.Writer, info peer.AddrInfo) error {
+	if c.passive {
+		return errPassiveMode
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.host.Connect(ctx, info); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", info.ID, err)
+	}
+	logger.Info("Connected to peer over libp2p, starting WebRTC signaling", "peer", info.ID)
+
+	webRTCPeer, err := c.initiateWebRTCConnection(info.ID)
+	if err != nil {
+		// ICE negotiate nahi hua (jaise strict NAT), toh seedha QUIC par try karte hai
+		logger.Warn("WebRTC connection failed, falling back to QUIC", "peer", info.ID, "error", err)
+		if _, qerr := c.dialQuic(info.ID); qerr != nil {
+			return fmt.Errorf("WebRTC connection to %s failed (%v) and QUIC fallback failed: %w", info.ID, err, qerr)
+		}
+		fmt.Fprintf(out, "✅ QUIC connection established with %s (WebRTC fallback)\n", p2p.FormatPeerID(info.ID, p2p.ShortPeerIDLength))
+		return nil
+	}
+	c.addWebRTCPeer(info.ID, webRTCPeer)
+	fmt.Fprintf(out, "✅ WebRTC connection established with %s\n", p2p.FormatPeerID(info.ID, p2p.ShortPeerIDLength))
+	return nil
+}
+
+// config ke bootstrap peers se ek-ek karke connect karta hai, fail hone wale peers sirf log hote hai
+func (c *Client) connectBootstrapPeers(addrs []string) {
+	for _, addr := range addrs {
+		if err := c.connectPeer(os.Stdout, addr); err != nil {
+			logger.Warn("Failed to connect to bootstrap peer", "addr", addr, "error", err)
+		}
+	}
+}
+
+// tracker se mile peers ke addresses ko default (finite) TTL ke saath peerstore mein add karta hai
+func (c *Client) rememberPeerAddrs(peers []db.Peer) {
+	for _, p := range peers {
+		id, err := peer.Decode(p.PeerID)
+		if err != nil || id == c.host.ID() {
+			continue
+		}
+		var addrs []ma.Multiaddr
+		for _, s := range p.Multiaddrs {
+			if a, err := ma.NewMultiaddr(s); err == nil {
+				addrs = append(addrs, a)
+			}
+		}
+		if len(addrs) > 0 {
+			c.host.Peerstore().AddAddrs(id, addrs, peerstore.AddressTTL)
+		}
+	}
+}
+
+// peerstoreGCLoop har interval par un peers ko peerstore se hata deta hai jinke saare addresses expire ho chuke hai
+// aur jinse abhi koi connection nahi hai, taaki long-running nodes ki memory na bhare.
+func (c *Client) peerstoreGCLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		removed := 0
+		ps := c.host.Peerstore()
+		for _, id := range ps.Peers() {
+			if id == c.host.ID() || len(ps.Addrs(id)) > 0 || len(c.host.Network().ConnsToPeer(id)) > 0 {
+				continue
+			}
+			if _, ok := c.getWebRTCPeer(id); ok {
+				continue
+			}
+			if _, ok := c.getQuicPeer(id); ok {
+				continue
+			}
+			ps.RemovePeer(id)
+			removed++
+		}
+		if removed > 0 {
+			logger.Info("Peerstore GC removed expired peers", "count", removed)
+		}
+	}
+}
+
+// transfer_hmac on ho toh peer ke saath libp2p identity keys ka ECDH secret nikal kar WebRTC chunks par HMAC lagata hai
+func (c *Client) enableTransferHMAC(id peer.ID, p *torrentiumWebRTC.WebRTCPeer) {
+	if !c.transferHMAC {
+		return
+	}
+	secret, err := p2p.SharedSecret(c.host.Peerstore().PrivKey(c.host.ID()), id)
+	if err != nil {
+		logger.Warn("Could not derive transfer HMAC key, chunks will not be authenticated", "peer", id, "error", err)
+		return
+	}
+	p.EnableHMAC(secret)
+}
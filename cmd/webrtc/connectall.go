@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// `connect-all [--filter-tag <tag>]` command: tracker ke saare online peers se ek-ek karke connect karta hai.
+// Tag diya ho toh sirf woh peers jo us tag wali koi file share kar rahe hai. Connection limit poori hote hi ruk jaata hai.
+func (c *Client) connectAllPeers(out io.Writer, args []string) error {
+	var tag string
+	switch {
+	case len(args) == 0:
+	case len(args) == 2 && args[0] == "--filter-tag":
+		tag = args[1]
+	default:
+		return errors.New("usage: connect-all [--filter-tag <tag>]")
+	}
+
+	peers, err := c.fetchPeers()
+	if err != nil {
+		return err
+	}
+	var allowed map[uuid.UUID]bool
+	if tag != "" {
+		if allowed, err = c.peersSharingTag(tag); err != nil {
+			return err
+		}
+	}
+
+	var connected, failed, skipped int
+	for _, p := range peers {
+		if p.PeerID == c.host.ID().String() || (allowed != nil && !allowed[p.ID]) {
+			continue
+		}
+		id, err := peer.Decode(p.PeerID)
+		if err != nil {
+			logger.Warn("Tracker returned an invalid peer ID", "peer", p.PeerID, "error", err)
+			failed++
+			continue
+		}
+		if c.peerManager.IsBanned(id.String()) {
+			continue
+		}
+		if _, ok := c.getWebRTCPeer(id); ok {
+			skipped++
+			continue
+		}
+		if _, ok := c.getQuicPeer(id); ok {
+			skipped++
+			continue
+		}
+		if c.peerManager.Full() {
+			fmt.Fprintf(out, "Connection limit (%d) reached, not connecting to more peers.\n", c.peerManager.MaxConnections)
+			break
+		}
+
+		info := peer.AddrInfo{ID: id}
+		for _, s := range p.Multiaddrs {
+			if addr, err := ma.NewMultiaddr(s); err == nil {
+				info.Addrs = append(info.Addrs, addr)
+			}
+		}
+		if len(info.Addrs) == 0 {
+			logger.Warn("Peer has no usable addresses", "peer", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+			failed++
+			continue
+		}
+		if err := c.dialPeer(out, info); err != nil {
+			logger.Warn("Failed to connect to peer", "peer", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), "error", err)
+			failed++
+			continue
+		}
+		connected++
+	}
+
+	fmt.Fprintf(out, "Connected: %d, Failed: %d, Skipped (already connected): %d\n", connected, failed, skipped)
+	return nil
+}
+
+// tag wali files share karne wale online peers ke DB IDs laata hai
+func (c *Client) peersSharingTag(tag string) (map[uuid.UUID]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	files, err := c.fetchFilesByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[uuid.UUID]bool)
+	for _, f := range files {
+		resp, err := c.trackerRequest("GET_PEERS_FOR_FILE", p2p.GetPeersPayload{FileID: f.ID})
+		if err != nil {
+			return nil, err
+		}
+		var peerFiles []db.PeerFile
+		if err := json.Unmarshal(resp.Payload, &peerFiles); err != nil {
+			return nil, fmt.Errorf("failed to parse peer list: %w", err)
+		}
+		for _, pf := range peerFiles {
+			allowed[pf.PeerID] = true
+		}
+	}
+	return allowed, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"torrentium/control"
+)
+
+// control socket par in commands ki ijazat nahi hai; exit se koi bhi script poora client band kar deta
+var controlBlockedCommands = map[string]bool{"exit": true}
+
+// yeh commands client ke terminal par confirmation poochte hai, jo control socket ke caller ko dikhta hi nahi
+var controlInteractiveCommands = map[string]bool{"remove": true, "verify": true}
+
+// path par control socket kholta hai; `torrentium <command> [args...]` isi se chalte client ko commands bhejta hai
+func (c *Client) startControlSocket(path string) error {
+	l, err := control.Listen(path)
+	if err != nil {
+		return err
+	}
+	logger.Info("Control socket listening", "path", path)
+	go control.Serve(l, c.runControlCommand)
+	return nil
+}
+
+// control socket se aaya command stdin loop wale runCommand se chalta hai; uska output isi command ke buffer mein
+// likha jaata hai aur response mein jaata hai
+func (c *Client) runControlCommand(cmd string, args []string, input io.Reader) (string, error) {
+	if controlBlockedCommands[cmd] {
+		return "", errors.New("command not available over the control socket")
+	}
+	if controlInteractiveCommands[cmd] {
+		return "", fmt.Errorf("%s asks for confirmation; run it at the client's prompt", cmd)
+	}
+	var out bytes.Buffer
+	c.commandMu.Lock()
+	defer c.commandMu.Unlock()
+	_, err := c.runCommand(&out, false, cmd, args, input)
+	return out.String(), err
+}
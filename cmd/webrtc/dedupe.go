@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/google/uuid"
+
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// tracker se poochta hai ki transport ke remote peer ne yeh file pehle se announce ki hai ya nahi.
+// Tracker se jawab na mile toh false, taaki transfer normal tarike se ho jaye.
+func (c *Client) requesterHasFile(p FileTransport, fileID uuid.UUID) (p2p.PeerFileStatusPayload, bool) {
+	var status p2p.PeerFileStatusPayload
+	id := transportPeerID(p)
+	if id == "" {
+		return status, false
+	}
+	resp, err := c.trackerRequest("CHECK_PEER_FILE", p2p.CheckPeerFilePayload{FileID: fileID, PeerID: id.String()})
+	if err != nil {
+		logger.Debug("Could not check requester's files", "peer", id, "error", err)
+		return status, false
+	}
+	if err := json.Unmarshal(resp.Payload, &status); err != nil {
+		logger.Debug("Failed to parse peer file status", "error", err)
+		return status, false
+	}
+	return status, status.HasFile
+}
+
+// uploader ne FILE_EXISTS bheja: local copy (receive directory ya downloaded_<name>) dhundh kar download skip karte hai
+func (c *Client) handleFileExists(cmd torrentiumWebRTC.FileExistsCommand) {
+	for _, path := range localCopyPaths(cmd.Filename) {
+		if _, err := os.Stat(path); err == nil {
+			logger.Info("Peer skipped the transfer, file already exists locally", "file", cmd.Filename, "path", path, "hash", cmd.FileHash)
+			return
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Could not check local copy", "path", path, "error", err)
+		}
+	}
+	logger.Warn("Peer says we already have this file, but no local copy was found", "file", cmd.Filename, "hash", cmd.FileHash)
+}
+
+// woh paths jahan pehle download hui file ho sakti hai
+func localCopyPaths(filename string) []string {
+	var paths []string
+	if path, err := torrentiumWebRTC.SafeReceivePath(torrentiumWebRTC.DefaultReceiveDir, filename); err == nil {
+		paths = append(paths, path)
+	}
+	if path, err := torrentiumWebRTC.SafeReceivePath(".", "downloaded_"+filename); err == nil {
+		paths = append(paths, path)
+	}
+	return paths
+}
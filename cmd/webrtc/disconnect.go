@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/p2p"
+)
+
+// disconnectPeer ek peer ka WebRTC (ya QUIC fallback) connection band karta hai, use peers map se hatata hai
+// aur libp2p connection bhi close kar deta hai. Tracker ko bhi report karte hai, jo peer ko tabhi offline
+// mark karta hai jab woh tracker se bhi connected nahi hai.
+func (c *Client) disconnectPeer(out io.Writer, idStr string) error {
+	id, err := peer.Decode(idStr)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+
+	c.peersMux.Lock()
+	webrtcPeer, hasWebRTC := c.webRTCPeers[id]
+	quicPeer, hasQuic := c.quicPeers[id]
+	delete(c.quicPeers, id)
+	delete(c.announcedAt, id)
+	c.peersMux.Unlock()
+	c.forgetBitfields(id)
+
+	hasLibp2p := len(c.host.Network().ConnsToPeer(id)) > 0
+	if !hasWebRTC && !hasQuic && !hasLibp2p {
+		return fmt.Errorf("not connected to %s", id)
+	}
+
+	if hasWebRTC {
+		// map se hatana aur count ghatana closeWebRTCPeer karta hai, Close se pehle
+		c.closeWebRTCPeer(id, webrtcPeer)
+	}
+	if hasQuic {
+		if err := quicPeer.Close(); err != nil {
+			logger.Warn("Error closing QUIC connection", "peer", id, "error", err)
+		}
+		c.stopForwardingEvents(quicPeer)
+	}
+	// libp2p streams (signaling wala bhi) connection ke saath band ho jaate hai
+	if err := c.host.Network().ClosePeer(id); err != nil {
+		logger.Warn("Error closing libp2p connection", "peer", id, "error", err)
+	}
+
+	// response PEER_OFFLINE_ACK background handler mein sirf log hota hai
+	payload, _ := json.Marshal(p2p.GetPeerByIDPayload{PeerID: id.String()})
+	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "REPORT_PEER_OFFLINE", Payload: payload}); err != nil {
+		logger.Warn("Failed to report peer offline", "peer", id, "error", err)
+	}
+
+	fmt.Fprintf(out, "Disconnected from %s\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	return nil
+}
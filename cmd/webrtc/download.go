@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// DownloadManager har download ke liye track karta hai ki kaunse pieces tracker ke file_pieces index
+// se verify ho chuke hai aur kaunse abhi bhi chahiye
+type DownloadManager struct {
+	request   func(command string, payload interface{}) (p2p.Message, error) // tracker request/response
+	sources   func(fileHash string, piece int) []peer.ID                     // peers ke bitfields se piece ke sources
+	mu        sync.Mutex
+	downloads map[uuid.UUID]*pieceDownload
+}
+
+// ek download ki piece-level state
+type pieceDownload struct {
+	fileHash    string
+	pieceLength int64
+	path        string
+	verified    map[int]bool
+}
+
+func NewDownloadManager(request func(command string, payload interface{}) (p2p.Message, error), sources func(fileHash string, piece int) []peer.ID) *DownloadManager {
+	return &DownloadManager{
+		request:   request,
+		sources:   sources,
+		downloads: make(map[uuid.UUID]*pieceDownload),
+	}
+}
+
+// Track ek naye download ko register karta hai, path woh file hai jismein data likha ja raha hai
+func (m *DownloadManager) Track(fileID uuid.UUID, fileHash string, pieceLength int64, path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.downloads[fileID] = &pieceDownload{
+		fileHash:    fileHash,
+		pieceLength: pieceLength,
+		path:        path,
+		verified:    make(map[int]bool),
+	}
+}
+
+// Lookup tracked download ka file hash aur path return karta hai, ok false ho toh download track nahi ho raha
+func (m *DownloadManager) Lookup(fileID uuid.UUID) (fileHash, path string, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.downloads[fileID]
+	if !ok {
+		return "", "", false
+	}
+	return d.fileHash, d.path, true
+}
+
+// Untrack download ki tracking bina piece verification ke khatam kar deta hai
+func (m *DownloadManager) Untrack(fileID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.downloads, fileID)
+}
+
+// SelectPeer missing piece ke liye woh peer chunta hai jiske bitfield mein yeh piece hai,
+// taaki aise peer se request na jaye jiske paas piece hi nahi hai
+func (m *DownloadManager) SelectPeer(fileHash string, piece int) (peer.ID, bool) {
+	ids := m.sources(fileHash, piece)
+	if len(ids) == 0 {
+		return "", false
+	}
+	return ids[0], true
+}
+
+// Verify poori downloaded file ko piece-by-piece tracker ke hashes se check karta hai aur woh pieces return karta hai
+// jo abhi bhi fetch karne hai (missing ya hash mismatch). Iske baad download ki tracking khatam ho jaati hai.
+func (m *DownloadManager) Verify(fileID uuid.UUID) ([]int, error) {
+	m.mu.Lock()
+	d, ok := m.downloads[fileID]
+	delete(m.downloads, fileID)
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("download %s is not tracked", fileID)
+	}
+	if d.pieceLength <= 0 {
+		return nil, fmt.Errorf("tracker has no piece index for %s", d.fileHash)
+	}
+
+	f, err := os.Open(d.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, d.pieceLength)
+	for idx := 0; ; idx++ {
+		n, err := io.ReadFull(f, buf)
+		if n == 0 {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		want, err := m.pieceHash(d.fileHash, idx)
+		if err != nil {
+			// tracker ke paas itne pieces nahi hai, baaki data verify nahi ho sakta
+			logger.Debug("No piece hash from tracker", "hash", d.fileHash, "piece", idx, "error", err)
+			break
+		}
+		if sum := sha1.Sum(buf[:n]); bytes.Equal(sum[:], want) {
+			d.verified[idx] = true
+		} else {
+			logger.Warn("Piece hash mismatch", "hash", d.fileHash, "piece", idx)
+		}
+	}
+
+	have := make([]int, 0, len(d.verified))
+	for idx := range d.verified {
+		have = append(have, idx)
+	}
+	return m.missingPieces(d.fileHash, have)
+}
+
+// tracker se ek piece ka expected SHA-1 hash laata hai
+func (m *DownloadManager) pieceHash(fileHash string, idx int) ([]byte, error) {
+	info, err := m.pieceInfo(fileHash, idx)
+	if err != nil {
+		return nil, err
+	}
+	return info.PieceHash, nil
+}
+
+// tracker se ek piece ka hash aur file ka piece size laata hai
+func (m *DownloadManager) pieceInfo(fileHash string, idx int) (p2p.PieceHashPayload, error) {
+	resp, err := m.request("GET_PIECE_HASH", p2p.GetPieceHashPayload{FileHash: fileHash, PieceIndex: idx})
+	if err != nil {
+		return p2p.PieceHashPayload{}, err
+	}
+	var payload p2p.PieceHashPayload
+	if err := json.Unmarshal(resp.Payload, &payload); err != nil {
+		return p2p.PieceHashPayload{}, err
+	}
+	if payload.PieceIndex != idx {
+		return p2p.PieceHashPayload{}, fmt.Errorf("tracker answered for piece %d, expected %d", payload.PieceIndex, idx)
+	}
+	return payload, nil
+}
+
+// tracker se woh pieces poochta hai jo have mein nahi hai
+func (m *DownloadManager) missingPieces(fileHash string, have []int) ([]int, error) {
+	resp, err := m.request("GET_MISSING_PIECES", p2p.GetMissingPiecesPayload{FileHash: fileHash, Have: have})
+	if err != nil {
+		return nil, err
+	}
+	var payload p2p.MissingPiecesPayload
+	if err := json.Unmarshal(resp.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Missing, nil
+}
+
+// download complete hone par poori file ka SHA-256 tracker ke record se milata hai.
+// Hash match na ho toh pieces verify karke kharab pieces log karte hai aur file delete kar dete hai.
+func (c *Client) verifyDownload(fileID uuid.UUID, filename string) {
+	fileHash, path, ok := c.downloads.Lookup(fileID)
+	if !ok {
+		return
+	}
+	match, err := fileMatchesHash(path, fileHash)
+	if err != nil {
+		logger.Warn("Could not hash downloaded file", "file", filename, "error", err)
+		c.downloads.Untrack(fileID)
+		return
+	}
+	if match {
+		c.downloads.Untrack(fileID)
+		logger.Info("Download integrity verified", "file", filename, "hash", fileHash)
+		return
+	}
+	defer c.discardCorruptDownload(path, filename)
+
+	missing, err := c.downloads.Verify(fileID)
+	if err != nil {
+		logger.Warn("Could not verify downloaded pieces", "file", filename, "error", err)
+		return
+	}
+	if len(missing) > 0 {
+		logger.Warn("Download is incomplete or corrupt", "file", filename, "missing_pieces", missing)
+		// bitfields se batate hai ki missing pieces kaunse connected peers se mil sakte hai
+		for _, piece := range missing {
+			if id, ok := c.downloads.SelectPeer(fileHash, piece); ok {
+				logger.Info("Missing piece is available from a connected peer", "file", filename, "piece", piece, "peer", id)
+			} else {
+				logger.Info("No connected peer has missing piece", "file", filename, "piece", piece)
+			}
+		}
+		return
+	}
+	logger.Info("All pieces verified", "file", filename)
+}
+
+// WebRTC/QUIC se receive hui file ka SHA-256 tracker ke record (filename se) ke hash se milata hai.
+// Hash match na ho toh kharab pieces p se NACK karke dobara maangte hai; repair fail ho tabhi file delete hoti hai.
+// Yeh FILE_END ke baad apni goroutine mein chalta hai, command loop ke saath; tracker ka jawab RequestID se isi ko milta hai.
+func (c *Client) verifyReceivedFile(p FileTransport, path, name string) {
+	resp, err := c.trackerRequest("GET_FILE_BY_NAME", p2p.GetFileByNamePayload{Filename: filepath.Base(name)})
+	if err != nil {
+		logger.Warn("Could not look up expected hash for received file", "file", name, "error", err)
+		return
+	}
+	// kisi aur command ka record le kar sahi file delete na ho jaye
+	if resp.Command != "FILE_INFO" {
+		logger.Warn("Unexpected tracker response while verifying received file", "file", name, "command", resp.Command)
+		return
+	}
+	var record db.File
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		logger.Warn("Failed to parse file info", "file", name, "error", err)
+		return
+	}
+
+	match, err := fileMatchesHash(path, record.FileHash)
+	if err != nil {
+		logger.Warn("Could not hash received file", "file", name, "error", err)
+		return
+	}
+	if !match {
+		if err := c.repairReceivedFile(p, path, name, record); err != nil {
+			logger.Warn("Could not repair received file", "file", name, "error", err)
+			c.discardCorruptDownload(path, name)
+			return
+		}
+		logger.Info("Download integrity verified after retransmitting pieces", "file", name, "hash", record.FileHash)
+		return
+	}
+	logger.Info("Download integrity verified", "file", name, "hash", record.FileHash)
+}
+
+// file ka SHA-256 expected hash (hex) se match karta hai ya nahi
+func fileMatchesHash(path, expected string) (bool, error) {
+	actual, _, err := calculateFileHash(path)
+	if err != nil {
+		return false, err
+	}
+	return actual == expected, nil
+}
+
+// hash mismatch wali file delete karke "corrupt" event bhejta hai
+func (c *Client) discardCorruptDownload(path, filename string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("Failed to delete corrupt download", "path", path, "error", err)
+	}
+	logger.Error("Downloaded file does not match the tracker's hash, deleted it", "file", filename, "path", path)
+	c.emitTransferEvent(torrentiumWebRTC.TransferEvent{Type: torrentiumWebRTC.TransferCorrupt, Filename: filename})
+}
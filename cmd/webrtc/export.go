@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"torrentium/db"
+)
+
+// export file ki ek row; JSON keys aur CSV headers same hai
+type catalogRecord struct {
+	Hash      string `json:"hash"`
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	PeerID    string `json:"peer_id"`
+	MimeType  string `json:"mime_type"`
+	CreatedAt string `json:"created_at"`
+}
+
+var catalogCSVHeader = []string{"hash", "filename", "size", "peer_id", "mime_type", "created_at"}
+
+func newCatalogRecord(e db.CatalogEntry) catalogRecord {
+	rec := catalogRecord{
+		Hash:      e.FileHash,
+		Filename:  e.Filename,
+		Size:      e.FileSize,
+		PeerID:    e.PeerID,
+		CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if e.ContentType != nil {
+		rec.MimeType = *e.ContentType
+	}
+	return rec
+}
+
+// `export [json|csv] <outfile>` command: tracker ka poora file catalog outfile mein likhta hai
+func (c *Client) exportCatalog(out io.Writer, format, outPath string) error {
+	var write func(io.Writer, []db.CatalogEntry) error
+	switch format {
+	case "json":
+		write = writeCatalogJSON
+	case "csv":
+		write = writeCatalogCSV
+	default:
+		return fmt.Errorf("unknown export format %q (use json or csv)", format)
+	}
+
+	resp, err := c.trackerRequest("EXPORT_CATALOG", nil)
+	if err != nil {
+		return err
+	}
+	var entries []db.CatalogEntry
+	if err := json.Unmarshal(resp.Payload, &entries); err != nil {
+		return fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	if err := writeFileAtomic(outPath, func(w io.Writer) error { return write(w, entries) }); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Fprintf(out, "Exported %d catalog entries to %s.\n", len(entries), outPath)
+	return nil
+}
+
+// writeFileAtomic write ka output pehle path ki directory mein temp file mein likhta hai aur phir rename karta hai,
+// taaki beech mein fail hone par aadhi likhi file na bache
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return err
+	}
+	// CreateTemp 0600 deta hai, export baaki files jaisi readable honi chahiye
+	if err := tmp.Chmod(0o644); err != nil {
+		logger.Warn("Could not set export file permissions", "error", err)
+	}
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// JSON array ek-ek record encode karke likhta hai, poora array memory mein marshal nahi hota
+func writeCatalogJSON(w io.Writer, entries []db.CatalogEntry) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i, e := range entries {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(newCatalogRecord(e)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+func writeCatalogCSV(w io.Writer, entries []db.CatalogEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		rec := newCatalogRecord(e)
+		if err := cw.Write([]string{rec.Hash, rec.Filename, strconv.FormatInt(rec.Size, 10), rec.PeerID, rec.MimeType, rec.CreatedAt}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// kitne time baad apni file list connected peers ko gossip karni hai
+const gossipInterval = 60 * time.Second
+
+// gossipLoop har interval par local file list saare connected peers ko bhejta hai
+func (c *Client) gossipLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.broadcastFileList()
+	}
+}
+
+// knownFileRecords apni files aur gossip se mili files, dono ko ek list mein return karta hai.
+// Gossip files tracker par save hoti hai; tracker na mile toh sirf apni files jaati hai.
+func (c *Client) knownFileRecords() []p2p.FileRecord {
+	gossip, err := c.fetchGossipFiles()
+	if err != nil {
+		logger.Debug("Gossip files unavailable, sending only local files", "error", err)
+	}
+
+	c.filesMux.RLock()
+	defer c.filesMux.RUnlock()
+	records := make([]p2p.FileRecord, 0, len(c.localFiles)+len(gossip))
+	for _, rec := range c.localFiles {
+		records = append(records, rec)
+	}
+	for _, rec := range gossip {
+		if _, ok := c.localFiles[rec.Hash]; ok {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// tracker se woh files laata hai jo is node ko gossip se pata chali thi
+func (c *Client) fetchGossipFiles() ([]p2p.FileRecord, error) {
+	resp, err := c.trackerRequest("GET_GOSSIP_FILES", nil)
+	if err != nil {
+		return nil, err
+	}
+	var payload p2p.GossipFilesPayload
+	if err := json.Unmarshal(resp.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse gossip files: %w", err)
+	}
+	return payload.Files, nil
+}
+
+// broadcastFileList GOSSIP_FILES message saare connected WebRTC peers ko bhejta hai
+func (c *Client) broadcastFileList() {
+	records := c.knownFileRecords()
+	if len(records) == 0 {
+		return
+	}
+	msg := p2p.ChannelMessage{Command: "GOSSIP_FILES", Files: records}
+
+	c.peersMux.RLock()
+	peers := make([]*torrentiumWebRTC.WebRTCPeer, 0, len(c.webRTCPeers))
+	for _, p := range c.webRTCPeers {
+		peers = append(peers, p)
+	}
+	c.peersMux.RUnlock()
+
+	for _, p := range peers {
+		if !p.IsConnected() {
+			continue
+		}
+		if err := p.Send(msg); err != nil {
+			logger.Warn("Error sending gossip file list", "error", err)
+		}
+	}
+}
+
+// mergeGossipFiles dusre peer se aayi file list mein se apni files chhod kar baaki tracker par is node ke naam save
+// karta hai. Tracker pehle se saved hashes dobara nahi likhta aur har peer ki list tracker.MaxGossipFilesPerPeer tak rakhta hai.
+func (c *Client) mergeGossipFiles(records []p2p.FileRecord) {
+	c.filesMux.RLock()
+	unknown := make([]p2p.FileRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Hash == "" || rec.OriginPeerID == c.host.ID().String() {
+			continue
+		}
+		if _, ok := c.localFiles[rec.Hash]; ok {
+			continue
+		}
+		unknown = append(unknown, rec)
+	}
+	c.filesMux.RUnlock()
+	if len(unknown) == 0 {
+		return
+	}
+
+	resp, err := c.trackerRequest("ADD_GOSSIP_FILES", p2p.GossipFilesPayload{Files: unknown})
+	if err != nil {
+		logger.Warn("Failed to save gossip files", "count", len(unknown), "error", err)
+		return
+	}
+	var ack p2p.GossipFilesPayload
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		logger.Warn("Invalid gossip files response", "error", err)
+		return
+	}
+	if ack.Added > 0 {
+		logger.Info("Learned about new files via gossip", "count", ack.Added)
+	}
+}
+
+// printGossipFiles gossip se mili un files ko print karta hai jo tracker ki list mein nahi hai
+func (c *Client) printGossipFiles(out io.Writer, known map[string]bool) {
+	gossip, err := c.fetchGossipFiles()
+	if err != nil {
+		logger.Warn("Could not load files known via gossip", "error", err)
+		return
+	}
+
+	printed := false
+	for _, rec := range gossip {
+		if known[rec.Hash] {
+			continue
+		}
+		if !printed {
+			fmt.Fprintln(out, "\nFiles known via gossip:")
+			printed = true
+		}
+		fmt.Fprintln(out, "--------------------")
+		fmt.Fprintf(out, "  Hash: %s\n  Name: %s\n  Size: %s\n  Origin: %s\n", rec.Hash, rec.Name, torrentiumWebRTC.FormatFileSizeIEC(rec.Size), p2p.FormatPeerIDString(rec.OriginPeerID, p2p.ShortPeerIDLength))
+	}
+	if printed {
+		fmt.Fprintln(out, "--------------------")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// `history <peer_id> [limit]` command: tracker ke audit log se peer ke sabse naye events dikhata hai
+// (connect/disconnect, file add/remove, transfers). Default 20 events, zyada se zyada 100.
+func (c *Client) showPeerHistory(out io.Writer, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: history <peer_id> [limit]")
+	}
+	id, err := peer.Decode(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+	limit := 20
+	if len(args) == 2 {
+		if limit, err = strconv.Atoi(args[1]); err != nil || limit < 1 || limit > 100 {
+			return errors.New("limit must be between 1 and 100")
+		}
+	}
+
+	resp, err := c.trackerRequest("GET_PEER_HISTORY", p2p.PeerHistoryPayload{PeerID: id.String(), Limit: limit})
+	if err != nil {
+		return err
+	}
+	var history []db.PeerHistoryRecord
+	if err := json.Unmarshal(resp.Payload, &history); err != nil {
+		return fmt.Errorf("failed to parse peer history: %w", err)
+	}
+	if len(history) == 0 {
+		fmt.Fprintf(out, "No recorded events for %s.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nHistory of %s (newest first):\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	for _, h := range history {
+		details := ""
+		if len(h.Metadata) > 0 && string(h.Metadata) != "null" {
+			details = " " + string(h.Metadata)
+		}
+		fmt.Fprintf(out, "  %s  %-18s%s\n", h.OccurredAt.Format("2006-01-02 15:04:05"), h.EventType, details)
+	}
+	return nil
+}
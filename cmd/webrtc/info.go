@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"torrentium/torrentfile"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// info command kitne piece hashes print karta hai
+const infoPieceHashes = 8
+
+// `info <file>` command: file ki .torrent metadata print karta hai. .torrent file na ho toh sirf file ka hash aur size.
+func (c *Client) showTorrentInfo(out io.Writer, filename string) error {
+	meta, err := torrentfile.ParseTorrentFile(filename + ".torrent")
+	if errors.Is(err, fs.ErrNotExist) {
+		fileHash, fileSize, err := calculateFileHash(filename)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "No torrent file for %s, showing basic metadata:\n", filename)
+		fmt.Fprintf(out, "  Hash: %s\n", fileHash)
+		fmt.Fprintf(out, "  Size: %s (%d bytes)\n", torrentiumWebRTC.FormatFileSizeIEC(fileSize), fileSize)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse torrent file: %w", err)
+	}
+
+	infoHash := "-"
+	if ih, err := torrentfile.InfoHash(meta); err == nil {
+		infoHash = hex.EncodeToString(ih[:])
+	}
+	// purani .torrent files mein info dictionary nahi hoti, unke pieces 0 dikhte hai
+	hashes := meta.PieceHashes()
+
+	fmt.Fprintf(out, "Torrent info for %s:\n", filename)
+	// .torrent files mein announce URL nahi hota, tracker client ke config se aata hai
+	fmt.Fprintf(out, "  Announce:     -\n")
+	fmt.Fprintf(out, "  Piece length: %s (%d bytes)\n", torrentiumWebRTC.FormatFileSizeIEC(meta.Info.PieceLength), meta.Info.PieceLength)
+	fmt.Fprintf(out, "  Total size:   %s (%d bytes)\n", torrentiumWebRTC.FormatFileSizeIEC(meta.Length), meta.Length)
+	fmt.Fprintf(out, "  Pieces:       %d\n", len(hashes))
+	fmt.Fprintf(out, "  Files:        %s\n", meta.Filename)
+	fmt.Fprintf(out, "  Created:      %s\n", time.Unix(meta.CreatedAt, 0).Format(time.RFC3339))
+	fmt.Fprintf(out, "  Hash:         %s\n", meta.Hash)
+	fmt.Fprintf(out, "  Info hash:    %s\n", infoHash)
+	fmt.Fprintln(out, "  Piece hashes:")
+	for i, h := range hashes {
+		if i == infoPieceHashes {
+			fmt.Fprintf(out, "    ... %d more\n", len(hashes)-infoPieceHashes)
+			break
+		}
+		fmt.Fprintf(out, "    %4d  %s\n", i, hex.EncodeToString(h))
+	}
+	return nil
+}
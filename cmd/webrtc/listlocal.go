@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"torrentium/db"
+	"torrentium/p2p"
+	"torrentium/torrentfile"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// listLocalFiles sirf woh files dikhata hai jo is node ne announce ki hai, taaki user audit kar sake ki kya share ho raha hai
+func (c *Client) listLocalFiles(out io.Writer) error {
+	resp, err := c.trackerRequest("LIST_PEER_FILES", p2p.GetPeerByIDPayload{PeerID: c.host.ID().String()})
+	if err != nil {
+		return err
+	}
+	var files []db.File
+	if err := json.Unmarshal(resp.Payload, &files); err != nil {
+		return fmt.Errorf("failed to parse file list: %w", err)
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(out, "You are not seeding any files.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILENAME\tSIZE\tHASH\tPIECES\tADDED")
+	for _, f := range files {
+		added := f.CreatedAt
+		if f.AnnouncedAt != nil {
+			added = *f.AnnouncedAt
+		}
+		pieces := torrentfile.PieceCount(f.FileSize, c.pieceLength)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", f.Filename, torrentiumWebRTC.FormatFileSizeIEC(f.FileSize),
+			hashPrefix(f.FileHash), pieces, added.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+// display ke liye hash ke pehle 12 characters
+func hashPrefix(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -2,16 +2,19 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -30,89 +33,308 @@ import (
 
 	"github.com/pion/webrtc/v3"
 
+	"torrentium/api"
+	"torrentium/blockstore"
+	"torrentium/config"
+	"torrentium/control"
 	"torrentium/db"
+	"torrentium/logging"
+	"torrentium/nat"
 	"torrentium/p2p"
+	"torrentium/pidfile"
+	"torrentium/progress"
+	"torrentium/quictransport"
 	"torrentium/torrentfile"
+	"torrentium/tracker"
 	"torrentium/webRTC"
 	torrentiumWebRTC "torrentium/webRTC"
 )
 
+// client binary ka logger, level aur format --log-level/--log-format flags se set hote hai
+var logger = logging.For("client")
+
 // Client struct client application ki state aur components ko hold karta hai.
 type Client struct {
 	host            host.Host
-	trackerConn     *websocket.Conn // WebSocket connection to tracker
+	trackerConn     *trackerSocket // WebSocket connection to tracker
 	peerName        string
+	stdin           *bufio.Scanner    // commands aur confirmations dono isi se padhe jaate hai
+	ipv4, ipv6      string            // local IP addresses jo tracker ko handshake mein bheje jaate hai
+	pieceLength     int64             // .torrent files ka piece size, config se aata hai
+	transferHMAC    bool              // WebRTC chunks par peers ke shared secret wali HMAC lagani hai ya nahi
+	passive         bool              // --passive: sirf aaye offers ka jawab dena, khud offer ya download nahi
+	maxFileSize     int64             // --max-file-size: isse badi aane wali files reject hoti hai, 0 ho toh koi limit nahi
+	tcpPort         int               // TCP fallback server ka port, tracker ko handshake mein jaata hai; 0 ho toh band
+	btTrackerURL    string            // --bt-tracker-url: add ki hui files yahan bhi announce hoti hai, empty ho toh band
+	btPeerID        [20]byte          // BitTorrent tracker ko bheja jaane wala peer ID
+	blocks          *blockstore.Store // downloads ke blocks yahan dedupe hokar store hote hai, nil ho toh disabled
+	webRTCConfig    torrentiumWebRTC.Config
 	webRTCPeers     map[peer.ID]*torrentiumWebRTC.WebRTCPeer
+	quicPeers       map[peer.ID]*quictransport.QuicTransfer // WebRTC fail hone par QUIC fallback connections
+	reconnecting    map[peer.ID]bool                        // jin peers ke liye reconnect loop chal raha hai
+	bitfields       map[peer.ID]map[string][]bool           // peer -> file hash -> kaunse pieces uske paas hai
+	peerManager     *p2p.PeerManager                        // webRTCPeers ki ginti, signaling handler connection limit ke liye use karta hai
+	announcedAt     map[peer.ID]time.Time                   // peer ko pichla announce gossip kab gaya (ya jaayega), throttle ke liye
+	catalogWaits    map[peer.ID]chan []p2p.FileRecord       // sync ke LIST_FILES jinka FILE_CATALOG abhi aana hai
 	peersMux        sync.RWMutex
 	sharingFiles    map[uuid.UUID]string
-	activeDownloads map[uuid.UUID]*os.File // Track active file downloads
+	localFiles      map[string]p2p.FileRecord // hash -> apni announced files ki info (gossip ke liye)
+	filesMux        sync.RWMutex
+	activeDownloads map[uuid.UUID]*os.File               // Track active file downloads
+	downloads       *DownloadManager                     // downloads ke verified/missing pieces
+	downloadedBytes map[uuid.UUID]int64                  // har active download ke ab tak likhe gaye bytes
+	receivingPaths  map[FileTransport]string             // WebRTC/QUIC par aa rahi file ka path, integrity check ke liye
+	uploads         map[FileTransport]context.CancelFunc // chal rahe uploads, receiver ke CANCEL par ruk jaate hai
+	repairs         map[FileTransport]*pieceRepair       // received files jinke kharab pieces NACK se dobara aa rahe hai
+	eventsDone      map[FileTransport]chan struct{}      // har transport ki forwardEvents goroutine, close hone par ruk jaati hai (peersMux se protected)
 	downloadsMux    sync.RWMutex
-
-	// Channels for handling responses
-	fileListChan        chan []db.File
-	peerListChan        chan []db.Peer
-	requestResponseChan chan p2p.Message
+	transferEvents  chan torrentiumWebRTC.TransferEvent // progress bar ke liye saare transfer events
+	events          *api.EventHub                       // API ke /events WebSocket feed ke subscribers
+	commandMu       sync.Mutex                          // stdin aur control socket ke commands ek-ek karke chalte hai
+
+	// tracker requests jinka response abhi aana hai, Message.RequestID se match hote hai
+	pendingRequests map[uint64]chan p2p.Message
+	nextRequestID   uint64
+	pendingMu       sync.Mutex
 }
 
 // entry point for the webRTC peer code
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: Could not load .env file: %v", err)
-		log.Println("Proceeding with system environment variables...")
+	configPath := flag.String("config", "", "path to config file (default ~/.torrentium/config.yaml)")
+	unordered := flag.Bool("unordered", false, "use an unordered data channel; chunks are sequenced and written in order (the other peer must support it too)")
+	logLevel := flag.String("log-level", "info", "log level: debug, info, warn or error")
+	logFormat := flag.String("log-format", "text", "log format: text or json")
+	enableMDNS := flag.Bool("mdns", true, "discover peers on the local network via mDNS (disable on public networks)")
+	passive := flag.Bool("passive", false, "only seed: answer incoming connections but never send offers or download")
+	maxFileSize := flag.Int64("max-file-size", 0, "reject incoming files larger than this many bytes (0 = no limit)")
+	peerName := flag.String("name", "", "peer name sent to the tracker (prompted on stdin if empty)")
+	controlSocket := flag.String("control-socket", "", "accept commands from the torrentium CLI on this Unix socket (empty = disabled)")
+	pidFile := flag.String("pid-file", "", "write the process ID to this file and refuse to start if it names a running process")
+	tcpPort := flag.Int("tcp-port", -1, "serve shared files over direct TCP on this port when WebRTC is blocked (0 = any free port, -1 = disabled)")
+	btTrackerURL := flag.String("bt-tracker-url", "", "also announce added files to this BitTorrent HTTP tracker (BEP 3), e.g. http://tracker.example.com/announce")
+	upnp := flag.Bool("upnp", false, "map the libp2p TCP and QUIC UDP ports on the home router via UPnP")
+	daemon := flag.Bool("daemon", false, "run without the stdin prompt; commands come only from the control socket (requires --name)")
+	flag.Parse()
+
+	if *daemon {
+		if *peerName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --daemon requires --name")
+			os.Exit(2)
+		}
+		if *controlSocket == "" {
+			*controlSocket = control.DefaultSocketPath()
+		}
+	}
+
+	// `config init` template config file likh kar exit kar deta hai
+	if handled, err := config.RunSubcommand(flag.Args(), *configPath); handled {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// .env pehle load karte hai taaki uske variables bhi config file ko override kar sake
+	envErr := godotenv.Load()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+		os.Exit(2)
+	}
+	// sirf explicitly diye gaye flags config ko override karte hai
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		case "log-format":
+			cfg.LogFormat = *logFormat
+		}
+	})
+
+	l, err := logging.New(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging flags: %v\n", err)
+		os.Exit(2)
+	}
+	logging.Configure(l)
+
+	if envErr != nil {
+		logger.Warn("Could not load .env file, proceeding with system environment variables", "error", envErr)
+	}
+
+	if *pidFile != "" {
+		if err := pidfile.Write(*pidFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer pidfile.Remove(*pidFile)
 	}
 
 	// Create libp2p host with WebSocket support
 	h, err := libp2p.New(
-		libp2p.Transport(libp2pws.New),                    // Add WebSocket transport
-		libp2p.ListenAddrStrings("/ip4/0.0.0.0/tcp/0/ws"), // WebSocket listen address
+		libp2p.Transport(libp2pws.New),               // Add WebSocket transport
+		libp2p.ListenAddrStrings(cfg.ListenAddrs...), // default: IPv4 + IPv6 dual-stack WebSocket
 	)
 	if err != nil {
-		log.Fatal("Failed to create libp2p host:", err)
+		logger.Error("Failed to create libp2p host", "error", err)
+		os.Exit(1)
 	}
-	log.Printf("Peer libp2p Host ID: %s", h.ID())
+	logger.Info("Peer libp2p host created", "peer_id", h.ID(), "addrs", h.Addrs())
+	// WebRTC ka port startup par pata nahi hota: pion har peer connection ke liye naya UDP port kholta hai
+	logger.Info("WebRTC listens on a new UDP port for each peer connection, run status to see each peer's local ICE address")
 
-	setupGracefulShutdown(h)
+	ipv4, ipv6, err := getLocalIP()
+	if err != nil {
+		logger.Warn("Could not detect local IP address", "error", err)
+	}
+	logger.Info("Local addresses", "ipv4", valueOrNone(ipv4), "ipv6", valueOrNone(ipv6))
 
-	// Get WebSocket tracker URL from .env with fallback
-	trackerWSURL := os.Getenv("TRACKER_WS_URL")
-	if trackerWSURL == "" {
-		trackerWSURL = "ws://localhost:8080/ws" // Listen on all interfaces
-		log.Printf("TRACKER_WS_URL not set, using default: %s", trackerWSURL)
+	// home router ke peeche bahar ke peers seedha connect kar sake
+	var gateway *nat.Gateway
+	if *upnp {
+		if gateway, err = startPortMapping(h); err != nil {
+			logger.Warn("UPnP port mapping disabled", "error", err)
+		} else {
+			defer releasePortMappings(gateway)
+		}
 	}
-	log.Printf("Connecting to tracker at: %s", trackerWSURL)
+
+	setupGracefulShutdown(h, *pidFile, gateway)
+
+	// tracker URL config file, TRACKER_WS_URL env ya default se aata hai
+	trackerWSURL := cfg.TrackerURL
+	logger.Info("Connecting to tracker", "url", trackerWSURL)
 
 	client := NewClient(h)
+	client.ipv4, client.ipv6 = ipv4, ipv6
+	client.pieceLength = cfg.PieceLength
+	client.transferHMAC = cfg.TransferHMAC
+	client.webRTCConfig.DataChannelPoolSize = cfg.DataChannelPool
+	client.passive = *passive
+	client.maxFileSize = *maxFileSize
+	client.peerName = *peerName
+	if *btTrackerURL != "" {
+		if id, err := tracker.NewPeerID(); err != nil {
+			logger.Warn("BitTorrent tracker announces disabled", "error", err)
+		} else {
+			client.btTrackerURL, client.btPeerID = *btTrackerURL, id
+		}
+	}
+	if client.passive {
+		logger.Info("Passive mode: only answering incoming connections")
+	}
+	if bs, err := blockstore.Open(cfg.BlockStoreDir); err != nil {
+		logger.Warn("Block store disabled", "error", err)
+	} else {
+		client.blocks = bs
+	}
+	if *unordered {
+		client.webRTCConfig.DataChannel.Ordered = false
+	}
+	if len(cfg.TURNServers) > 0 {
+		turn := make([]webrtc.ICEServer, len(cfg.TURNServers))
+		for i, s := range cfg.TURNServers {
+			turn[i] = webrtc.ICEServer{URLs: s.URLs, Username: s.Username, Credential: s.Credential}
+		}
+		client.webRTCConfig.SetTURNServers(turn)
+	}
 	// WebRTC offers ko handle karne ke liye signaling protocol register kra hain.
-	p2p.RegisterSignalingProtocol(h, client.handleWebRTCOffer)
+	p2p.RegisterSignalingProtocol(h, client.peerManager, client.handleWebRTCOffer)
+	// WebRTC fail ho jaye toh peers QUIC par connect kar sake
+	if err := client.startQuicListener(); err != nil {
+		logger.Warn("QUIC fallback disabled", "error", err)
+	}
+	// WebRTC aur UDP dono block ho toh downloads seedha TCP par ho sake; port handshake mein tracker ko jaata hai
+	if err := client.startTCPServer(*tcpPort); err != nil {
+		logger.Warn("TCP fallback disabled", "error", err)
+	}
+
+	// LAN peers bina tracker ya manual multiaddr ke mil jaate hai
+	if *enableMDNS {
+		if svc, err := client.startMDNS(); err != nil {
+			logger.Warn("mDNS discovery disabled", "error", err)
+		} else {
+			defer svc.Close()
+		}
+	}
 
 	if err := client.connectToTrackerWS(trackerWSURL); err != nil {
-		log.Fatalf("Failed to connect to tracker: %v", err)
+		logger.Error("Failed to connect to tracker", "error", err)
+		os.Exit(1)
 	}
 	defer client.trackerConn.Close()
+	client.loadBans()
+
+	// transfers ki progress terminal par dikhane ke liye renderer
+	go progress.NewRenderer(os.Stdout, "> ").Run(client.transferEvents)
+
+	go client.gossipLoop(gossipInterval)
+	go client.peerstoreGCLoop(peerstoreGCInterval)
+	go client.statsLoop(statsSampleInterval)
+
+	client.startAPIServer(cfg.APIPort)
+
+	if cfg.WatchDir != "" {
+		client.announceDir(cfg.WatchDir)
+	}
+	if len(cfg.BootstrapPeers) > 0 && !client.passive {
+		go client.connectBootstrapPeers(cfg.BootstrapPeers)
+	}
 
+	if *controlSocket != "" {
+		if err := client.startControlSocket(*controlSocket); err != nil {
+			logger.Error("Failed to open control socket", "path", *controlSocket, "error", err)
+			os.Exit(1)
+		}
+	}
+	if *daemon {
+		// shutdown signal handler (setupGracefulShutdown) process band karta hai
+		select {}
+	}
 	client.commandLoop()
 }
 
 func NewClient(h host.Host) *Client {
-	return &Client{
-		host:                h,
-		webRTCPeers:         make(map[peer.ID]*torrentiumWebRTC.WebRTCPeer),
-		sharingFiles:        make(map[uuid.UUID]string),
-		activeDownloads:     make(map[uuid.UUID]*os.File),
-		fileListChan:        make(chan []db.File, 1),
-		peerListChan:        make(chan []db.Peer, 1),
-		requestResponseChan: make(chan p2p.Message, 1),
+	c := &Client{
+		host:            h,
+		stdin:           bufio.NewScanner(os.Stdin),
+		pieceLength:     torrentfile.DefaultPieceLength,
+		webRTCConfig:    torrentiumWebRTC.DefaultConfig(),
+		webRTCPeers:     make(map[peer.ID]*torrentiumWebRTC.WebRTCPeer),
+		quicPeers:       make(map[peer.ID]*quictransport.QuicTransfer),
+		reconnecting:    make(map[peer.ID]bool),
+		bitfields:       make(map[peer.ID]map[string][]bool),
+		peerManager:     p2p.NewPeerManager(),
+		sharingFiles:    make(map[uuid.UUID]string),
+		localFiles:      make(map[string]p2p.FileRecord),
+		activeDownloads: make(map[uuid.UUID]*os.File),
+		downloadedBytes: make(map[uuid.UUID]int64),
+		receivingPaths:  make(map[FileTransport]string),
+		uploads:         make(map[FileTransport]context.CancelFunc),
+		announcedAt:     make(map[peer.ID]time.Time),
+		catalogWaits:    make(map[peer.ID]chan []p2p.FileRecord),
+		repairs:         make(map[FileTransport]*pieceRepair),
+		eventsDone:      make(map[FileTransport]chan struct{}),
+		transferEvents:  make(chan torrentiumWebRTC.TransferEvent, 64),
+		events:          api.NewEventHub(),
+		pendingRequests: make(map[uint64]chan p2p.Message),
 	}
+	c.downloads = NewDownloadManager(c.trackerRequest, c.peersWithPiece)
+	return c
 }
 
 // WebSocket connection to tracker
 func (c *Client) connectToTrackerWS(wsURL string) error {
-	fmt.Print("Enter your peer name: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	if !scanner.Scan() {
-		return errors.New("failed to read peer name")
+	// --name na diya ho toh naam stdin se poochte hai
+	if c.peerName == "" {
+		fmt.Print("Enter your peer name: ")
+		if !c.stdin.Scan() {
+			return errors.New("failed to read peer name")
+		}
+		c.peerName = c.stdin.Text()
 	}
-	c.peerName = scanner.Text()
 	if c.peerName == "" {
 		return errors.New("peer name cannot be empty")
 	}
@@ -124,11 +346,27 @@ func (c *Client) connectToTrackerWS(wsURL string) error {
 	}
 
 	// Connect to WebSocket tracker
-	c.trackerConn, _, err = websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WebSocket tracker: %w", err)
 	}
-	log.Println("Successfully connected to tracker via WebSocket.")
+	c.trackerConn = &trackerSocket{Conn: conn}
+	logger.Info("Successfully connected to tracker via WebSocket")
+
+	// tracker pehle NONCE bhejta hai; handshake mein us par apni libp2p key se signature dete hai, taaki tracker
+	// maan sake ki yeh peer ID sach mein hamari hai
+	var nonceMsg p2p.Message
+	if err := c.trackerConn.ReadJSON(&nonceMsg); err != nil {
+		return fmt.Errorf("failed to read nonce from tracker: %w", err)
+	}
+	var nonce p2p.NoncePayload
+	if nonceMsg.Command != "NONCE" || json.Unmarshal(nonceMsg.Payload, &nonce) != nil {
+		return fmt.Errorf("expected NONCE from tracker, got %s", nonceMsg.Command)
+	}
+	sig, err := tracker.SignHandshake(c.host.Peerstore().PrivKey(c.host.ID()), nonce.Nonce, c.host.ID().String())
+	if err != nil {
+		return fmt.Errorf("failed to sign tracker handshake: %w", err)
+	}
 
 	// Send handshake directly using WebSocket JSON
 	addrs := c.host.Addrs()
@@ -141,6 +379,10 @@ func (c *Client) connectToTrackerWS(wsURL string) error {
 		Name:        c.peerName,
 		ListenAddrs: addrStrings,
 		PeerID:      c.host.ID().String(),
+		IPv4:        c.ipv4,
+		IPv6:        c.ipv6,
+		TCPPort:     c.tcpPort,
+		Signature:   sig,
 	})
 	msg := p2p.Message{Command: "HANDSHAKE", Payload: handshakePayload}
 
@@ -150,13 +392,13 @@ func (c *Client) connectToTrackerWS(wsURL string) error {
 
 	// Wait for welcome message
 	var welcomeMsg p2p.Message
-	log.Printf("%T", welcomeMsg.Payload)
 	if err := c.trackerConn.ReadJSON(&welcomeMsg); err != nil {
-		log.Printf("Error reading welcome message: %v", welcomeMsg.Payload)
-		log.Printf("Error reading welcome message: %T", welcomeMsg.Payload)
 		return fmt.Errorf("failed to read welcome message from tracker: %w", err)
 	}
-	log.Printf("✅ Tracker handshake complete. Welcome message: %s", welcomeMsg.Command)
+	if welcomeMsg.Command != "WELCOME" {
+		return fmt.Errorf("tracker rejected handshake: %s", welcomeMsg.Payload)
+	}
+	logger.Info("Tracker handshake complete", "welcome", welcomeMsg.Command)
 
 	// Start background message handler
 	go c.handleIncomingMessages()
@@ -169,7 +411,7 @@ func (c *Client) handleIncomingMessages() {
 	for {
 		var msg p2p.Message
 		if err := c.trackerConn.ReadJSON(&msg); err != nil {
-			log.Printf("Error reading message from tracker: %v", err)
+			logger.Error("Error reading message from tracker", "error", err)
 			return
 		}
 
@@ -177,47 +419,13 @@ func (c *Client) handleIncomingMessages() {
 		case "REQUEST_FILE":
 			go c.handleFileRequest(msg)
 		case "FILE_CHUNK":
-			go c.handleFileChunk(msg)
-		case "FILE_LIST":
-			// Handle file list response
-			var files []db.File
-			if err := json.Unmarshal(msg.Payload, &files); err != nil {
-				log.Printf("Error unmarshaling file list: %v", err)
-				continue
-			}
-			select {
-			case c.fileListChan <- files:
-				// Successfully sent to channel
-			default:
-				// Channel full, ignore (shouldn't happen with buffer size 1)
-				log.Printf("File list channel full, ignoring response")
-			}
-		case "PEER_LIST_ALL":
-			// Handle peer list response
-			var peers []db.Peer
-			if err := json.Unmarshal(msg.Payload, &peers); err != nil {
-				log.Printf("Error unmarshaling peer list: %v", err)
-				continue
-			}
-			select {
-			case c.peerListChan <- peers:
-				// Successfully sent to channel
-			default:
-				// Channel full, ignore (shouldn't happen with buffer size 1)
-				log.Printf("Peer list channel full, ignoring response")
-			}
-		case "FILE_REQUEST_INITIATED", "ERROR", "ACK":
-			// Handle generic responses
-			select {
-			case c.requestResponseChan <- msg:
-				// Successfully sent to channel
-			default:
-				// Channel full, ignore
-				log.Printf("Request response channel full, ignoring response")
-			}
+			// chunks file mein append hote hai, isliye tracker ke order mein yahin likhte hai (goroutine mein order bigad jaata)
+			c.handleFileChunk(msg)
 		default:
-			// Ignore other messages in background handler
-			log.Printf("Unhandled message command: %s", msg.Command)
+			// baaki sab kisi trackerRequest ke responses hai; RequestID ke bina wale (jaise PIECES_MARKED) ka koi wait nahi karta
+			if !c.deliverTrackerResponse(msg) {
+				logger.Debug("Unhandled message command", "command", msg.Command, "request_id", msg.RequestID)
+			}
 		}
 	}
 }
@@ -226,7 +434,7 @@ func (c *Client) handleIncomingMessages() {
 func (c *Client) handleFileChunk(msg p2p.Message) {
 	var chunkPayload p2p.FileTransferPayload
 	if err := json.Unmarshal(msg.Payload, &chunkPayload); err != nil {
-		log.Printf("Error unmarshaling file chunk: %v", err)
+		logger.Error("Error unmarshaling file chunk", "error", err)
 		return
 	}
 
@@ -242,43 +450,58 @@ func (c *Client) handleFileChunk(msg p2p.Message) {
 
 	// Write chunk to file
 	if _, err := outputFile.Write(chunkPayload.ChunkData); err != nil {
-		log.Printf("Failed to write chunk to file: %v", err)
+		logger.Error("Failed to write chunk to file", "file", chunkPayload.Filename, "error", err)
 		return
 	}
+	api.BytesDownloaded.Add(float64(len(chunkPayload.ChunkData)))
+
+	c.downloadsMux.Lock()
+	c.downloadedBytes[chunkPayload.FileID] += int64(len(chunkPayload.ChunkData))
+	done := c.downloadedBytes[chunkPayload.FileID]
+	c.downloadsMux.Unlock()
 
-	log.Printf("Received chunk %d for file %s", chunkPayload.ChunkIndex, chunkPayload.Filename)
+	ev := torrentiumWebRTC.TransferEvent{
+		Type:       torrentiumWebRTC.TransferProgress,
+		Filename:   chunkPayload.Filename,
+		BytesDone:  done,
+		TotalBytes: chunkPayload.TotalSize,
+	}
 
 	if chunkPayload.IsLast {
-		log.Printf("File download completed: %s", chunkPayload.Filename)
 		outputFile.Close()
 
 		// Remove from active downloads
 		c.downloadsMux.Lock()
 		delete(c.activeDownloads, chunkPayload.FileID)
+		delete(c.downloadedBytes, chunkPayload.FileID)
 		c.downloadsMux.Unlock()
+
+		ev.Type = torrentiumWebRTC.TransferComplete
+		go c.verifyDownload(chunkPayload.FileID, chunkPayload.Filename)
 	}
+	c.emitTransferEvent(ev)
 }
 
 // handleFileRequest handles incoming file requests from other peers via tracker
 func (c *Client) handleFileRequest(msg p2p.Message) {
 	var payload p2p.RequestFilePayload
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-		log.Printf("Error unmarshaling file request: %v", err)
+		logger.Error("Error unmarshaling file request", "error", err)
 		return
 	}
 
-	log.Printf("Received file request for FileID: %s from peer: %s", payload.FileID, payload.RequesterPeerID)
+	logger.Info("Received file request", "file_id", payload.FileID, "requester", payload.RequesterPeerID)
 
 	// Check if we have this file
-	filePath, exists := c.sharingFiles[payload.FileID]
+	filePath, exists := c.sharedFilePath(payload.FileID)
 	if !exists {
-		log.Printf("File %s not found in sharing files", payload.FileID)
+		logger.Warn("File not found in sharing files", "file_id", payload.FileID)
 		return
 	}
 
 	// Open and send the file
 	if err := c.sendFileToTracker(payload.FileID, filePath, payload.RequesterPeerID); err != nil {
-		log.Printf("Error sending file: %v", err)
+		logger.Error("Error sending file", "file_id", payload.FileID, "error", err)
 	}
 }
 
@@ -300,7 +523,7 @@ func (c *Client) sendFileToTracker(fileID uuid.UUID, filePath string, requesterP
 	buffer := make([]byte, chunkSize)
 	chunkIndex := 0
 
-	log.Printf("Sending file %s (%d bytes) to peer %s", filepath.Base(filePath), fileInfo.Size(), requesterPeerID)
+	logger.Info("Sending file", "file", filepath.Base(filePath), "bytes", fileInfo.Size(), "peer", requesterPeerID)
 
 	for {
 		n, err := file.Read(buffer)
@@ -334,11 +557,12 @@ func (c *Client) sendFileToTracker(fileID uuid.UUID, filePath string, requesterP
 			return fmt.Errorf("failed to send chunk %d: %w", chunkIndex, err)
 		}
 
-		log.Printf("Sent chunk %d (%d bytes)", chunkIndex, n)
+		logger.Debug("Sent chunk", "index", chunkIndex, "bytes", n)
+		api.BytesUploaded.Add(float64(n))
 		chunkIndex++
 
 		if isLast {
-			log.Printf("File transfer completed for %s", filepath.Base(filePath))
+			logger.Info("File transfer completed", "file", filepath.Base(filePath))
 			break
 		}
 	}
@@ -346,88 +570,335 @@ func (c *Client) sendFileToTracker(fileID uuid.UUID, filePath string, requesterP
 	return nil
 }
 
+// tracker se online peers ki list fetch karta hai
+func (c *Client) fetchPeers() ([]db.Peer, error) {
+	resp, err := c.trackerRequest("LIST_PEERS", nil)
+	if err != nil {
+		return nil, err
+	}
+	var peers []db.Peer
+	if err := json.Unmarshal(resp.Payload, &peers); err != nil {
+		return nil, fmt.Errorf("failed to parse peer list: %w", err)
+	}
+	c.rememberPeerAddrs(peers)
+	return peers, nil
+}
+
+// trackerSocket tracker ka WebSocket hai. gorilla/websocket ek time par ek hi writer allow karta hai, aur tracker ko
+// commands, relayed file chunks aur piece cache updates alag goroutines se jaate hai, isliye WriteJSON lock leta hai.
+type trackerSocket struct {
+	*websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (s *trackerSocket) WriteJSON(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.Conn.WriteJSON(v)
+}
+
+// tracker ko ek command bhejta hai aur usi RequestID wale response ka wait karta hai.
+// Har request ka apna channel hai, isliye command loop, API handlers aur transfer goroutines ek saath request kar sakte hai.
+func (c *Client) trackerRequest(command string, payload interface{}) (p2p.Message, error) {
+	return c.trackerRequestContext(context.Background(), command, payload)
+}
+
+// trackerRequest jaisa hai, par ctx cancel hote hi (jaise API client ne request chhod di) wait band kar deta hai
+func (c *Client) trackerRequestContext(ctx context.Context, command string, payload interface{}) (p2p.Message, error) {
+	msg := p2p.Message{Command: command}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return p2p.Message{}, err
+		}
+		msg.Payload = data
+	}
+
+	respCh := make(chan p2p.Message, 1)
+	c.pendingMu.Lock()
+	c.nextRequestID++
+	msg.RequestID = c.nextRequestID
+	c.pendingRequests[msg.RequestID] = respCh
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pendingRequests, msg.RequestID)
+		c.pendingMu.Unlock()
+	}()
+
+	if err := c.trackerConn.WriteJSON(msg); err != nil {
+		return p2p.Message{}, err
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Command == "ERROR" {
+			return resp, fmt.Errorf("tracker error: %s", resp.Payload)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return p2p.Message{}, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return p2p.Message{}, fmt.Errorf("timeout waiting for tracker response")
+	}
+}
+
+// tracker ka response us request ke channel par bhejta hai jiska RequestID match kare.
+// Koi wait nahi kar raha (timeout ho gaya ya fire-and-forget message tha) toh false.
+func (c *Client) deliverTrackerResponse(msg p2p.Message) bool {
+	if msg.RequestID == 0 {
+		return false
+	}
+	c.pendingMu.Lock()
+	respCh, ok := c.pendingRequests[msg.RequestID]
+	c.pendingMu.Unlock()
+	if !ok {
+		return false
+	}
+	respCh <- msg // buffer 1 hai aur har ID ka ek hi response aata hai
+	return true
+}
+
+// user se ek sawaal poochta hai aur unka jawab (lowercase, trimmed) return karta hai
+func (c *Client) ask(out io.Writer, question string) string {
+	fmt.Fprint(out, question)
+	if !c.stdin.Scan() {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(c.stdin.Text()))
+}
+
 // traker se online peers ki list request karta hai
-func (c *Client) listPeers() error {
-	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "LIST_PEERS"}); err != nil {
+func (c *Client) listPeers(out io.Writer) error {
+	peers, err := c.fetchPeers()
+	if err != nil {
 		return err
 	}
 
-	// Wait for response from background handler
-	select {
-	case peers := <-c.peerListChan:
-		fmt.Println("\nOnline Peers:")
-		fmt.Println("----------------------------------------")
-		if len(peers) <= 1 {
-			fmt.Println("You are the only peer currently online.")
-		} else {
-			for _, peer := range peers {
-				if peer.PeerID == c.host.ID().String() {
-					continue // khud ko list mein nahi show karna hai
-				}
-				fmt.Printf("  Name: %s\n  ID:   %s\n", peer.Name, peer.PeerID)
-				fmt.Print("  Addrs:")
-				if len(peer.Multiaddrs) > 0 {
-					fmt.Printf(" %s\n", peer.Multiaddrs[0])
-				} else {
-					fmt.Printf(" No addresses available\n")
-				}
-				fmt.Println("----------------------------------------")
+	fmt.Fprintln(out, "\nOnline Peers:")
+	fmt.Fprintln(out, "----------------------------------------")
+	if len(peers) <= 1 {
+		fmt.Fprintln(out, "You are the only peer currently online.")
+	} else {
+		for _, peer := range peers {
+			if peer.PeerID == c.host.ID().String() {
+				continue // khud ko list mein nahi show karna hai
+			}
+			fmt.Fprintf(out, "  Name: %s\n  ID:   %s\n", peer.Name, peer.PeerID)
+			fmt.Fprint(out, "  Addrs:")
+			if len(peer.Multiaddrs) > 0 {
+				fmt.Fprintf(out, " %s\n", peer.Multiaddrs[0])
+			} else {
+				fmt.Fprintf(out, " No addresses available\n")
 			}
+			fmt.Fprintln(out, "----------------------------------------")
 		}
-		return nil
-	case <-time.After(10 * time.Second):
-		return fmt.Errorf("timeout waiting for peer list response")
 	}
+	return nil
 }
 
 // commandLoop user se input leta hai aur uske hisab se actions perform karta hai, jab tak connection close nhi ho jata
 func (c *Client) commandLoop() {
-	scanner := bufio.NewScanner(os.Stdin)
-	webRTC.PrintClientInstructions()
+	webRTC.PrintClientInstructions(os.Stdout)
 	for {
 		fmt.Print("> ")
-		if !scanner.Scan() {
+		if !c.stdin.Scan() {
 			break
 		}
-		parts := strings.Fields(scanner.Text())
+		parts := strings.Fields(c.stdin.Text())
 		if len(parts) == 0 {
 			continue
 		}
-		cmd, args := parts[0], parts[1:]
+		c.commandMu.Lock()
+		exit, err := c.runCommand(os.Stdout, true, parts[0], parts[1:], nil)
+		c.commandMu.Unlock()
+		if exit {
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+}
 
-		var err error
-		switch cmd {
-		case "help":
-			webRTC.PrintClientInstructions()
-		case "add":
-			if len(args) != 1 {
-				err = errors.New("usage: add <filepath>")
-			} else {
-				err = c.addFile(args[0])
+// ek command chalata hai, stdin loop aur control socket dono isi se jaate hai. exit true ho toh client band hona chahiye.
+// Command ka output out par jaata hai. interactive false ho (control socket) toh command stdin se kuch pooch nahi sakta.
+// input control socket ki stream request ka data hai (sirf `pipe` use karta hai), stdin loop se nil aata hai.
+// Caller commandMu pakde rakhta hai, kyunki stdin loop aur control socket ke commands ek hi client state badalte hai.
+func (c *Client) runCommand(out io.Writer, interactive bool, cmd string, args []string, input io.Reader) (exit bool, err error) {
+	if err := c.checkPassive(cmd); err != nil {
+		return false, err
+	}
+	switch cmd {
+	case "help":
+		webRTC.PrintClientInstructions(out)
+	case "add":
+		if len(args) == 2 && (args[0] == "--dry-run" || args[0] == "--preview") {
+			err = c.previewFile(out, args[1])
+		} else if len(args) != 1 {
+			err = errors.New("usage: add [--dry-run] <filepath>")
+		} else {
+			err = c.addFile(out, args[0])
+		}
+	case "info":
+		if len(args) != 1 {
+			err = errors.New("usage: info <filename>")
+		} else {
+			err = c.showTorrentInfo(out, args[0])
+		}
+	case "list":
+		err = c.listFiles(out, interactive)
+	case "list-local":
+		err = c.listLocalFiles(out)
+	case "listpeers":
+		err = c.listPeers(out)
+	case "connect":
+		if len(args) != 1 {
+			err = errors.New("usage: connect <multiaddr>")
+		} else {
+			err = c.connectPeer(out, args[0])
+		}
+	case "connect-all":
+		err = c.connectAllPeers(out, args)
+	case "disconnect":
+		if len(args) != 1 {
+			err = errors.New("usage: disconnect <peer_id>")
+		} else {
+			err = c.disconnectPeer(out, args[0])
+		}
+	case "peers":
+		switch {
+		case len(args) == 0:
+			err = c.showPeers(out)
+		case len(args) == 2 && args[0] == "import":
+			err = c.importPeers(out, args[1])
+		case len(args) == 2 && args[0] == "export":
+			err = c.exportPeers(out, args[1])
+		default:
+			err = errors.New("usage: peers [import|export <file>]")
+		}
+	case "status":
+		err = c.showStatus(out)
+	case "get":
+		if len(args) != 1 {
+			err = errors.New("usage: get <file_id> <output_path>")
+		} else {
+			err = c.get(out, args[0], "downloaded_"+args[0])
+		}
+	case "verify":
+		if len(args) != 1 {
+			err = errors.New("usage: verify <filename>")
+		} else {
+			err = c.verifyFile(out, args[0])
+		}
+	case "tag", "untag":
+		if len(args) != 2 {
+			err = fmt.Errorf("usage: %s <filename> <tag>", cmd)
+		} else {
+			err = c.tagFile(out, args[0], args[1], cmd == "untag")
+		}
+	case "get-range":
+		err = c.getRange(out, args)
+	case "sync":
+		if len(args) != 1 {
+			err = errors.New("usage: sync <peer_id>")
+		} else {
+			err = c.syncWithPeer(out, args[0])
+		}
+	case "send-dir":
+		if len(args) < 1 || len(args) > 2 {
+			err = errors.New("usage: send-dir <dirpath> [peer_id]")
+		} else {
+			idStr := ""
+			if len(args) == 2 {
+				idStr = args[1]
 			}
-		case "list":
-			err = c.listFiles()
-		case "listpeers":
-			err = c.listPeers()
-		case "get":
-			if len(args) != 1 {
-				err = errors.New("usage: get <file_id> <output_path>")
-			} else {
-				err = c.get(args[0], "downloaded_"+args[0])
+			err = c.sendDirectory(out, args[0], idStr)
+		}
+	case "stats":
+		if len(args) != 0 {
+			err = errors.New("usage: stats")
+		} else {
+			err = c.showNetworkStats(out)
+		}
+	case "top-seeders":
+		limit := 10
+		if len(args) > 1 {
+			err = errors.New("usage: top-seeders [limit]")
+		} else if len(args) == 1 {
+			if limit, err = strconv.Atoi(args[0]); err != nil || limit < 1 || limit > 100 {
+				err = errors.New("limit must be between 1 and 100")
 			}
-		case "exit":
-			return
-		default:
-			err = errors.New("unknown command")
 		}
-		if err != nil {
-			log.Printf("Error: %v", err)
+		if err == nil {
+			err = c.topSeeders(out, limit)
+		}
+	case "ban":
+		err = c.banPeer(out, args)
+	case "unban":
+		if len(args) != 1 {
+			err = errors.New("usage: unban <peer_id>")
+		} else {
+			err = c.unbanPeer(out, args[0])
 		}
+	case "allow", "deny":
+		if len(args) != 2 {
+			err = fmt.Errorf("usage: %s <filename> <peer_id>", cmd)
+		} else {
+			err = c.setFileAccess(out, args[0], args[1], cmd == "allow")
+		}
+	case "export":
+		if len(args) != 2 {
+			err = errors.New("usage: export [json|csv] <outfile>")
+		} else {
+			err = c.exportCatalog(out, args[0], args[1])
+		}
+	case "pipe":
+		if len(args) != 2 {
+			err = errors.New("usage: pipe <peer_id> <remote_filename>")
+		} else {
+			err = c.pipeInput(out, args[0], args[1], input)
+		}
+	case "announce":
+		err = c.announceLocalFiles(out)
+	case "at-risk":
+		err = c.showAtRiskFiles(out, args)
+	case "history":
+		err = c.showPeerHistory(out, args)
+	case "remove":
+		if len(args) != 1 {
+			err = errors.New("usage: remove <filename>")
+		} else {
+			err = c.removeFile(out, args[0])
+		}
+	case "exit":
+		return true, nil
+	default:
+		err = errors.New("unknown command")
+	}
+	return false, err
+}
+
+// file ka SHA-256 hash (hex) aur size calculate karta hai
+func calculateFileHash(filePath string) (string, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", 0, err
 	}
+	return fmt.Sprintf("%x", hasher.Sum(nil)), info.Size(), nil
 }
 
 // ek local file ko tracker par announce karta hai
-func (c *Client) addFile(filePath string) error {
+func (c *Client) addFile(out io.Writer, filePath string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
@@ -437,35 +908,63 @@ func (c *Client) addFile(filePath string) error {
 	if err != nil {
 		return err
 	}
+
+	// file ko ek hi baar padhte hai: TeeReader se SHA-256 aur .torrent ke piece hashes saath mein bante hai
 	hasher := sha256.New()
-	if _, err := io.Copy(hasher, file); err != nil {
-		return err
+	var torrent bytes.Buffer
+	// --bt-tracker-url diya ho toh .torrent mein wahi announce URL jaata hai, taaki BitTorrent clients swarm dhoondh sake
+	torrentCfg := torrentfile.TorrentConfig{Announce: c.btTrackerURL, CreatedBy: "Torrentium"}
+	meta, err := torrentfile.CreateTorrentFileFromReader(io.TeeReader(file, hasher), info.Name(), info.Size(), c.pieceLength, torrentCfg, &torrent)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	fileHash, fileSize := hex.EncodeToString(hasher.Sum(nil)), info.Size()
+
+	// Create the corresponding .torrent file; uska info-hash bhi tracker ko bhejte hain.
+	if err := os.WriteFile(filePath+".torrent", torrent.Bytes(), 0o644); err != nil {
+		logger.Warn("Failed to create .torrent file", "file", filePath, "error", err)
+	}
+	// announce se pehle check karte hai ki disk par file abhi bhi piece hashes se match karti hai,
+	// taaki corrupt ya beech mein badli hui file peers ko serve na ho. Restart ke baad pichli baar verify hue
+	// pieces (file ka modification time na badla ho toh) tracker ke piece cache se skip hote hai.
+	cached := c.fetchVerifiedPieces(fileHash, info.ModTime())
+	failed, err := torrentfile.VerifyPiecesSkipping(filePath, meta, cached)
+	if err != nil {
+		return fmt.Errorf("failed to verify pieces: %w", err)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d piece(s) failed verification (first: %d), not announcing", len(failed), failed[0])
+	}
+	c.markPiecesVerified(fileHash, info.ModTime(), torrentfile.PieceCount(fileSize, meta.Info.PieceLength), cached)
+	var infoHash string
+	ih, ihErr := torrentfile.InfoHash(meta)
+	if ihErr == nil {
+		infoHash = hex.EncodeToString(ih[:])
 	}
-	fileHash := fmt.Sprintf("%x", hasher.Sum(nil))
 
 	// Create the payload to send to the tracker.
-	payload, _ := json.Marshal(p2p.AnnounceFilePayload{
+	announce := p2p.AnnounceFilePayload{
 		FileHash: fileHash,
+		InfoHash: infoHash,
 		Filename: filepath.Base(filePath),
-		FileSize: info.Size(),
+		FileSize: fileSize,
 		PeerID:   c.host.ID().String(),
-	})
-
-	// Send the ANNOUNCE_FILE command to the tracker.
-	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "ANNOUNCE_FILE", Payload: payload}); err != nil {
-		return err
 	}
-
-	// Wait for response from background handler
-	var resp p2p.Message
-	select {
-	case resp = <-c.requestResponseChan:
-		// Got response
-	case <-time.After(10 * time.Second):
-		return fmt.Errorf("timeout waiting for tracker response")
+	// tracker signature se check karta hai ki announcement sach mein isi peer ka hai
+	if sig, err := tracker.SignAnnouncement(c.host.Peerstore().PrivKey(c.host.ID()), fileHash, announce.PeerID); err != nil {
+		logger.Warn("Could not sign announcement, other peers will not download it from us", "file", filePath, "error", err)
+	} else {
+		announce.Signature = sig
 	}
+	// tracker in hashes se file_pieces index banata hai, jisse downloaders pieces verify karte hai
+	announce.PieceLength = meta.Info.PieceLength
+	announce.Pieces = meta.PieceHashes()
 
-	// Wait for the "ACK" (acknowledgement) from the tracker.
+	// Send the ANNOUNCE_FILE command to the tracker and wait for the "ACK" (acknowledgement).
+	resp, err := c.trackerRequest("ANNOUNCE_FILE", announce)
+	if err != nil {
+		return err
+	}
 	if resp.Command != "ACK" {
 		return fmt.Errorf("tracker responded with error: %s", resp.Payload)
 	}
@@ -475,45 +974,89 @@ func (c *Client) addFile(filePath string) error {
 	if err := json.Unmarshal(resp.Payload, &ackPayload); err != nil {
 		return fmt.Errorf("failed to parse tracker's ACK payload: %w", err)
 	}
+	c.filesMux.Lock()
 	c.sharingFiles[ackPayload.FileID] = filePath // Add the file to the map.
-
-	// Create the corresponding .torrent file.
-	if err := torrentfile.CreateTorrentFile(filePath); err != nil {
-		log.Printf("Warning: failed to create .torrent file: %v", err)
+	c.localFiles[fileHash] = p2p.FileRecord{
+		Hash:         fileHash,
+		Name:         filepath.Base(filePath),
+		Size:         fileSize,
+		OriginPeerID: c.host.ID().String(),
+		FileID:       ackPayload.FileID.String(),
+	}
+	c.filesMux.Unlock()
+	c.updateFilesAnnouncedMetric()
+	if c.btTrackerURL != "" && ihErr == nil {
+		go c.announceToBTTracker(filePath, ih)
 	}
 
-	fmt.Printf("File '%s' announced successfully and is ready to be shared.\n", filepath.Base(filePath))
+	fmt.Fprintf(out, "File '%s' announced successfully and is ready to be shared.\n", filepath.Base(filePath))
 	return nil
 }
 
-// listFiles tracker par available sabhi files ki list get karta hai.
-func (c *Client) listFiles() error {
-	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "LIST_FILES"}); err != nil {
-		return err
-	}
+// ek page par kitni files dikhani hai
+const filesPerPage = 20
 
-	// Wait for response from background handler
-	select {
-	case files := <-c.fileListChan:
-		if len(files) == 0 {
-			fmt.Println("No files available on the tracker.")
-			return nil
+// listFiles tracker par available files ko 20-20 ke pages mein dikhata hai, n/p se pages badalte hai.
+// interactive false ho toh poochne ki jagah saare pages ek ke baad ek likhta hai.
+func (c *Client) listFiles(out io.Writer, interactive bool) error {
+	known := make(map[string]bool)
+	page := 1
+	for {
+		resp, err := c.trackerRequest("LIST_FILES_PAGE", p2p.ListFilesPagePayload{Page: page, PerPage: filesPerPage})
+		if err != nil {
+			return err
+		}
+		var result p2p.FilePagePayload
+		if err := json.Unmarshal(resp.Payload, &result); err != nil {
+			return fmt.Errorf("failed to parse file list: %w", err)
 		}
 
-		fmt.Println("\nAvailable Files:")
-		for _, file := range files {
-			fmt.Println("--------------------")
-			fmt.Printf("  ID: %s\n  Name: %s\n  Size: %s\n", file.ID, file.Filename, torrentiumWebRTC.FormatFileSize(file.FileSize))
+		if result.Total == 0 {
+			fmt.Fprintln(out, "No files available on the tracker.")
+			break
+		}
+
+		pages := (result.Total + filesPerPage - 1) / filesPerPage
+		fmt.Fprintf(out, "\nAvailable Files (page %d/%d, %d total):\n", page, pages, result.Total)
+		for _, file := range result.Files {
+			known[file.FileHash] = true
+			fmt.Fprintln(out, "--------------------")
+			fmt.Fprintf(out, "  ID: %s\n  Name: %s\n  Size: %s\n", file.ID, file.Filename, torrentiumWebRTC.FormatFileSizeIEC(file.FileSize))
+			if len(file.Tags) > 0 {
+				fmt.Fprintf(out, "  Tags: %s\n", strings.Join(file.Tags, ", "))
+			}
+		}
+		fmt.Fprintln(out, "--------------------")
+		if pages <= 1 {
+			break
+		}
+		if !interactive {
+			if page == pages {
+				break
+			}
+			page++
+			continue
+		}
+
+		ans := c.ask(out, "[n]ext, [p]revious, [q]uit: ")
+		if ans == "n" || ans == "next" {
+			if page < pages {
+				page++
+			}
+		} else if ans == "p" || ans == "prev" || ans == "previous" {
+			if page > 1 {
+				page--
+			}
+		} else {
+			break
 		}
-		fmt.Println("--------------------")
-		return nil
-	case <-time.After(10 * time.Second):
-		return fmt.Errorf("timeout waiting for file list response")
 	}
+	c.printGossipFiles(out, known)
+	return nil
 }
 
 // get function ek file ko download karne ka process shuru karta hai using WebSocket.
-func (c *Client) get(fileIDStr string, outputPath string) error {
+func (c *Client) get(out io.Writer, fileIDStr string, outputPath string) error {
 	// pehle, fileID parse karte hai to locate and identify the file
 	fileID, err := uuid.Parse(fileIDStr)
 	if err != nil {
@@ -532,42 +1075,17 @@ func (c *Client) get(fileIDStr string, outputPath string) error {
 	c.downloadsMux.Unlock()
 
 	// tracker se file request send karte hai
-	payload, _ := json.Marshal(p2p.RequestFilePayload{
+	resp, err := c.trackerRequest("REQUEST_FILE", p2p.RequestFilePayload{
 		FileID:          fileID,
 		RequesterPeerID: c.host.ID().String(),
 	})
-
-	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "REQUEST_FILE", Payload: payload}); err != nil {
-		// Clean up on error
-		outputFile.Close()
-		c.downloadsMux.Lock()
-		delete(c.activeDownloads, fileID)
-		c.downloadsMux.Unlock()
-		return fmt.Errorf("failed to send REQUEST_FILE request: %w", err)
-	}
-
-	var resp p2p.Message
-
-	// Wait for response from background handler
-	select {
-	case resp = <-c.requestResponseChan:
-		// Got response
-	case <-time.After(10 * time.Second):
-		// Clean up on timeout
-		outputFile.Close()
-		c.downloadsMux.Lock()
-		delete(c.activeDownloads, fileID)
-		c.downloadsMux.Unlock()
-		return fmt.Errorf("timeout waiting for tracker response")
-	}
-
-	if resp.Command == "ERROR" {
+	if err != nil {
 		// Clean up on error
 		outputFile.Close()
 		c.downloadsMux.Lock()
 		delete(c.activeDownloads, fileID)
 		c.downloadsMux.Unlock()
-		return fmt.Errorf("tracker error: %s", resp.Payload)
+		return err
 	}
 
 	if resp.Command != "FILE_REQUEST_INITIATED" {
@@ -579,55 +1097,114 @@ func (c *Client) get(fileIDStr string, outputPath string) error {
 		return fmt.Errorf("unexpected tracker response: %s", resp.Command)
 	}
 
-	fmt.Printf("File request initiated. Download will continue in background...\n")
-	fmt.Printf("Check the file at: %s\n", outputPath)
+	var initiated p2p.FileRequestInitiatedPayload
+	if err := json.Unmarshal(resp.Payload, &initiated); err != nil {
+		logger.Debug("Tracker did not send piece info", "error", err)
+	} else {
+		if initiated.FileHash != "" {
+			c.downloads.Track(fileID, initiated.FileHash, initiated.PieceLength, outputPath)
+		}
+		go c.watchDownloadForTCPFallback(initiated, outputPath)
+	}
+
+	fmt.Fprintf(out, "Downloading to %s...\n", outputPath)
 
 	return nil
 }
 
 // WebRTC offer/answer exchange process ko handle karta hai
 func (c *Client) initiateWebRTCConnection(targetPeerID peer.ID) (*torrentiumWebRTC.WebRTCPeer, error) {
-	//signaling ke liye target peer ke saath ek naya stream kholte hai(isse shayad libp2p pe shift karna hai)
-	s, err := c.host.NewStream(context.Background(), targetPeerID, p2p.SignalingProtocolID)
+	if c.passive {
+		return nil, errPassiveMode
+	}
+	webRTCPeer, err := torrentiumWebRTC.NewWebRTCPeer(c.onDataChannelMessage, c.webRTCConfig)
 	if err != nil {
 		return nil, err
 	}
-	// defer s.Close()
+	if err := c.signalWebRTCOffer(webRTCPeer, targetPeerID); err != nil {
+		webRTCPeer.Close()
+		return nil, err
+	}
+	return webRTCPeer, nil
+}
 
-	webRTCPeer, err := torrentiumWebRTC.NewWebRTCPeer(c.onDataChannelMessage)
+// naye (ya Reset kiye hue) peer ke liye offer/answer exchange karta hai aur connection banne ka wait karta hai
+func (c *Client) signalWebRTCOffer(webRTCPeer *torrentiumWebRTC.WebRTCPeer, targetPeerID peer.ID) error {
+	//signaling ke liye target peer ke saath ek naya stream kholte hai(isse shayad libp2p pe shift karna hai)
+	s, encoder, decoder, err := c.openSignalingStream(targetPeerID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	webRTCPeer.SetSignalingStream(s)
+	webRTCPeer.SetRemotePeerID(targetPeerID)
 
 	// Offer create karke signaling stream par bhejte hain
 	offer, err := webRTCPeer.CreateOffer()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	encoder := json.NewEncoder(s)
 	if err := encoder.Encode(offer); err != nil {
-		return nil, err
+		return err
 	}
 
 	//peer se answer ka wait karte hai
 	var answer string
-	decoder := json.NewDecoder(s)
 	if err := decoder.Decode(&answer); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := webRTCPeer.SetAnswer(answer); err != nil {
-		return nil, err
+		return err
 	}
 
 	//connection ko 30 sec ka time diya hai completely establish hone ke liye
 	if err := webRTCPeer.WaitForConnection(30 * time.Second); err != nil {
-		return nil, err
+		if errors.Is(err, torrentiumWebRTC.ErrTimeout) {
+			return fmt.Errorf("ICE did not connect to %s within 30s: %w", targetPeerID, err)
+		}
+		return err
 	}
-	return webRTCPeer, nil
+	return nil
+}
+
+// signaling stream kholta hai aur protocol version negotiate karta hai.
+// Purana peer VERSION message nahi samajhta, toh naye stream par bina negotiation ke (v1.0 ki tarah) chalte hai.
+func (c *Client) openSignalingStream(targetPeerID peer.ID) (network.Stream, *json.Encoder, *json.Decoder, error) {
+	s, err := c.host.NewStream(context.Background(), targetPeerID, p2p.SignalingProtocolID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	encoder, decoder := json.NewEncoder(s), json.NewDecoder(s)
+
+	err = p2p.NegotiateSignalingVersion(encoder, decoder)
+	if err == nil {
+		return s, encoder, decoder, nil
+	}
+	s.Reset()
+
+	var mismatch *p2p.VersionMismatchError
+	if errors.As(err, &mismatch) {
+		return nil, nil, nil, fmt.Errorf("peer %s runs an incompatible Torrentium version (signaling %s, ours %s); both peers need to upgrade to the same major version",
+			targetPeerID, mismatch.Remote, mismatch.Local)
+	}
+	if errors.Is(err, p2p.ErrBanned) {
+		return nil, nil, nil, fmt.Errorf("peer %s has banned this node: %w", targetPeerID, err)
+	}
+	if errors.Is(err, p2p.ErrTooManyConnections) {
+		return nil, nil, nil, fmt.Errorf("peer %s rejected the connection: %w", targetPeerID, err)
+	}
+	if !errors.Is(err, p2p.ErrLegacySignaling) {
+		return nil, nil, nil, fmt.Errorf("signaling version negotiation failed: %w", err)
+	}
+
+	logger.Info("Peer uses legacy signaling, downgrading", "peer", targetPeerID)
+	s, err = c.host.NewStream(context.Background(), targetPeerID, p2p.SignalingProtocolID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s, json.NewEncoder(s), json.NewDecoder(s), nil
 }
 
 // fellow peer se aaye WebRTC offer ko handle karta hai
@@ -637,13 +1214,14 @@ func (c *Client) handleWebRTCOffer(offer, remotePeerIDStr string, s network.Stre
 		return "", err
 	}
 
-	log.Printf("Handling incoming WebRTC offer from %s", remotePeerID)
-	webRTCPeer, err := torrentiumWebRTC.NewWebRTCPeer(c.onDataChannelMessage)
+	logger.Info("Handling incoming WebRTC offer", "peer", remotePeerID)
+	webRTCPeer, err := torrentiumWebRTC.NewWebRTCPeer(c.onDataChannelMessage, c.webRTCConfig)
 	if err != nil {
 		return "", err
 	}
 
 	webRTCPeer.SetSignalingStream(s)
+	webRTCPeer.SetRemotePeerID(remotePeerID)
 
 	answer, err := webRTCPeer.CreateAnswer(offer)
 	if err != nil {
@@ -658,102 +1236,415 @@ func (c *Client) handleWebRTCOffer(offer, remotePeerIDStr string, s network.Stre
 
 // WebRTC data channel par aaye messages ko process karta hai
 func (c *Client) onDataChannelMessage(msg webrtc.DataChannelMessage, p *torrentiumWebRTC.WebRTCPeer) {
+	c.handleTransportMessage(p, msg.Data, msg.IsString)
+}
 
-	if msg.IsString {
-		var message map[string]string
-		if err := json.Unmarshal(msg.Data, &message); err != nil {
-			log.Printf("Received un-parseable message: %s", string(msg.Data))
+// kisi bhi transport (WebRTC ya QUIC) par aaye message ko process karta hai
+func (c *Client) handleTransportMessage(p FileTransport, data []byte, isString bool) {
+	if isString {
+		command, err := torrentiumWebRTC.ParseCommand(string(data))
+		if err != nil {
+			logger.Warn("Received un-parseable message", "peer", transportPeerID(p), "data", string(data), "error", err)
 			return
 		}
 
-		if cmd, ok := message["command"]; ok && cmd == "REQUEST_FILE" {
-			fileIDStr, hasFileID := message["file_id"]
-			if !hasFileID {
-				log.Println("Received file request without a file_id.")
-				return
-			}
-			fileID, err := uuid.Parse(fileIDStr)
+		switch cmd := command.(type) {
+		case torrentiumWebRTC.RequestFileCommand:
+			fileID, err := uuid.Parse(cmd.FileID)
 			if err != nil {
-				log.Printf("Received file request with invalid file ID: %s", fileIDStr)
+				logger.Warn("Received file request with invalid file ID", "peer", transportPeerID(p), "file_id", cmd.FileID)
 				return
 			}
 			// Start sending the file in a new concurrent routine.
 			go c.sendFile(p, fileID)
-		} else if status, ok := message["status"]; ok && status == "TRANSFER_COMPLETE" {
-			log.Println("File transfer complete!")
+		case torrentiumWebRTC.RequestRangeCommand:
+			fileID, err := uuid.Parse(cmd.FileID)
+			if err != nil {
+				logger.Warn("Received range request with invalid file ID", "peer", transportPeerID(p), "file_id", cmd.FileID)
+				return
+			}
+			go c.sendFileRange(p, fileID, cmd.Start, cmd.End)
+		case torrentiumWebRTC.FileStartCommand:
+			total := cmd.Size
+			// directory archive ka size pehle se pata nahi hota (extract hote bytes gine jaate hai), baaki transfers ke liye disk space check karte hai
+			if cmd.ContentType != torrentiumWebRTC.ContentTypeTarGzip && !c.acceptIncomingSize(p, cmd.Filename, cmd.Size) {
+				return
+			}
+			if cmd.Offset > 0 {
+				// range transfer: bytes existing file mein offset par jaate hai, compression aur block store nahi
+				f, err := p.ReceiveFileAt(cmd.Filename, cmd.Offset)
+				if err != nil {
+					logger.Warn("Rejected incoming file range", "peer", transportPeerID(p), "name", cmd.Filename, "error", err)
+					return
+				}
+				p.SetFileWriter(f)
+				p.SetTransferInfo(cmd.Filename, total)
+				return
+			}
+			if cmd.ContentType == torrentiumWebRTC.ContentTypeTarGzip {
+				// directory archive seedha receive directory mein extract hota hai
+				w, err := p.ReceiveDirectory()
+				if err != nil {
+					logger.Warn("Rejected incoming directory", "peer", transportPeerID(p), "name", cmd.Filename, "error", err)
+					return
+				}
+				p.SetFileWriter(&unknownSizeReceive{WriteCloser: w, c: c, p: p, name: cmd.Filename})
+				p.SetTransferInfo(cmd.Filename, 0)
+				return
+			}
+			// size 0 stream (pipe) ka size FILE_START par check nahi hota, isliye likhe gaye bytes gine jaate hai
+			stream := false
+			if p.GetFileWriter() == nil {
+				// naam remote peer deta hai, isliye file sirf receive directory ke andar banti hai
+				var f io.WriteCloser
+				var err error
+				if cmd.Size > 0 {
+					f, err = p.ReceiveFile(cmd.Filename)
+				} else {
+					f, err = p.ReceiveStream(cmd.Filename)
+					stream = true
+				}
+				if err != nil {
+					logger.Warn("Rejected incoming file", "peer", transportPeerID(p), "name", cmd.Filename, "error", err)
+					return
+				}
+				p.SetFileWriter(f)
+				// size 0 stream (pipe) hai: tracker mein iska hash nahi hota, isliye verification nahi
+				if file, ok := f.(*os.File); ok && cmd.Size > 0 {
+					c.downloadsMux.Lock()
+					c.receivingPaths[p] = file.Name()
+					c.downloadsMux.Unlock()
+				}
+			}
+			if writer := p.GetFileWriter(); writer != nil {
+				// file ke blocks block store mein bhi jaate hai taaki duplicate blocks dobara store na ho
+				if c.blocks != nil {
+					writer = blockstore.NewWriter(c.blocks, writer)
+				}
+				if cmd.Compress == torrentiumWebRTC.CompressionGzip {
+					writer = torrentiumWebRTC.NewGunzipWriter(writer)
+				}
+				if stream {
+					writer = &unknownSizeReceive{WriteCloser: writer, c: c, p: p, name: cmd.Filename}
+				}
+				p.SetFileWriter(writer)
+			}
+			if cmd.Compress == torrentiumWebRTC.CompressionGzip {
+				// compressed bytes aate hai, isliye total size se progress galat dikhega
+				total = 0
+			}
+			p.SetTransferInfo(cmd.Filename, total)
+		case torrentiumWebRTC.BitfieldCommand:
+			c.handleBitfield(p, cmd)
+		case torrentiumWebRTC.FileEndCommand:
+			p.CompleteTransfer()
 			if writer := p.GetFileWriter(); writer != nil {
-				writer.Close() // Close the output file.
+				// directory archive ho toh Close extraction poora hone tak rukta hai
+				if err := writer.Close(); err != nil {
+					logger.Warn("Failed to finish received file", "peer", transportPeerID(p), "name", cmd.Filename, "error", err)
+				}
+			}
+			c.downloadsMux.Lock()
+			path, ok := c.receivingPaths[p]
+			delete(c.receivingPaths, p)
+			c.downloadsMux.Unlock()
+			if ok {
+				// sender ke path ke directory components verification mein use nahi hote
+				name := filepath.Base(cmd.Filename)
+				if cmd.Filename == "" {
+					// purane peers TRANSFER_COMPLETE mein naam nahi bhejte
+					name = filepath.Base(path)
+				}
+				// connection tab tak khula rehta hai jab tak kharab pieces NACK se dobara na aa jaye
+				go func() {
+					c.verifyReceivedFile(p, path, name)
+					c.closeTransport(p)
+				}()
+			}
+			// connection band karne se pehle apni file list gossip kar dete hai
+			c.broadcastFileList()
+			if !ok {
+				c.closeTransport(p) // Close the WebRTC connection.
+			}
+		case torrentiumWebRTC.RangeEndCommand:
+			c.handleRangeEnd(p, cmd)
+		case torrentiumWebRTC.FileExistsCommand:
+			c.handleFileExists(cmd)
+		case torrentiumWebRTC.CancelCommand:
+			c.handleCancel(p, cmd)
+		case torrentiumWebRTC.NackCommand:
+			go c.sendPiece(p, cmd)
+		case torrentiumWebRTC.PieceDataCommand:
+			c.handlePieceData(p, cmd)
+		case torrentiumWebRTC.PieceEndCommand:
+			c.handlePieceEnd(p, cmd)
+		case torrentiumWebRTC.ErrorCommand:
+			if name, ok := strings.CutPrefix(cmd.Message, torrentiumWebRTC.ErrorAccessDenied+":"); ok {
+				fmt.Printf("❌ Access denied: %s is not shared with you\n", name)
+			}
+			logger.Warn("Peer reported a transfer error", "peer", transportPeerID(p), "error", cmd.Message)
+			c.failPieceRepair(p, fmt.Errorf("peer reported an error: %s", cmd.Message))
+		case torrentiumWebRTC.RawCommand:
+			switch cmd.Name {
+			case "LIST_FILES":
+				// har file ka access tracker se check hota hai, isliye message handler ko block nahi karte
+				go c.sendFileCatalog(p)
+				return
+			case "GOSSIP_FILES", "FILE_CATALOG":
+			default:
+				logger.Debug("Ignoring unknown data channel command", "command", cmd.Name)
+				return
+			}
+			var message p2p.ChannelMessage
+			if err := json.Unmarshal(cmd.Data, &message); err != nil {
+				logger.Warn("Received un-parseable file list", "peer", transportPeerID(p), "command", cmd.Name, "error", err)
+				return
+			}
+			if cmd.Name == "FILE_CATALOG" {
+				c.deliverFileCatalog(p, message.Files)
+			} else {
+				// tracker ka round trip message handler ko na roke
+				go c.mergeGossipFiles(message.Files)
 			}
-			p.Close() // Close the WebRTC connection.
 		}
 
 	} else {
 		// This is the downloader receiving file chunks.
 		if writer := p.GetFileWriter(); writer != nil {
-			if _, err := writer.Write(msg.Data); err != nil {
-				log.Printf("Error writing file chunk: %v", err)
+			if _, err := writer.Write(data); err != nil {
+				logger.Error("Error writing file chunk", "peer", transportPeerID(p), "error", err)
+				if _, limited := cancelReason(err); limited {
+					// sender ko CANCEL ja chuka hai, raste mein bache chunks kahin nahi likhne
+					p.SetFileWriter(nil)
+					writer.Close()
+				}
+			} else {
+				api.BytesDownloaded.Add(float64(len(data)))
 			}
 		} else {
-			log.Println("Received binary data but no file writer is active.")
+			logger.Warn("Received binary data but no file writer is active", "peer", transportPeerID(p))
 		}
 	}
 }
 
-func (c *Client) sendFile(p *torrentiumWebRTC.WebRTCPeer, fileID uuid.UUID) {
-	log.Printf("Processing request to send file with ID: %s", fileID)
+// share ki hui file ka local path uske file ID se dhoondhta hai
+func (c *Client) sharedFilePath(fileID uuid.UUID) (string, bool) {
+	c.filesMux.RLock()
+	defer c.filesMux.RUnlock()
+	path, ok := c.sharingFiles[fileID]
+	return path, ok
+}
+
+func (c *Client) sendFile(p FileTransport, fileID uuid.UUID) {
+	logger.Info("Processing request to send file", "file_id", fileID)
 
-	filePath, ok := c.sharingFiles[fileID]
+	filePath, ok := c.sharedFilePath(fileID)
 	if !ok {
-		log.Printf("Error: Received request for file ID %s, but I am not sharing it.", fileID)
-		p.Send(map[string]string{"error": "File not found"})
+		logger.Warn("Received request for a file that is not shared", "peer", transportPeerID(p), "file_id", fileID)
+		p.SendTextData(map[string]string{"error": "File not found"})
 		return
 	}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		log.Printf("Error opening file %s to send: %v", filePath, err)
-		p.Send(map[string]string{"error": "Could not open file"})
+	// file ki allow list mein na ho toh requester ko kuch nahi milta
+	if !c.requesterAllowed(p, fileID) {
+		logger.Warn("Refused file request from peer not in the allow list", "peer", transportPeerID(p), "file", filepath.Base(filePath))
+		p.SendTextData(torrentiumWebRTC.AccessDeniedMessage(filepath.Base(filePath)))
 		return
 	}
-	defer file.Close()
 
-	log.Printf("Starting file transfer for %s", filepath.Base(filePath))
-	buffer := make([]byte, 16*1024) // 16KB chunks
-	for {
-		bytesRead, err := file.Read(buffer)
-		if err != nil {
-			if err == io.EOF {
-				break // End of file
-			}
-			log.Printf("Error reading file chunk: %v", err)
-			return
-		}
-		if err := p.SendRaw(buffer[:bytesRead]); err != nil {
-			log.Printf("Error sending file chunk: %v", err)
-			return
+	// requester ke paas file pehle se hai toh dobara data bhejna bekaar hai
+	if status, ok := c.requesterHasFile(p, fileID); ok {
+		logger.Warn("Requester already has this file, not sending it", "peer", transportPeerID(p), "file", filepath.Base(filePath), "hash", status.FileHash)
+		p.SendTextData(map[string]string{"command": "FILE_EXISTS", "file_hash": status.FileHash, "name": status.Filename})
+		return
+	}
+
+	logger.Info("Starting file transfer", "file", filepath.Base(filePath))
+	ctx, done := c.startUpload(p)
+	defer done()
+	if err := p.SendFileWithContext(ctx, filePath, torrentiumWebRTC.DefaultPieceSize); err != nil {
+		var transferErr *torrentiumWebRTC.TransferError
+		switch {
+		case errors.Is(err, torrentiumWebRTC.ErrFileNotFound):
+			logger.Warn("Shared file is no longer on disk", "peer", transportPeerID(p), "file", filePath, "file_id", fileID)
+		case errors.Is(err, context.Canceled):
+			logger.Info("File transfer cancelled", "peer", transportPeerID(p), "file", filepath.Base(filePath))
+		case errors.As(err, &transferErr):
+			logger.Error("Error sending file", "file", filePath, "peer", transferErr.Peer, "error", transferErr.Cause)
+		default:
+			logger.Error("Error sending file", "peer", transportPeerID(p), "file", filePath, "error", err)
 		}
+		return
+	}
+	// upload counter sirf poori bheji gayi files ka size count karta hai
+	if info, err := os.Stat(filePath); err == nil {
+		api.BytesUploaded.Add(float64(info.Size()))
+		c.reportUpload(info.Size())
 	}
-	log.Printf("Finished sending file %s", filepath.Base(filePath))
-	// Send a "transfer complete" message so the receiver can clean up.
-	p.Send(map[string]string{"status": "TRANSFER_COMPLETE"})
+	logger.Info("Finished sending file", "file", filepath.Base(filePath))
 }
 
 // ek naye WebRTC peer ko thread-safe tarike se map mein add karta hai (race condition avoid karne ke liye)
 func (c *Client) addWebRTCPeer(id peer.ID, p *torrentiumWebRTC.WebRTCPeer) {
 	c.peersMux.Lock()
 	defer c.peersMux.Unlock()
+	// reconnect same peer ko naye connection se replace karta hai, use dobara nahi ginna
+	if _, exists := c.webRTCPeers[id]; !exists {
+		c.peerManager.Add()
+	}
 	c.webRTCPeers[id] = p
+	p.MaxReceiveFileSize = c.maxFileSize
+	p.SetPieceHandlers(c.pooledPieceHandlers(p))
+	c.enableTransferHMAC(id, p)
+	c.startForwardingEventsLocked(p)
+	c.announcePiecesOnConnect(p)
+	c.announceFilesOnConnect(id, p)
+	trackPeerStateMetrics(p)
+	p.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		c.events.Publish(api.TopicConnect, api.ConnectionEvent{PeerID: id.String(), State: s.String()})
+	})
+
+	// connection toot jaye toh reconnect try karte hai; band ho jaye (remote ne close kiya, ya fail hua aur
+	// reconnect humari zimmedari nahi) toh peer map aur connection count se hat jaata hai
+	p.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		switch s {
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateDisconnected:
+			c.reconnectPeer(id, p)
+		case webrtc.PeerConnectionStateClosed:
+			c.peersMux.RLock()
+			reconnecting := c.reconnecting[id]
+			c.peersMux.RUnlock()
+			if !reconnecting {
+				go c.closeWebRTCPeer(id, p)
+			}
+		}
+	})
+}
+
+// closeWebRTCPeer WebRTC peer ko band karne ka ek hi rasta hai: pehle map se hatata hai (taaki reconnect callback use
+// dobara na jode) aur connection count ghatata hai, phir connection close karta hai. p ki jagah map mein naya
+// connection aa chuka ho toh sirf p band hota hai, count nahi badalta.
+func (c *Client) closeWebRTCPeer(id peer.ID, p *torrentiumWebRTC.WebRTCPeer) {
+	c.peersMux.Lock()
+	removed := c.webRTCPeers[id] == p
+	if removed {
+		delete(c.webRTCPeers, id)
+		delete(c.announcedAt, id)
+		c.peerManager.Remove()
+	}
+	c.peersMux.Unlock()
+	if removed {
+		c.forgetBitfields(id)
+	}
+	if err := p.Close(); err != nil {
+		logger.Warn("Error closing WebRTC connection", "peer", id, "error", err)
+	}
+	// Close ke baad peer Reset se dobara nahi judta, isliye uske events ka intezaar khatam
+	c.stopForwardingEvents(p)
+}
+
+// transfer khatam hone par transport band karta hai; WebRTC peer closeWebRTCPeer se jaata hai taaki count sahi rahe
+func (c *Client) closeTransport(p FileTransport) {
+	switch t := p.(type) {
+	case *torrentiumWebRTC.WebRTCPeer:
+		c.closeWebRTCPeer(t.RemotePeerID(), t)
+	case *quictransport.QuicTransfer:
+		if id, err := peer.Decode(t.RemotePeerID()); err == nil {
+			c.peersMux.Lock()
+			if c.quicPeers[id] == t {
+				delete(c.quicPeers, id)
+			}
+			c.peersMux.Unlock()
+		}
+		t.Close()
+		c.stopForwardingEvents(t)
+	default:
+		p.Close()
+	}
+}
+
+// p ke liye forwardEvents goroutine shuru karta hai (pehle se chal rahi ho toh kuch nahi). c.peersMux lock hona chahiye.
+func (c *Client) startForwardingEventsLocked(p FileTransport) {
+	if _, running := c.eventsDone[p]; running {
+		return
+	}
+	done := make(chan struct{})
+	c.eventsDone[p] = done
+	go c.forwardEvents(p, done)
+}
+
+// p ki forwardEvents goroutine rokta hai. Transport ke events channel kabhi close nahi hote (WebRTCPeer Reset ke baad
+// wahi channel use karta hai), isliye transport ke aakhri teardown par yeh call na ho toh goroutine aur peer leak hote hai.
+func (c *Client) stopForwardingEvents(p FileTransport) {
+	c.peersMux.Lock()
+	c.stopForwardingEventsLocked(p)
+	c.peersMux.Unlock()
+}
+
+// stopForwardingEvents jaisa, jab c.peersMux pehle se lock ho
+func (c *Client) stopForwardingEventsLocked(p FileTransport) {
+	if done, running := c.eventsDone[p]; running {
+		delete(c.eventsDone, p)
+		close(done)
+	}
+}
+
+// peer ke transfer events ko client ke common events channel mein bhejta hai, done close hone tak
+func (c *Client) forwardEvents(p FileTransport, done <-chan struct{}) {
+	events := p.Events()
+	for {
+		select {
+		case <-done:
+			return
+		case ev := <-events:
+			if errors.Is(ev.Cause, torrentiumWebRTC.ErrReceiveTimeout) {
+				// adhoori file ka hash check nahi karna, der se aaya FILE_END use verify na kare
+				c.downloadsMux.Lock()
+				delete(c.receivingPaths, p)
+				c.downloadsMux.Unlock()
+			}
+			c.emitTransferEvent(ev)
+		}
+	}
+}
+
+// event ko progress renderer tak bhejta hai, agar channel full hai toh progress events drop kar deta hai
+func (c *Client) emitTransferEvent(ev torrentiumWebRTC.TransferEvent) {
+	c.events.Publish(api.TopicProgress, ev)
+	if ev.Type == torrentiumWebRTC.TransferComplete || ev.Type == torrentiumWebRTC.TransferFailed || ev.Type == torrentiumWebRTC.TransferCorrupt {
+		// complete/error/corrupt events drop nahi karte, warna prompt dobara print nahi hoga
+		c.transferEvents <- ev
+		c.reportTransfer(ev)
+		return
+	}
+	select {
+	case c.transferEvents <- ev:
+	default:
+	}
 }
 
-// Ctrl+C jaise signals ko handle karta hai taaki program theek se band ho
-func setupGracefulShutdown(h host.Host) {
+// map se WebRTC peer ko thread-safe tarike se fetch karta hai
+func (c *Client) getWebRTCPeer(id peer.ID) (*torrentiumWebRTC.WebRTCPeer, bool) {
+	c.peersMux.RLock()
+	defer c.peersMux.RUnlock()
+	p, ok := c.webRTCPeers[id]
+	return p, ok
+}
+
+// Ctrl+C jaise signals ko handle karta hai taaki program theek se band ho.
+// os.Exit defers nahi chalata, isliye PID file yahin hatti hai.
+func setupGracefulShutdown(h host.Host, pidFile string, gateway *nat.Gateway) {
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-ch
-		log.Println("Shutting down...")
+		logger.Info("Shutting down...")
+		releasePortMappings(gateway)
 		if err := h.Close(); err != nil {
-			log.Printf("Error closing libp2p host: %v", err)
+			logger.Error("Error closing libp2p host", "error", err)
+		}
+		if pidFile != "" {
+			pidfile.Remove(pidFile)
 		}
 		os.Exit(0)
 	}()
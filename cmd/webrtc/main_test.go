@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"torrentium/db/dbtest"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// cliTimeout ek step (tracker start, connect, download) ka maximum intezaar hai
+const cliTimeout = 60 * time.Second
+
+// process ek chalta hua binary hai jiska stdin likha ja sakta hai aur stdout+stderr ki lines padhi ja sakti hai
+type process struct {
+	t     *testing.T
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	mu     sync.Mutex
+	lines  []string
+	notify chan struct{} // har nayi line par signal
+	exited chan struct{}
+}
+
+// binary ko dir mein env ke saath start karta hai; test khatam hone par "exit" bhej kar (ya kill karke) band karta hai
+func startProcess(t *testing.T, name, dir string, env []string, bin string, args ...string) *process {
+	t.Helper()
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd.Stdin = stdinR
+	cmd.Stdout, cmd.Stderr = outW, outW
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting %s: %v", name, err)
+	}
+	// child ke paas apni copies hai
+	stdinR.Close()
+	outW.Close()
+
+	p := &process{t: t, name: name, cmd: cmd, stdin: stdinW, notify: make(chan struct{}, 1), exited: make(chan struct{})}
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			p.mu.Lock()
+			p.lines = append(p.lines, scanner.Text())
+			p.mu.Unlock()
+			select {
+			case p.notify <- struct{}{}:
+			default:
+			}
+		}
+		outR.Close()
+	}()
+	go func() {
+		cmd.Wait()
+		close(p.exited)
+	}()
+
+	t.Cleanup(func() {
+		p.send("exit")
+		p.stdin.Close()
+		select {
+		case <-p.exited:
+		case <-time.After(5 * time.Second):
+			cmd.Process.Kill()
+			<-p.exited
+		}
+		if t.Failed() {
+			p.mu.Lock()
+			t.Logf("%s output:\n%s", name, strings.Join(p.lines, "\n"))
+			p.mu.Unlock()
+		}
+	})
+	return p
+}
+
+// stdin par ek command bhejta hai
+func (p *process) send(line string) {
+	fmt.Fprintln(p.stdin, line)
+}
+
+// from index se aage pehli line jo re se match ho uske submatches aur agla index deta hai; cliTimeout tak rukta hai
+func (p *process) expect(from int, re *regexp.Regexp) ([]string, int) {
+	p.t.Helper()
+	deadline := time.After(cliTimeout)
+	for {
+		p.mu.Lock()
+		for i := from; i < len(p.lines); i++ {
+			if m := re.FindStringSubmatch(p.lines[i]); m != nil {
+				p.mu.Unlock()
+				return m, i + 1
+			}
+		}
+		from = len(p.lines)
+		p.mu.Unlock()
+
+		select {
+		case <-p.notify:
+		case <-p.exited:
+			p.t.Fatalf("%s exited while waiting for %q", p.name, re)
+		case <-deadline:
+			p.t.Fatalf("%s: timed out waiting for %q", p.name, re)
+		}
+	}
+}
+
+// ab tak aayi lines ki ginti, taaki agla expect sirf naye output mein dhoondhe
+func (p *process) mark() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.lines)
+}
+
+// go build se package ka binary dir mein banata hai
+func buildBinary(t *testing.T, dir, pkg string) string {
+	t.Helper()
+	bin := filepath.Join(dir, filepath.Base(pkg))
+	out, err := exec.Command("go", "build", "-o", bin, pkg).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go build %s: %v\n%s", pkg, err, out)
+	}
+	return bin
+}
+
+// localhost par ek khaali TCP port
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// TestCLIIntegration tracker aur do clients ko alag processes ki tarah chalata hai: A test_data/sample.txt add karta hai,
+// B A ke multiaddr se connect karke file list karta hai aur get se download karta hai. Downloaded file original jaisi honi chahiye.
+func TestCLIIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binaries and runs a tracker and two clients")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not in PATH")
+	}
+	dsn := dbtest.PostgresURL(t)
+
+	bins := t.TempDir()
+	trackerBin := buildBinary(t, bins, "torrentium/cmd/tracker")
+	clientBin := buildBinary(t, bins, "torrentium/cmd/webrtc")
+
+	trackerAddr := fmt.Sprintf("127.0.0.1:%d", freePort(t))
+	trackerDir := t.TempDir()
+	tracker := startProcess(t, "tracker", trackerDir, []string{"HOME=" + trackerDir, "DATABASE_URL=" + dsn, "TRACKER_WS_ADDR=" + trackerAddr}, trackerBin)
+	tracker.expect(0, regexp.MustCompile(`WebSocket tracker listening`))
+
+	startClient := func(name string) (*process, string) {
+		dir := t.TempDir()
+		env := []string{"HOME=" + dir, "TRACKER_WS_URL=ws://" + trackerAddr + "/ws", "TORRENTIUM_API_PORT=0"}
+		p := startProcess(t, name, dir, env, clientBin, "--name", name, "--mdns=false")
+		p.expect(0, regexp.MustCompile(`Tracker handshake complete`))
+		return p, dir
+	}
+	seeder, seederDir := startClient("seeder")
+	leecher, leecherDir := startClient("leecher")
+
+	want, err := os.ReadFile(filepath.Join("test_data", "sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(seederDir, "test_data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(seederDir, "test_data", "sample.txt"), want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	at := seeder.mark()
+	seeder.send("add test_data/sample.txt")
+	seeder.expect(at, regexp.MustCompile(`File 'sample\.txt' announced successfully`))
+
+	// seeder ka multiaddr uske startup log se leecher ko dete hai
+	m, _ := seeder.expect(0, regexp.MustCompile(`peer_id=(\S+) addrs="\[(/ip4/127\.0\.0\.1/tcp/\d+/ws)`))
+	at = leecher.mark()
+	leecher.send("connect " + m[2] + "/p2p/" + m[1])
+	leecher.expect(at, regexp.MustCompile(`WebRTC connection established`))
+
+	at = leecher.mark()
+	leecher.send("list")
+	idLine, next := leecher.expect(at, regexp.MustCompile(`ID: ([0-9a-f-]{36})`))
+	leecher.expect(next, regexp.MustCompile(`Name: sample\.txt`))
+	fileID := idLine[1]
+
+	at = leecher.mark()
+	leecher.send("get " + fileID)
+	leecher.expect(at, regexp.MustCompile(`Downloading to`))
+
+	// download background mein poora hota hai; file ke poore bytes aane tak dekhte rehte hai
+	downloaded := filepath.Join(leecherDir, "downloaded_"+fileID)
+	deadline := time.Now().Add(cliTimeout)
+	for {
+		got, err := os.ReadFile(downloaded)
+		if err == nil && bytes.Equal(got, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("downloaded file = %q, %v; want %q", got, err, want)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func TestForwardEventsStopsOnTeardown(t *testing.T) {
+	c := NewClient(nil)
+	p := torrentiumWebRTC.NewMockWebRTCPeer()
+	c.peersMux.Lock()
+	c.startForwardingEventsLocked(p)
+	c.startForwardingEventsLocked(p) // dobara add hone par doosri goroutine nahi banti
+	done := c.eventsDone[p]
+	c.peersMux.Unlock()
+
+	p.SetTransferInfo("payload.bin", 10)
+	select {
+	case ev := <-c.transferEvents:
+		if ev.Type != torrentiumWebRTC.TransferStart {
+			t.Fatalf("forwarded event type = %s, want %s", ev.Type, torrentiumWebRTC.TransferStart)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not forwarded")
+	}
+
+	c.stopForwardingEvents(p)
+	c.stopForwardingEvents(p) // teardown ke dono raste (state callback aur disconnect) call kar sakte hai
+	select {
+	case <-done:
+	default:
+		t.Fatal("done channel still open after teardown")
+	}
+	if _, running := c.eventsDone[p]; running {
+		t.Fatal("transport still registered after teardown")
+	}
+
+	// events channel khula rehta hai, phir bhi goroutine done par ruk jaani chahiye
+	stop := make(chan struct{})
+	returned := make(chan struct{})
+	go func() {
+		c.forwardEvents(p, stop)
+		close(returned)
+	}()
+	close(stop)
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("forwardEvents kept running after done was closed")
+	}
+}
+
+// size 0 stream ka size FILE_START par pata nahi hota; limit paar hote hi receiver ek baar CANCEL bhejta hai aur baaki chunks chhod deta hai
+func TestStreamReceiveCancelsPastLimit(t *testing.T) {
+	c := NewClient(nil)
+	p := torrentiumWebRTC.NewMockWebRTCPeer()
+	p.ReceiveDir = t.TempDir()
+	p.MaxReceiveFileSize = 1024
+
+	c.handleTransportMessage(p, []byte(`{"command":"FILE_START","name":"pipe.bin","size":0}`), true)
+	chunk := make([]byte, 512)
+	for i := 0; i < 4; i++ {
+		c.handleTransportMessage(p, chunk, false)
+	}
+
+	var cancels []map[string]string
+	for _, raw := range p.SentText() {
+		var msg map[string]string
+		if err := json.Unmarshal(raw, &msg); err == nil && msg["command"] == "CANCEL" {
+			cancels = append(cancels, msg)
+		}
+	}
+	if len(cancels) != 1 || cancels[0]["name"] != "pipe.bin" || cancels[0]["reason"] != torrentiumWebRTC.CancelReasonFileTooLarge {
+		t.Fatalf("sent cancels = %v, want one FILE_TOO_LARGE cancel for pipe.bin", cancels)
+	}
+	if p.GetFileWriter() != nil {
+		t.Fatal("file writer still active after the limit was reached")
+	}
+	for {
+		select {
+		case ev := <-c.transferEvents:
+			if ev.Type != torrentiumWebRTC.TransferFailed {
+				continue
+			}
+			return
+		default:
+			t.Fatal("no error event after the stream was cancelled")
+		}
+	}
+}
+
+// control socket ka output command ke apne buffer mein aata hai, aur confirmation poochne wale commands reject hote hai
+func TestRunControlCommand(t *testing.T) {
+	c := NewClient(nil)
+	out, err := c.runControlCommand("help", nil, nil)
+	if err != nil || !strings.Contains(out, "Torrentium Client Commands") {
+		t.Fatalf("help over the control socket = %q, %v", out, err)
+	}
+	for _, cmd := range []string{"exit", "remove", "verify"} {
+		if _, err := c.runControlCommand(cmd, []string{"foo.txt"}, nil); err == nil {
+			t.Fatalf("%s over the control socket succeeded, want an error", cmd)
+		}
+	}
+}
+
+// remote peer ka FILE_START asli handleTransportMessage se chalta hai, phir content ka chunk. accepted batata hai ki
+// handler ne file kholi ya nahi (bahar likhi file root ke walk mein nahi dikhti); files root ke andar bani files hai.
+func receiveViaHandler(t *testing.T, filename, content string) (root string, files []string, accepted bool) {
+	t.Helper()
+	c := NewClient(nil)
+	p := torrentiumWebRTC.NewMockWebRTCPeer()
+	root = t.TempDir()
+	p.ReceiveDir = filepath.Join(root, "recv")
+
+	start, err := json.Marshal(map[string]interface{}{"command": "FILE_START", "name": filename, "size": len(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.handleTransportMessage(p, start, true)
+	if w := p.GetFileWriter(); w != nil {
+		accepted = true
+		c.handleTransportMessage(p, []byte(content), false)
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, files, accepted
+}
+
+func TestFileStartTraversalStaysInReceiveDir(t *testing.T) {
+	for _, name := range []string{"../../../etc/cron.d/evil", "subdir/../../evil", `..\..\evil.bat`, ".."} {
+		t.Run(name, func(t *testing.T) {
+			_, files, accepted := receiveViaHandler(t, name, "payload")
+			if accepted || len(files) != 0 {
+				t.Fatalf("FILE_START %q was accepted (%v) and wrote %v, want it rejected", name, accepted, files)
+			}
+		})
+	}
+	root, files, _ := receiveViaHandler(t, "/etc/passwd", "payload")
+	if len(files) != 1 || files[0] != "recv/passwd" {
+		t.Fatalf("FILE_START /etc/passwd wrote %v, want only recv/passwd", files)
+	}
+	if got, err := os.ReadFile(filepath.Join(root, "recv", "passwd")); err != nil || string(got) != "payload" {
+		t.Fatalf("received content = %q, %v", got, err)
+	}
+}
+
+// Windows sender ka backslash wala naam handler se sirf base name ki file banata hai
+func TestFileStartWindowsPathWritesBaseName(t *testing.T) {
+	root, files, _ := receiveViaHandler(t, `subdir\file.txt`, "hello from windows")
+	if len(files) != 1 || files[0] != "recv/file.txt" {
+		t.Fatalf(`FILE_START subdir\file.txt wrote %v, want only recv/file.txt`, files)
+	}
+	if got, err := os.ReadFile(filepath.Join(root, "recv", "file.txt")); err != nil || string(got) != "hello from windows" {
+		t.Fatalf("received content = %q, %v", got, err)
+	}
+}
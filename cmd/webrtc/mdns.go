@@ -0,0 +1,38 @@
+package main
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
+)
+
+// mDNS service name, sirf isi naam se announce karne wale Torrentium peers milte hai
+const mdnsServiceName = "torrentium"
+
+// mDNS se mile LAN peers ke addresses itni der peerstore mein rehte hai
+const mdnsPeerTTL = time.Hour
+
+// mdnsNotifee mdns.Notifee implement karta hai, mile hue peers client ke peerstore mein jaate hai
+type mdnsNotifee struct {
+	c *Client
+}
+
+// HandlePeerFound har discovered LAN peer ke liye call hota hai
+func (n *mdnsNotifee) HandlePeerFound(info peer.AddrInfo) {
+	if info.ID == n.c.host.ID() {
+		return // khud ka announcement bhi aata hai
+	}
+	n.c.host.Peerstore().AddAddrs(info.ID, info.Addrs, mdnsPeerTTL)
+	logger.Info("Discovered peer via mDNS", "peer", info.ID, "addrs", info.Addrs)
+}
+
+// local network par mDNS discovery start karta hai taaki same LAN ke peers bina multiaddr share kiye mil jaye
+func (c *Client) startMDNS() (mdns.Service, error) {
+	svc := mdns.NewMdnsService(c.host, mdnsServiceName, &mdnsNotifee{c: c})
+	if err := svc.Start(); err != nil {
+		return nil, err
+	}
+	logger.Info("mDNS discovery started", "service", mdnsServiceName)
+	return svc, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+
+	"torrentium/api"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// peer ke connection state changes ko torrentium_webrtc_connection_state aur
+// torrentium_active_connections gauges mein reflect karta hai. Closed peers gauge se hat jaate hai.
+func trackPeerStateMetrics(p *torrentiumWebRTC.WebRTCPeer) {
+	prev := p.State()
+	api.WebRTCConnectionState.WithLabelValues(prev.String()).Inc()
+	if prev == webrtc.PeerConnectionStateConnected {
+		api.ActiveConnections.Inc()
+	}
+
+	var mu sync.Mutex // state changes pion aur data channel dono goroutines se aa sakte hai
+	p.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		mu.Lock()
+		defer mu.Unlock()
+		// Closed gauge mein gina nahi jaata; Reset ke baad peer Closed se dobara Connecting mein jaata hai
+		if prev != webrtc.PeerConnectionStateClosed {
+			api.WebRTCConnectionState.WithLabelValues(prev.String()).Dec()
+		}
+		if prev == webrtc.PeerConnectionStateConnected {
+			api.ActiveConnections.Dec()
+		}
+		if s != webrtc.PeerConnectionStateClosed {
+			api.WebRTCConnectionState.WithLabelValues(s.String()).Inc()
+			if s == webrtc.PeerConnectionStateConnected {
+				api.ActiveConnections.Inc()
+			}
+		}
+		prev = s
+	})
+}
+
+// files_announced gauge ko localFiles ke size se update karta hai
+func (c *Client) updateFilesAnnouncedMetric() {
+	c.filesMux.RLock()
+	n := len(c.localFiles)
+	c.filesMux.RUnlock()
+	api.FilesAnnounced.Set(float64(n))
+}
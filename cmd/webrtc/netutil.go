@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// getLocalIP best non-loopback IPv4 aur IPv6 address dhundhta hai.
+// eth/en se start hone wale interfaces (wired/primary) ko baaki interfaces se zyada preference milti hai.
+// IPv6-only ya IPv4-only network par dusra value empty rehta hai.
+func getLocalIP() (ipv4, ipv6 string, err error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", "", err
+	}
+
+	var v4Preferred, v6Preferred bool
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		preferred := strings.HasPrefix(iface.Name, "eth") || strings.HasPrefix(iface.Name, "en")
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || ipnet.IP.IsLoopback() || ipnet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+
+			if ip4 := ipnet.IP.To4(); ip4 != nil {
+				// pehla mila address rakhte hai, jab tak koi preferred interface ka na mile
+				if ipv4 == "" || (preferred && !v4Preferred) {
+					ipv4, v4Preferred = ip4.String(), preferred
+				}
+			} else if ipv6 == "" || (preferred && !v6Preferred) {
+				ipv6, v6Preferred = ipnet.IP.String(), preferred
+			}
+		}
+	}
+
+	if ipv4 == "" && ipv6 == "" {
+		return "", "", errors.New("no non-loopback IP address found")
+	}
+	return ipv4, ipv6, nil
+}
+
+// empty value ke liye "none" return karta hai (banner mein print karne ke liye)
+func valueOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errPassiveMode passive node par connection banane ya download karne ki koshish par aata hai
+var errPassiveMode = errors.New("this node is in passive mode and only answers incoming connections")
+
+// woh commands jo passive mode mein band hai: yeh khud offer bhejte hai ya kuch download karte hai
+var passiveBlockedCommands = map[string]bool{
+	"connect":     true,
+	"connect-all": true,
+	"get":         true,
+	"get-range":   true,
+	"sync":        true,
+}
+
+// passive mode mein cmd allowed na ho toh informative error return karta hai
+func (c *Client) checkPassive(cmd string) error {
+	if c.passive && passiveBlockedCommands[cmd] {
+		return fmt.Errorf("%s is disabled: %w (restart without --passive to use it)", cmd, errPassiveMode)
+	}
+	return nil
+}
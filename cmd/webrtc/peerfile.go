@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// peers import/export file ki ek entry. multiaddr mein /p2p/<peer_id> ho ya na ho, dono chalte hai.
+type peerAddrEntry struct {
+	PeerID    string `json:"peer_id"`
+	Multiaddr string `json:"multiaddr"`
+}
+
+// `peers import <file>` command: JSON file ke saare valid peer addresses ek saath peerstore mein daalta hai.
+// User ne khud yeh addresses diye hai, isliye connect command jaisa yeh bhi permanently store hote hai.
+func (c *Client) importPeers(out io.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var entries []peerAddrEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	batch := make(map[peer.ID][]ma.Multiaddr)
+	added, failed := 0, 0
+	for i, e := range entries {
+		info, err := e.addrInfo()
+		if err != nil {
+			logger.Warn("Skipping invalid peer entry", "index", i, "peer_id", e.PeerID, "multiaddr", e.Multiaddr, "error", err)
+			failed++
+			continue
+		}
+		if info.ID == c.host.ID() {
+			continue
+		}
+		batch[info.ID] = append(batch[info.ID], info.Addrs...)
+		added++
+	}
+	for id, addrs := range batch {
+		c.host.Peerstore().AddAddrs(id, addrs, peerstore.PermanentAddrTTL)
+	}
+	fmt.Fprintf(out, "Imported %d address(es) for %d peer(s), %d failed to parse.\n", added, len(batch), failed)
+	return nil
+}
+
+// `peers export <file>` command: peerstore ke saare peers ke addresses import wale format mein likhta hai
+func (c *Client) exportPeers(out io.Writer, path string) error {
+	var entries []peerAddrEntry
+	ps := c.host.Peerstore()
+	for _, id := range ps.Peers() {
+		if id == c.host.ID() {
+			continue
+		}
+		for _, addr := range ps.Addrs(id) {
+			entries = append(entries, peerAddrEntry{PeerID: id.String(), Multiaddr: addr.String()})
+		}
+	}
+	if entries == nil {
+		entries = []peerAddrEntry{}
+	}
+
+	err := writeFileAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write peers: %w", err)
+	}
+	fmt.Fprintf(out, "Exported %d peer address(es) to %s.\n", len(entries), path)
+	return nil
+}
+
+// entry ko AddrInfo mein badalta hai. multiaddr mein /p2p nahi hai toh peer_id jod dete hai;
+// dono diye ho toh unka match hona zaroori hai.
+func (e peerAddrEntry) addrInfo() (*peer.AddrInfo, error) {
+	addr, err := ma.NewMultiaddr(e.Multiaddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid multiaddr: %w", err)
+	}
+	if _, err := addr.ValueForProtocol(ma.P_P2P); err != nil {
+		if e.PeerID == "" {
+			return nil, fmt.Errorf("no peer_id and no /p2p in multiaddr")
+		}
+		id, err := peer.Decode(e.PeerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer_id: %w", err)
+		}
+		p2pAddr, err := ma.NewMultiaddr("/p2p/" + id.String())
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Encapsulate(p2pAddr)
+	}
+	info, err := peer.AddrInfoFromP2pAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	if e.PeerID != "" && e.PeerID != info.ID.String() {
+		return nil, fmt.Errorf("peer_id %s does not match multiaddr peer %s", e.PeerID, info.ID)
+	}
+	if len(info.Addrs) == 0 {
+		return nil, fmt.Errorf("multiaddr has no transport address")
+	}
+	return info, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// showPeers libp2p peerstore ke saare peers, unke multiaddrs aur WebRTC state print karta hai.
+// Saath hi tracker se yeh bhi check karta hai ki kaun se peers tracker ko online dikh rahe hain.
+func (c *Client) showPeers(out io.Writer) error {
+	// tracker ke online peers ka set banate hai taaki har peer ke saath dikha sake
+	trackerOnline := make(map[string]bool)
+	trackerPeers, err := c.fetchPeers()
+	if err != nil {
+		logger.Warn("Could not fetch online peers from tracker", "error", err)
+	}
+	for _, p := range trackerPeers {
+		trackerOnline[p.PeerID] = true
+	}
+
+	fmt.Fprintln(out, "\nKnown Peers:")
+	fmt.Fprintln(out, "----------------------------------------")
+	count := 0
+	for _, id := range c.host.Peerstore().Peers() {
+		if id == c.host.ID() {
+			continue // khud ko list mein nahi show karna hai
+		}
+		count++
+
+		state := "none"
+		if p, ok := c.getWebRTCPeer(id); ok {
+			state = p.State().String()
+		} else if _, ok := c.getQuicPeer(id); ok {
+			state = "none (QUIC fallback connected)"
+		}
+
+		fmt.Fprintf(out, "  ID:      %s\n", id)
+		fmt.Fprintf(out, "  WebRTC:  %s\n", state)
+		fmt.Fprintf(out, "  Tracker: %s\n", onlineLabel(trackerOnline[id.String()]))
+		fmt.Fprint(out, "  Addrs:")
+		addrs := c.host.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			fmt.Fprintln(out, " No addresses available")
+		} else {
+			fmt.Fprintln(out)
+			for _, addr := range addrs {
+				fmt.Fprintf(out, "    %s\n", addr)
+			}
+		}
+		fmt.Fprintln(out, "----------------------------------------")
+	}
+	if count == 0 {
+		fmt.Fprintln(out, "No peers in the local peerstore.")
+	}
+
+	// Tracker ke jo peers peerstore mein nahi hai, unko bhi alag se dikhate hai
+	fmt.Fprintln(out, "\nOnline on tracker:")
+	shown := 0
+	for _, p := range trackerPeers {
+		if p.PeerID == c.host.ID().String() {
+			continue
+		}
+		fmt.Fprintf(out, "  %s (%s)\n", p.Name, p.PeerID)
+		shown++
+	}
+	if shown == 0 {
+		fmt.Fprintln(out, "  No other peers online.")
+	}
+	return nil
+}
+
+func onlineLabel(online bool) string {
+	if online {
+		return "online"
+	}
+	return "offline"
+}
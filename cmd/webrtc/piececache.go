@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"torrentium/p2p"
+)
+
+// tracker ke piece cache se is node ke pehle verify kiye pieces laata hai. Cache na mile toh nil,
+// tab saare pieces disk se verify hote hai.
+func (c *Client) fetchVerifiedPieces(fileHash string, modTime time.Time) []int {
+	resp, err := c.trackerRequest("GET_VERIFIED_PIECES", p2p.PieceCachePayload{PeerID: c.host.ID().String(), FileHash: fileHash, ModTime: modTime})
+	if err != nil {
+		logger.Debug("Piece cache unavailable", "hash", fileHash, "error", err)
+		return nil
+	}
+	var pieces []int
+	if err := json.Unmarshal(resp.Payload, &pieces); err != nil {
+		logger.Debug("Invalid piece cache response", "hash", fileHash, "error", err)
+		return nil
+	}
+	if len(pieces) > 0 {
+		logger.Debug("Skipping cached pieces", "hash", fileHash, "pieces", len(pieces))
+	}
+	return pieces
+}
+
+// abhi verify hue pieces (total mein se jo cached nahi the) tracker ke piece cache mein likhta hai.
+// Response PIECES_MARKED background handler mein sirf log hota hai.
+func (c *Client) markPiecesVerified(fileHash string, modTime time.Time, total int, cached []int) {
+	skip := make(map[int]bool, len(cached))
+	for _, idx := range cached {
+		skip[idx] = true
+	}
+	var pieces []int
+	for idx := 0; idx < total; idx++ {
+		if !skip[idx] {
+			pieces = append(pieces, idx)
+		}
+	}
+	if len(pieces) == 0 {
+		return
+	}
+	payload, _ := json.Marshal(p2p.PieceCachePayload{PeerID: c.host.ID().String(), FileHash: fileHash, ModTime: modTime, Pieces: pieces})
+	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "MARK_PIECES_VERIFIED", Payload: payload}); err != nil {
+		logger.Warn("Failed to update piece cache", "hash", fileHash, "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"torrentium/p2p"
+)
+
+// `pipe <peer_id> <remote_filename>` command: input ke bytes EOF tak peer ko remote_filename naam se stream karta hai,
+// jaise `tar cf - . | torrentium pipe <peer> backup.tar`. Client ka apna stdin command prompt hai, isliye input
+// sirf control socket (cmd/torrentium) se aata hai.
+func (c *Client) pipeInput(out io.Writer, idStr, name string, input io.Reader) error {
+	if input == nil {
+		return errors.New("pipe reads from the torrentium CLI: run `torrentium pipe <peer_id> <remote_filename>` with data on its stdin")
+	}
+	p, id, err := c.pickTransport(idStr)
+	if err != nil {
+		return err
+	}
+
+	ctx, done := c.startUpload(p)
+	defer done()
+	counter := &countingReader{r: input}
+	if err := p.SendStream(ctx, counter, name); err != nil {
+		return fmt.Errorf("failed to stream %s: %w", name, err)
+	}
+	fmt.Fprintf(out, "Sent %d bytes as %s to %s\n", counter.n, name, p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	return nil
+}
+
+// padhe gaye bytes ginta hai
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"torrentium/torrentfile"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// previewFile `add --dry-run` ke liye: woh hash, size aur pieces print karta hai jo announce par tracker ko jaate,
+// lekin tracker ko kuch nahi bhejta. .torrent file temp directory mein banti hai aur end mein delete ho jaati hai.
+func (c *Client) previewFile(out io.Writer, filePath string) error {
+	fileHash, fileSize, err := calculateFileHash(filePath)
+	if err != nil {
+		return err
+	}
+	meta, err := torrentfile.NewTorrentMeta(filePath, c.pieceLength)
+	if err != nil {
+		return fmt.Errorf("failed to build torrent metadata: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))+"-*.torrent")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+	if err := torrentfile.WriteTorrentFile(meta, tmpPath); err != nil {
+		return fmt.Errorf("failed to write torrent file: %w", err)
+	}
+
+	infoHash := "-"
+	if ih, err := torrentfile.InfoHash(meta); err == nil {
+		infoHash = hex.EncodeToString(ih[:])
+	}
+
+	fmt.Fprintf(out, "Dry run for %s (nothing was announced):\n", filePath)
+	fmt.Fprintf(out, "  Hash:         %s\n", fileHash)
+	fmt.Fprintf(out, "  Size:         %s (%d bytes)\n", torrentiumWebRTC.FormatFileSizeIEC(fileSize), fileSize)
+	fmt.Fprintf(out, "  Pieces:       %d x %s\n", len(meta.PieceHashes()), torrentiumWebRTC.FormatFileSizeIEC(meta.Info.PieceLength))
+	fmt.Fprintf(out, "  Info hash:    %s\n", infoHash)
+	fmt.Fprintf(out, "  Torrent file: %s (deleted)\n", tmpPath)
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"torrentium/api"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// REQUEST_RANGE ka jawab: shared file ke [start, end) bytes bhejta hai
+func (c *Client) sendFileRange(p FileTransport, fileID uuid.UUID, start, end int64) {
+	filePath, ok := c.sharedFilePath(fileID)
+	if !ok {
+		logger.Warn("Received range request for a file that is not shared", "file_id", fileID)
+		p.SendTextData(map[string]string{"error": "File not found"})
+		return
+	}
+	if !c.requesterAllowed(p, fileID) {
+		logger.Warn("Refused range request from peer not in the allow list", "peer", transportPeerID(p), "file", filepath.Base(filePath))
+		p.SendTextData(torrentiumWebRTC.AccessDeniedMessage(filepath.Base(filePath)))
+		return
+	}
+
+	logger.Info("Sending file range", "file", filepath.Base(filePath), "start", start, "end", end)
+	ctx, done := c.startUpload(p)
+	defer done()
+	if err := p.SendFileRange(ctx, filePath, start, end); err != nil {
+		logger.Error("Error sending file range", "file", filePath, "error", err)
+		return
+	}
+	api.BytesUploaded.Add(float64(end - start))
+	c.reportUpload(end - start)
+}
+
+// `get-range` command: connected peer se file ka ek byte range maangta hai, jaise adhoore download ko resume karne ke liye.
+// Bytes receive directory mein file ke usi offset par likhe jaate hai.
+func (c *Client) getRange(out io.Writer, args []string) error {
+	if len(args) < 3 || len(args) > 4 {
+		return errors.New("usage: get-range <file_id> <start> <end> [peer_id]")
+	}
+	fileID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid file ID format: %w", err)
+	}
+	start, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if start < 0 || end <= start {
+		return errors.New("range must satisfy 0 <= start < end")
+	}
+
+	idStr := ""
+	if len(args) == 4 {
+		idStr = args[3]
+	}
+	p, id, err := c.pickTransport(idStr)
+	if err != nil {
+		return err
+	}
+	if err := p.RequestFileRange(fileID.String(), start, end); err != nil {
+		return fmt.Errorf("failed to request range: %w", err)
+	}
+	fmt.Fprintf(out, "Requested bytes %d-%d of %s from %s\n", start, end, fileID, id)
+	return nil
+}
+
+// RANGE_END: range ke saare bytes aa gaye, file close karte hai. Connection khula rehta hai taaki aur ranges maange ja sake.
+func (c *Client) handleRangeEnd(p FileTransport, cmd torrentiumWebRTC.RangeEndCommand) {
+	p.CompleteTransfer()
+	if writer := p.GetFileWriter(); writer != nil {
+		if err := writer.Close(); err != nil {
+			logger.Warn("Failed to finish received range", "name", cmd.Filename, "error", err)
+		}
+		p.SetFileWriter(nil)
+	}
+	logger.Info("Received file range", "file", cmd.Filename, "start", cmd.Start, "end", cmd.End)
+}
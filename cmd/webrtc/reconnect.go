@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// connection toot jaane par kitni baar reconnect try karna hai
+const maxReconnectAttempts = 3
+
+// reconnectPeer WebRTC connection fail/disconnect hone par peer ko Reset karke libp2p signaling se naya connection
+// banane ki koshish karta hai. Peer object wahi rehta hai, isliye map, callbacks aur HMAC key nahi badalte. Dono side ek saath offer na bheje isliye sirf chhote peer ID wala side reconnect karta hai.
+// Teeno attempts fail hone par peer ko map se hata kar tracker ko offline report kar dete hai.
+// reconnecting[id] state callback ke andar hi set hota hai (attempts goroutine mein chalte hai), taaki baad mein aaya
+// Closed state peer ko map se na hata de.
+func (c *Client) reconnectPeer(id peer.ID, old *torrentiumWebRTC.WebRTCPeer) {
+	// passive node khud offer nahi bhejta, remote peer hi reconnect karega
+	if id == "" || c.passive || c.host.ID().String() > id.String() {
+		return
+	}
+
+	c.peersMux.Lock()
+	if c.reconnecting[id] || c.webRTCPeers[id] != old {
+		// pehle se reconnect chal raha hai, ya peer already replace ho chuka hai
+		c.peersMux.Unlock()
+		return
+	}
+	c.reconnecting[id] = true
+	c.peersMux.Unlock()
+
+	go c.reconnectAttempts(id, old)
+}
+
+// reconnectPeer ke attempts; reconnecting[id] pehle se set hai aur khatam hone par hat jaata hai
+func (c *Client) reconnectAttempts(id peer.ID, old *torrentiumWebRTC.WebRTCPeer) {
+	defer func() {
+		c.peersMux.Lock()
+		delete(c.reconnecting, id)
+		c.peersMux.Unlock()
+	}()
+
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+
+		// Disconnected state kabhi kabhi khud hi wapas Connected ho jaati hai
+		if old.IsConnected() {
+			logger.Info("WebRTC connection recovered on its own", "peer", id)
+			return
+		}
+		// beech mein remote peer ne naya offer bhej diya ho toh hume kuch nahi karna
+		if current, ok := c.getWebRTCPeer(id); ok && current != old {
+			return
+		}
+
+		logger.Info("Reconnecting to peer", "peer", id, "attempt", attempt, "max_attempts", maxReconnectAttempts)
+		if err := old.Reset(); err != nil {
+			logger.Warn("Reconnect attempt failed", "peer", id, "attempt", attempt, "error", err)
+			continue
+		}
+		if err := c.signalWebRTCOffer(old, id); err != nil {
+			logger.Warn("Reconnect attempt failed", "peer", id, "attempt", attempt, "error", err)
+			continue
+		}
+		logger.Info("Reconnected to peer", "peer", id)
+		return
+	}
+
+	logger.Warn("Giving up on peer after reconnect attempts", "peer", id, "attempts", maxReconnectAttempts)
+	c.closeWebRTCPeer(id, old)
+
+	// response PEER_OFFLINE_ACK background handler mein sirf log hota hai
+	payload, _ := json.Marshal(p2p.GetPeerByIDPayload{PeerID: id.String()})
+	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "REPORT_PEER_OFFLINE", Payload: payload}); err != nil {
+		logger.Error("Failed to report peer offline", "peer", id, "error", err)
+	}
+}
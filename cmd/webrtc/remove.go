@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// removeFile is node ka file announcement tracker ke database se hata deta hai.
+// Pehle filename se hash resolve hota hai, phir user confirm kare tabhi entry delete hoti hai.
+func (c *Client) removeFile(out io.Writer, filename string) error {
+	filename = filepath.Base(filename)
+	resp, err := c.trackerRequest("GET_FILE_BY_NAME", p2p.GetFileByNamePayload{Filename: filename})
+	if err != nil {
+		return err
+	}
+	var record db.File
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		return fmt.Errorf("failed to parse file info: %w", err)
+	}
+
+	if ans := c.ask(out, fmt.Sprintf("Remove announcement of %s (hash %s)? [y/N] ", filename, hashPrefix(record.FileHash))); ans != "y" && ans != "yes" {
+		fmt.Fprintln(out, "No changes made.")
+		return nil
+	}
+
+	remaining, err := c.retractAnnouncement(record)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Removed announcement of %s.\n", filename)
+	warnRemainingPeers(out, remaining)
+	return nil
+}
+
+// tracker se is node ka announcement REMOVE_FILE se hatata hai aur file ko local maps se bhi hata deta hai.
+// Return value batati hai ki kitne aur peers abhi bhi file announce karte hai.
+func (c *Client) retractAnnouncement(record db.File) (int, error) {
+	resp, err := c.trackerRequest("REMOVE_FILE", p2p.RemoveFilePayload{
+		FileHash: record.FileHash,
+		PeerID:   c.host.ID().String(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	var ack p2p.RemoveFileAckPayload
+	if err := json.Unmarshal(resp.Payload, &ack); err != nil {
+		return 0, fmt.Errorf("failed to parse tracker response: %w", err)
+	}
+
+	// local maps se bhi hata dete hai taaki file serve na ho
+	c.filesMux.Lock()
+	delete(c.sharingFiles, record.ID)
+	delete(c.localFiles, record.FileHash)
+	c.filesMux.Unlock()
+	c.updateFilesAnnouncedMetric()
+	return ack.RemainingPeers, nil
+}
+
+func warnRemainingPeers(out io.Writer, remaining int) {
+	if remaining > 0 {
+		fmt.Fprintf(out, "⚠️  %d other peer(s) still announce this file, it remains available from them.\n", remaining)
+	}
+}
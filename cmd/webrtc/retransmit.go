@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"torrentium/db"
+	"torrentium/torrentfile"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// pieceRepairTimeout saare kharab pieces dobara aane ka maximum intezaar hai
+const pieceRepairTimeout = 2 * time.Minute
+
+// ek received file jiske kharab pieces NACK se dobara maange ja rahe hai.
+// Bina data channel pool ke ek transport par ek hi piece chalta hai, taaki PIECE_DATA ke chunks aapas mein na mile;
+// pool ho toh har piece apne pool channel par aata hai aur window jitne pieces ek saath maange jaate hai.
+type pieceRepair struct {
+	fileHash    string
+	name        string
+	path        string
+	pieceLength int64
+	expected    map[int][]byte // piece -> tracker ka SHA-1 hash
+	queue       []int          // kharab pieces jinka NACK abhi nahi gaya
+	inFlight    map[int]bool   // jin pieces ka NACK ja chuka hai aur data ka intezaar hai
+	window      int            // ek saath kitne pieces maange ja sakte hai
+	attempts    map[int]int    // piece -> kitni baar NACK bheja
+	writerAt    io.WriterAt    // pool ho toh pieces isi pre-allocated file mein WriteAt se likhe jaate hai, warna nil
+	done        chan error
+}
+
+// received file ke kharab pieces tracker ke piece hashes se dhoondh kar p se dobara maangta hai.
+// Har piece ko MaxPieceRetransmits baar tak maanga jaata hai; koi piece phir bhi kharab rahe toh error aata hai.
+func (c *Client) repairReceivedFile(p FileTransport, path, name string, record db.File) error {
+	first, err := c.downloads.pieceInfo(record.FileHash, 0)
+	if err != nil {
+		return fmt.Errorf("no piece hashes from tracker: %w", err)
+	}
+	if first.PieceLength <= 0 {
+		return fmt.Errorf("tracker has no piece index for %s", record.FileHash)
+	}
+	// sender ne zyada bytes bheje ho toh woh piece hashes mein kabhi match nahi honge
+	if err := os.Truncate(path, record.FileSize); err != nil {
+		return err
+	}
+
+	repair := &pieceRepair{
+		fileHash:    record.FileHash,
+		name:        name,
+		path:        path,
+		pieceLength: first.PieceLength,
+		expected:    make(map[int][]byte),
+		inFlight:    make(map[int]bool),
+		window:      1,
+		attempts:    make(map[int]int),
+		done:        make(chan error, 1),
+	}
+	for idx := 0; idx < torrentfile.PieceCount(record.FileSize, first.PieceLength); idx++ {
+		want, err := c.downloads.pieceHash(record.FileHash, idx)
+		if err != nil {
+			return fmt.Errorf("no hash for piece %d: %w", idx, err)
+		}
+		ok, err := repair.pieceMatches(idx, want)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Warn("Piece hash mismatch", "file", name, "piece", idx)
+			repair.expected[idx] = want
+			repair.queue = append(repair.queue, idx)
+		}
+	}
+	if len(repair.queue) == 0 {
+		return errors.New("file hash mismatch but every piece matches the tracker")
+	}
+
+	// pool channels par har piece alag chalta hai, isliye utne pieces ek saath maang sakte hai
+	if wp, ok := p.(*torrentiumWebRTC.WebRTCPeer); ok && wp.PoolSize() > 0 && !wp.HMACEnabled() {
+		repair.window = wp.PoolSize()
+		// path ReceiveFile ne ReceiveDir mein banaya tha; wahi file ho tabhi pieces uske WriteAt se likhte hai
+		if target, err := torrentiumWebRTC.SafeReceivePath(wp.ReceiveDir, filepath.Base(path)); err == nil && target == path {
+			if err := wp.SetFileWriterAt(filepath.Base(path), record.FileSize); err != nil {
+				return err
+			}
+			defer wp.CloseFileWriterAt()
+			repair.writerAt = wp
+		}
+	}
+
+	c.downloadsMux.Lock()
+	c.repairs[p] = repair
+	c.downloadsMux.Unlock()
+	defer func() {
+		c.downloadsMux.Lock()
+		delete(c.repairs, p)
+		c.downloadsMux.Unlock()
+	}()
+
+	logger.Info("Requesting corrupt pieces again", "file", name, "pieces", repair.queue)
+	c.downloadsMux.Lock()
+	err = c.requestPieces(p, repair)
+	c.downloadsMux.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-repair.done:
+		if err != nil {
+			return err
+		}
+	case <-time.After(pieceRepairTimeout):
+		return errors.New("timed out waiting for retransmitted pieces")
+	}
+
+	match, err := fileMatchesHash(path, record.FileHash)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return errors.New("file hash still does not match after retransmission")
+	}
+	return nil
+}
+
+// window bharne tak queue ke pieces ke NACK bhejta hai. downloadsMux lock hona chahiye.
+func (c *Client) requestPieces(p FileTransport, r *pieceRepair) error {
+	for len(r.inFlight) < r.window && len(r.queue) > 0 {
+		idx := r.queue[0]
+		if r.attempts[idx] >= torrentiumWebRTC.MaxPieceRetransmits {
+			return fmt.Errorf("piece %d is still corrupt after %d retransmissions", idx, r.attempts[idx])
+		}
+		r.attempts[idx]++
+		if err := p.SendNack(r.fileHash, r.name, idx, r.pieceLength); err != nil {
+			return fmt.Errorf("failed to send NACK for piece %d: %w", idx, err)
+		}
+		r.queue = r.queue[1:]
+		r.inFlight[idx] = true
+	}
+	return nil
+}
+
+// PIECE_DATA ka piece maanga gaya tha aur uska offset/size sahi hai, yeh check karke repair return karta hai
+func (c *Client) requestedPiece(p FileTransport, cmd torrentiumWebRTC.PieceDataCommand) (*pieceRepair, error) {
+	c.downloadsMux.RLock()
+	r, ok := c.repairs[p]
+	requested := ok && r.fileHash == cmd.FileHash && r.inFlight[cmd.Piece]
+	c.downloadsMux.RUnlock()
+	if !requested {
+		return nil, fmt.Errorf("piece %d of %s was not requested", cmd.Piece, cmd.Filename)
+	}
+	if cmd.Offset != int64(cmd.Piece)*r.pieceLength || cmd.Size > r.pieceLength {
+		return nil, fmt.Errorf("piece %d has wrong offset %d or size %d", cmd.Piece, cmd.Offset, cmd.Size)
+	}
+	return r, nil
+}
+
+// PIECE_DATA ke liye file piece ke offset par kholta hai. Piece maanga na gaya ho toh error.
+func (c *Client) openPieceFile(p FileTransport, cmd torrentiumWebRTC.PieceDataCommand) (*os.File, *pieceRepair, error) {
+	r, err := c.requestedPiece(p, cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// path humne khud banaya tha, isliye remote ka naam use nahi karte
+	f, err := os.OpenFile(r.path, os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, r, err
+	}
+	if _, err := f.Seek(cmd.Offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, r, err
+	}
+	return f, r, nil
+}
+
+// PIECE_DATA: retransmit kiya gaya piece aa raha hai, uske bytes file mein piece ke offset par likhte hai
+func (c *Client) handlePieceData(p FileTransport, cmd torrentiumWebRTC.PieceDataCommand) {
+	f, r, err := c.openPieceFile(p, cmd)
+	if err != nil {
+		logger.Warn("Rejected retransmitted piece", "file", cmd.Filename, "piece", cmd.Piece, "error", err)
+		if r != nil {
+			c.failPieceRepair(p, err)
+		}
+		return
+	}
+	p.SetFileWriter(f)
+	p.SetTransferInfo(filepath.Base(r.path), cmd.Size)
+}
+
+// PIECE_END: piece ka writer band karke uska hash check karte hai
+func (c *Client) handlePieceEnd(p FileTransport, cmd torrentiumWebRTC.PieceEndCommand) {
+	p.CompleteTransfer()
+	var writeErr error
+	if writer := p.GetFileWriter(); writer != nil {
+		writeErr = writer.Close()
+		p.SetFileWriter(nil)
+	}
+	c.pieceReceived(p, cmd.FileHash, cmd.Piece, writeErr)
+}
+
+// pool channels par aaye pieces ke handlers. Pieces repair ki pre-allocated file mein apne offset par WriteAt se
+// likhte hai (woh na ho toh har piece apna file handle kholta hai), isliye woh ek saath likh sakte hai.
+func (c *Client) pooledPieceHandlers(p *torrentiumWebRTC.WebRTCPeer) torrentiumWebRTC.PieceHandlers {
+	return torrentiumWebRTC.PieceHandlers{
+		Open: func(cmd torrentiumWebRTC.PieceDataCommand) (io.WriteCloser, error) {
+			r, err := c.requestedPiece(p, cmd)
+			if err != nil {
+				return nil, err
+			}
+			if r.writerAt != nil {
+				return offsetWriteCloser{io.NewOffsetWriter(r.writerAt, cmd.Offset)}, nil
+			}
+			f, _, err := c.openPieceFile(p, cmd)
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		},
+		Done: func(cmd torrentiumWebRTC.PieceEndCommand, err error) {
+			c.pieceReceived(p, cmd.FileHash, cmd.Piece, err)
+		},
+	}
+}
+
+// poora aaya piece verify karta hai: sahi ho toh window mein agla kharab piece maangte hai, warna yehi piece dobara
+func (c *Client) pieceReceived(p FileTransport, fileHash string, piece int, writeErr error) {
+	c.downloadsMux.Lock()
+	defer c.downloadsMux.Unlock()
+	r, ok := c.repairs[p]
+	if !ok || r.fileHash != fileHash || !r.inFlight[piece] {
+		return
+	}
+	delete(r.inFlight, piece)
+
+	ok = false
+	if writeErr != nil {
+		logger.Warn("Failed to finish received piece", "file", r.name, "piece", piece, "error", writeErr)
+	} else {
+		var err error
+		if ok, err = r.pieceMatches(piece, r.expected[piece]); err != nil {
+			r.finish(err)
+			return
+		}
+	}
+	if ok {
+		logger.Info("Retransmitted piece verified", "file", r.name, "piece", piece)
+		if len(r.queue) == 0 && len(r.inFlight) == 0 {
+			r.finish(nil)
+			return
+		}
+	} else {
+		logger.Warn("Retransmitted piece is still corrupt", "file", r.name, "piece", piece, "attempt", r.attempts[piece])
+		r.queue = append([]int{piece}, r.queue...)
+	}
+	if err := c.requestPieces(p, r); err != nil {
+		r.finish(err)
+	}
+}
+
+// p par chal rahi piece repair (agar hai) ko err ke saath fail karta hai
+func (c *Client) failPieceRepair(p FileTransport, err error) {
+	c.downloadsMux.RLock()
+	r, ok := c.repairs[p]
+	c.downloadsMux.RUnlock()
+	if ok {
+		r.finish(err)
+	}
+}
+
+// NACK: receiver ko humari bheji file ka ek piece kharab mila, woh piece disk se padh kar dobara bhejte hai
+func (c *Client) sendPiece(p FileTransport, cmd torrentiumWebRTC.NackCommand) {
+	filePath, ok := c.sharedPathByHash(cmd.FileHash)
+	if !ok {
+		logger.Warn("Received NACK for a file that is not shared", "hash", cmd.FileHash, "piece", cmd.Piece)
+		p.SendTextData(map[string]string{"error": "File not found"})
+		return
+	}
+
+	logger.Info("Retransmitting piece", "file", filepath.Base(filePath), "piece", cmd.Piece)
+	ctx, done := c.startUpload(p)
+	defer done()
+	if err := p.SendPiece(ctx, filePath, cmd.FileHash, cmd.Piece, cmd.PieceLength); err != nil {
+		logger.Error("Error retransmitting piece", "file", filePath, "piece", cmd.Piece, "error", err)
+	}
+}
+
+// apni announced file ka local path uske hash se dhoondhta hai
+func (c *Client) sharedPathByHash(fileHash string) (string, bool) {
+	c.filesMux.RLock()
+	defer c.filesMux.RUnlock()
+	rec, ok := c.localFiles[fileHash]
+	if !ok {
+		return "", false
+	}
+	for _, path := range c.sharingFiles {
+		if filepath.Base(path) == rec.Name {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// file ke piece idx ke bytes ka SHA-1 want se match karta hai ya nahi; file chhoti ho toh piece kharab hai
+func (r *pieceRepair) pieceMatches(idx int, want []byte) (bool, error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, r.pieceLength)
+	n, err := f.ReadAt(buf, int64(idx)*r.pieceLength)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	sum := sha1.Sum(buf[:n])
+	return bytes.Equal(sum[:], want), nil
+}
+
+// repair ka result ek hi baar deliver hota hai
+func (r *pieceRepair) finish(err error) {
+	select {
+	case r.done <- err:
+	default:
+	}
+}
+
+// WriterAt ke ek offset se likhne wala writer; file repair ke end mein band hoti hai, isliye Close kuch nahi karta
+type offsetWriteCloser struct {
+	*io.OffsetWriter
+}
+
+func (offsetWriteCloser) Close() error { return nil }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// tracker se sabse zyada upload karne wale peers laata hai
+func (c *Client) fetchTopSeeders(ctx context.Context, limit int) ([]db.SeederRecord, error) {
+	resp, err := c.trackerRequestContext(ctx, "TOP_SEEDERS", p2p.TopSeedersPayload{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	var seeders []db.SeederRecord
+	if err := json.Unmarshal(resp.Payload, &seeders); err != nil {
+		return nil, err
+	}
+	return seeders, nil
+}
+
+// tracker se network ke totals (files, peers, transferred bytes) laata hai
+func (c *Client) fetchNetworkStats(ctx context.Context) (*db.NetworkStats, error) {
+	resp, err := c.trackerRequestContext(ctx, "NETWORK_STATS", nil)
+	if err != nil {
+		return nil, err
+	}
+	var stats db.NetworkStats
+	if err := json.Unmarshal(resp.Payload, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// `stats` command: tracker ke network totals print karta hai
+func (c *Client) showNetworkStats(out io.Writer) error {
+	s, err := c.fetchNetworkStats(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nNetwork stats:")
+	fmt.Fprintf(out, "  Files:             %d (%s)\n", s.TotalFiles, torrentiumWebRTC.FormatFileSizeIEC(s.TotalSize))
+	fmt.Fprintf(out, "  Peers:             %d online, %d offline\n", s.OnlinePeers, s.OfflinePeers)
+	fmt.Fprintf(out, "  Bytes transferred: %s\n", torrentiumWebRTC.FormatFileSizeIEC(s.TotalBytesTransferred))
+	return nil
+}
+
+// `top-seeders` command: network ke top seeders uploaded bytes ke order mein print karta hai
+func (c *Client) topSeeders(out io.Writer, limit int) error {
+	seeders, err := c.fetchTopSeeders(context.Background(), limit)
+	if err != nil {
+		return err
+	}
+	if len(seeders) == 0 {
+		fmt.Fprintln(out, "No upload stats recorded yet.")
+		return nil
+	}
+	fmt.Fprintln(out, "\nTop seeders:")
+	for i, s := range seeders {
+		fmt.Fprintf(out, "  %2d. %s\n      Uploaded: %s | Files shared: %d | Last seen: %s\n",
+			i+1, p2p.FormatPeerIDString(s.PeerID, p2p.ShortPeerIDLength), torrentiumWebRTC.FormatFileSizeIEC(s.BytesUploaded), s.FilesShared, s.LastSeen.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// direct (WebRTC/QUIC) transfer ke baad tracker ko uploaded bytes batata hai.
+// Response UPLOAD_RECORDED background handler mein sirf log hota hai.
+func (c *Client) reportUpload(bytes int64) {
+	payload, _ := json.Marshal(p2p.ReportUploadPayload{PeerID: c.host.ID().String(), Bytes: bytes})
+	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "REPORT_UPLOAD", Payload: payload}); err != nil {
+		logger.Warn("Failed to report upload to tracker", "error", err)
+	}
+}
+
+// khatam hua (complete/error/corrupt) transfer tracker ke audit log ke liye report karta hai.
+// Response TRANSFER_RECORDED background handler mein sirf log hota hai.
+func (c *Client) reportTransfer(ev torrentiumWebRTC.TransferEvent) {
+	if c.trackerConn == nil {
+		return
+	}
+	payload, _ := json.Marshal(p2p.ReportTransferPayload{
+		PeerID:       c.host.ID().String(),
+		RemotePeerID: ev.PeerID,
+		Filename:     ev.Filename,
+		Bytes:        ev.BytesDone,
+		Outcome:      ev.Type,
+	})
+	if err := c.trackerConn.WriteJSON(p2p.Message{Command: "REPORT_TRANSFER", Payload: payload}); err != nil {
+		logger.Warn("Failed to report transfer to tracker", "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/p2p"
+)
+
+// sendDirectory ek directory ko tar.gz archive ki tarah connected peer ko bhejta hai.
+// peer ID na diya ho toh sirf ek connected peer hone par usi ko bhejte hai.
+func (c *Client) sendDirectory(out io.Writer, dirPath, idStr string) error {
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dirPath)
+	}
+
+	p, id, err := c.pickTransport(idStr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Sending directory %s to %s...\n", dirPath, p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	if err := p.SendDirectory(context.Background(), dirPath); err != nil {
+		return fmt.Errorf("failed to send directory: %w", err)
+	}
+	fmt.Fprintf(out, "Sent directory %s\n", dirPath)
+	return nil
+}
+
+// idStr wale peer ka transport deta hai (WebRTC pehle, phir QUIC). idStr empty ho toh exactly ek connected peer hona chahiye.
+func (c *Client) pickTransport(idStr string) (FileTransport, peer.ID, error) {
+	c.peersMux.RLock()
+	defer c.peersMux.RUnlock()
+
+	if idStr == "" {
+		if len(c.webRTCPeers)+len(c.quicPeers) != 1 {
+			return nil, "", errors.New("connected to zero or several peers, pass a peer ID")
+		}
+		for id, p := range c.webRTCPeers {
+			return p, id, nil
+		}
+		for id, t := range c.quicPeers {
+			return t, id, nil
+		}
+	}
+
+	id, err := peer.Decode(idStr)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if p, ok := c.webRTCPeers[id]; ok {
+		return p, id, nil
+	}
+	if t, ok := c.quicPeers[id]; ok {
+		return t, id, nil
+	}
+	return nil, "", fmt.Errorf("no WebRTC or QUIC connection to %s", id)
+}
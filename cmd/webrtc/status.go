@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/api"
+	"torrentium/db"
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// statsSampleInterval par har WebRTC peer ke stats debug level par log hote hai
+const statsSampleInterval = 5 * time.Second
+
+// collectStatus har active connection ka state aur WebRTC stats ikattha karta hai, CLI aur /status dono ke liye
+func (c *Client) collectStatus() api.StatusReport {
+	c.peersMux.RLock()
+	webrtcPeers := make(map[peer.ID]*torrentiumWebRTC.WebRTCPeer, len(c.webRTCPeers))
+	for id, p := range c.webRTCPeers {
+		webrtcPeers[id] = p
+	}
+	quicIDs := make([]peer.ID, 0, len(c.quicPeers))
+	for id := range c.quicPeers {
+		quicIDs = append(quicIDs, id)
+	}
+	c.peersMux.RUnlock()
+
+	// GetStats lock ke bahar call karte hai, kyunki woh pion ke andar block kar sakta hai
+	report := api.StatusReport{PeerID: c.host.ID().String()}
+	for id, p := range webrtcPeers {
+		status := api.PeerStatus{
+			ID:        id.String(),
+			Transport: "webrtc",
+			State:     p.State().String(),
+			ICEState:  p.ICEConnectionState().String(),
+			Gathering: p.ICEGatheringState().String(),
+		}
+		if addr, err := p.ListenAddr(); err != nil {
+			logger.Debug("No local ICE address", "peer", id, "error", err)
+		} else {
+			status.LocalAddr = addr.String()
+		}
+		channel := p.DataChannelStats()
+		status.Channel = &channel
+		if stats, err := p.Stats(); err != nil {
+			logger.Debug("Failed to get WebRTC stats", "peer", id, "error", err)
+		} else {
+			status.Stats = &stats
+		}
+		status.Transfers = p.PendingTransfers()
+		report.Peers = append(report.Peers, status)
+	}
+	for _, id := range quicIDs {
+		report.Peers = append(report.Peers, api.PeerStatus{ID: id.String(), Transport: "quic", State: "connected"})
+	}
+	return report
+}
+
+// statsLoop har interval par saare WebRTC peers ke stats debug level par log karta hai
+func (c *Client) statsLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, s := range c.collectStatus().Peers {
+			if s.Stats == nil {
+				continue
+			}
+			logger.Debug("WebRTC stats",
+				"peer", s.ID,
+				"state", s.State,
+				"rtt_ms", s.Stats.RTTMs,
+				"bytes_sent", s.Stats.BytesSent,
+				"bytes_received", s.Stats.BytesReceived,
+				"packets_lost", s.Stats.PacketsLost,
+				"data_channel", s.Stats.DataChannelState,
+			)
+		}
+	}
+}
+
+// showStatus har active connection ka state, WebRTC stats aur tracker database mein store peer ki info dikhata hai
+func (c *Client) showStatus(out io.Writer) error {
+	fmt.Fprintf(out, "\nPeer ID: %s\n", c.host.ID())
+	fmt.Fprintln(out, "Active Connections:")
+	fmt.Fprintln(out, "----------------------------------------")
+
+	report := c.collectStatus()
+	if len(report.Peers) == 0 {
+		fmt.Fprintln(out, "No active connections.")
+		return nil
+	}
+
+	for _, s := range report.Peers {
+		id := s.ID
+		fmt.Fprintf(out, "  ID:        %s\n", p2p.FormatPeerIDString(id, p2p.ShortPeerIDLength))
+		if s.Transport == "quic" {
+			fmt.Fprintf(out, "  QUIC:      %s\n", s.State)
+		} else {
+			fmt.Fprintf(out, "  WebRTC:    %s\n", s.State)
+			fmt.Fprintf(out, "  ICE:       %s (gathering %s)\n", s.ICEState, s.Gathering)
+			fmt.Fprintf(out, "  Local:     %s\n", valueOrNone(s.LocalAddr))
+		}
+		if s.Stats != nil {
+			fmt.Fprintf(out, "  RTT:       %.1f ms\n", s.Stats.RTTMs)
+			fmt.Fprintf(out, "  Sent:      %s\n", torrentiumWebRTC.FormatFileSizeIEC(int64(s.Stats.BytesSent)))
+			fmt.Fprintf(out, "  Received:  %s\n", torrentiumWebRTC.FormatFileSizeIEC(int64(s.Stats.BytesReceived)))
+			fmt.Fprintf(out, "  Lost:      %d packets\n", s.Stats.PacketsLost)
+			fmt.Fprintf(out, "  Channel:   %s\n", s.Stats.DataChannelState)
+		}
+		if ch := s.Channel; ch != nil && ch.Label != "" {
+			fmt.Fprintf(out, "  Data:      %q, %d msgs sent (%s), %d msgs received (%s), %s buffered\n", ch.Label,
+				ch.MessagesSent, torrentiumWebRTC.FormatFileSizeIEC(int64(ch.BytesSent)),
+				ch.MessagesReceived, torrentiumWebRTC.FormatFileSizeIEC(int64(ch.BytesReceived)),
+				torrentiumWebRTC.FormatFileSizeIEC(int64(ch.BufferedAmount)))
+		}
+
+		record, err := c.fetchPeerRecord(id)
+		if err != nil {
+			logger.Warn("Could not fetch tracker record", "peer", id, "error", err)
+		} else {
+			fmt.Fprintf(out, "  Name:      %s\n", record.Name)
+			fmt.Fprintf(out, "  IPv4:      %s\n", valueOrNone(record.IPAddress))
+			fmt.Fprintf(out, "  IPv6:      %s\n", valueOrNone(record.IPv6))
+			fmt.Fprintf(out, "  Tracker:   %s\n", onlineLabel(record.IsOnline))
+			fmt.Fprintf(out, "  Last seen: %s\n", record.LastSeen.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Fprintln(out, "----------------------------------------")
+	}
+	return printActiveTransfers(out, report)
+}
+
+// printActiveTransfers saare peers ke chalte hue transfers ki table progress ke saath print karta hai
+func printActiveTransfers(out io.Writer, report api.StatusReport) error {
+	fmt.Fprintln(out, "Active Transfers:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PEER\tDIRECTION\tFILENAME\tPROGRESS\tDONE\tTOTAL\tSTARTED")
+	n := 0
+	for _, s := range report.Peers {
+		for _, t := range s.Transfers {
+			n++
+			total := "unknown"
+			if t.TotalBytes > 0 {
+				total = torrentiumWebRTC.FormatFileSizeIEC(t.TotalBytes)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p2p.FormatPeerIDString(s.ID, p2p.ShortPeerIDLength), t.Direction,
+				t.Filename, progressPercent(t), torrentiumWebRTC.FormatFileSizeIEC(t.BytesDone), total, t.StartedAt.Format("15:04:05"))
+		}
+	}
+	if n == 0 {
+		fmt.Fprintln(out, "No active transfers.")
+		return nil
+	}
+	return w.Flush()
+}
+
+// size pata na ho toh "-"; gzip wale send mein wire bytes kam hote hai, isliye 100% par rok dete hai
+func progressPercent(t torrentiumWebRTC.TransferInfo) string {
+	if t.TotalBytes <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", min(float64(t.BytesDone)/float64(t.TotalBytes)*100, 100))
+}
+
+// tracker se ek peer ka database record uske libp2p ID se fetch karta hai
+func (c *Client) fetchPeerRecord(peerID string) (*db.Peer, error) {
+	resp, err := c.trackerRequest("GET_PEER_BY_ID", p2p.GetPeerByIDPayload{PeerID: peerID})
+	if err != nil {
+		return nil, err
+	}
+	var record db.Peer
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse peer info: %w", err)
+	}
+	return &record, nil
+}
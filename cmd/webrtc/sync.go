@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/p2p"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// itni der tak remote peer ka FILE_CATALOG na aaye toh sync fail
+const catalogTimeout = 30 * time.Second
+
+// syncWithPeer connected peer se LIST_FILES bhej kar uski poori file list maangta hai, use tracker par is node ki
+// files (GetFilesByPeer) se milata hai aur jo files yahan nahi hai unhe receive directory mein `get` ki tarah
+// download karna shuru karta hai. Downloads tracker ke through saath-saath chalte hai aur DownloadManager complete
+// hone par unke pieces verify karta hai.
+func (c *Client) syncWithPeer(out io.Writer, idStr string) error {
+	p, id, err := c.pickTransport(idStr)
+	if err != nil {
+		return err
+	}
+	remote, err := c.requestFileCatalog(p, id)
+	if err != nil {
+		return err
+	}
+	local, err := c.localCatalogMessage()
+	if err != nil {
+		return fmt.Errorf("failed to load local files: %w", err)
+	}
+	have := make(map[string]bool, len(local.Files))
+	for _, rec := range local.Files {
+		have[rec.Hash] = true
+	}
+
+	var missing []p2p.FileRecord
+	for _, rec := range remote {
+		if rec.Hash != "" && !have[rec.Hash] {
+			missing = append(missing, rec)
+			have[rec.Hash] = true // ek hi file do naam se ho toh ek baar download
+		}
+	}
+	fmt.Fprintf(out, "%s shares %d file(s), %d missing here.\n", p2p.FormatPeerID(id, p2p.ShortPeerIDLength), len(remote), len(missing))
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(torrentiumWebRTC.DefaultReceiveDir, 0o755); err != nil {
+		return err
+	}
+	started := 0
+	for _, rec := range missing {
+		// naam remote peer deta hai, isliye path sirf receive directory ke andar banta hai
+		path, err := torrentiumWebRTC.SafeReceivePath(torrentiumWebRTC.DefaultReceiveDir, filepath.Base(rec.Name))
+		if err != nil {
+			fmt.Fprintf(out, "Skipping %q: %v\n", rec.Name, err)
+			continue
+		}
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(out, "Skipping %s: %s already exists\n", rec.Name, path)
+			continue
+		}
+		if _, err := uuid.Parse(rec.FileID); err != nil {
+			// purane peers catalog mein file ID nahi bhejte
+			fmt.Fprintf(out, "Skipping %s: peer did not send a file ID\n", rec.Name)
+			continue
+		}
+		if err := c.get(out, rec.FileID, path); err != nil {
+			fmt.Fprintf(out, "Failed to download %s: %v\n", rec.Name, err)
+			continue
+		}
+		started++
+	}
+	fmt.Fprintf(out, "Started %d of %d download(s).\n", started, len(missing))
+	return nil
+}
+
+// p ko LIST_FILES bhejta hai aur uske FILE_CATALOG ka wait karta hai
+func (c *Client) requestFileCatalog(p FileTransport, id peer.ID) ([]p2p.FileRecord, error) {
+	ch := make(chan []p2p.FileRecord, 1)
+	c.peersMux.Lock()
+	c.catalogWaits[id] = ch
+	c.peersMux.Unlock()
+	defer func() {
+		c.peersMux.Lock()
+		if c.catalogWaits[id] == ch {
+			delete(c.catalogWaits, id)
+		}
+		c.peersMux.Unlock()
+	}()
+
+	if err := p.SendTextData(p2p.ChannelMessage{Command: "LIST_FILES"}); err != nil {
+		return nil, fmt.Errorf("failed to request file catalog: %w", err)
+	}
+	select {
+	case files := <-ch:
+		return files, nil
+	case <-time.After(catalogTimeout):
+		return nil, fmt.Errorf("timeout waiting for file catalog from %s", p2p.FormatPeerID(id, p2p.ShortPeerIDLength))
+	}
+}
+
+// aaya FILE_CATALOG us peer ke chal rahe sync tak pahunchata hai; bina maange aaya catalog ignore hota hai
+func (c *Client) deliverFileCatalog(p FileTransport, files []p2p.FileRecord) {
+	id := transportPeerID(p)
+	c.peersMux.Lock()
+	ch, ok := c.catalogWaits[id]
+	delete(c.catalogWaits, id)
+	c.peersMux.Unlock()
+	if !ok {
+		logger.Debug("Ignoring unrequested file catalog", "peer", id)
+		return
+	}
+	ch <- files
+}
+
+// LIST_FILES ka jawab: tracker par is node ki saari files, jinke allow list mein requester nahi hai unhe chhod kar
+func (c *Client) sendFileCatalog(p FileTransport) {
+	msg, err := c.localCatalogMessage()
+	if err != nil {
+		// khaali catalog bhejne se requester ko lagta ki hamare paas kuch nahi hai, isliye jawab hi nahi dete
+		logger.Warn("Could not load local files for catalog", "peer", transportPeerID(p), "error", err)
+		return
+	}
+	shared := msg.Files[:0]
+	for _, rec := range msg.Files {
+		fileID, err := uuid.Parse(rec.FileID)
+		if err != nil || !c.requesterAllowed(p, fileID) {
+			continue
+		}
+		shared = append(shared, rec)
+	}
+	if err := p.SendTextData(p2p.ChannelMessage{Command: "FILE_CATALOG", Files: shared}); err != nil {
+		logger.Warn("Failed to send file catalog", "peer", transportPeerID(p), "error", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// tagFile tracker par file ka category tag lagata hai (remove true ho toh hatata hai).
+// File filename se resolve hoti hai, jaise remove command mein.
+func (c *Client) tagFile(out io.Writer, filename, tag string, remove bool) error {
+	filename = filepath.Base(filename)
+	resp, err := c.trackerRequest("GET_FILE_BY_NAME", p2p.GetFileByNamePayload{Filename: filename})
+	if err != nil {
+		return err
+	}
+	var record db.File
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		return fmt.Errorf("failed to parse file info: %w", err)
+	}
+
+	if _, err := c.trackerRequest("TAG_FILE", p2p.TagFilePayload{FileHash: record.FileHash, Tag: tag, Remove: remove}); err != nil {
+		return err
+	}
+	if remove {
+		fmt.Fprintf(out, "Removed tag %q from %s.\n", tag, filename)
+	} else {
+		fmt.Fprintf(out, "Tagged %s as %q.\n", filename, tag)
+	}
+	return nil
+}
+
+// tracker se ek tag wali saari files laata hai, API ke /files?tag= ke liye
+func (c *Client) fetchFilesByTag(ctx context.Context, tag string) ([]db.File, error) {
+	resp, err := c.trackerRequestContext(ctx, "SEARCH_TAG", p2p.SearchTagPayload{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	var files []db.File
+	if err := json.Unmarshal(resp.Payload, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/api"
+	"torrentium/p2p"
+	"torrentium/tcptransfer"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// tcpFallbackTimeout tak `get` ka ek bhi byte na aaye toh download seeder se seedha TCP par kiya jaata hai.
+// WebRTC connection ke liye bhi 30s hi diye jaate hai.
+const tcpFallbackTimeout = 30 * time.Second
+
+// share ki hui files ke liye TCP fallback server start karta hai aur uska port tracker handshake ke liye yaad rakhta hai.
+// Server opt-in hai: port negative ho toh band, 0 ho toh koi bhi free port.
+func (c *Client) startTCPServer(port int) error {
+	if port < 0 {
+		return nil
+	}
+	s, err := tcptransfer.StartServer(context.Background(), port, c.authorizeTCPRequest)
+	if err != nil {
+		return err
+	}
+	c.tcpPort = s.Port()
+	logger.Info("TCP fallback server started", "port", c.tcpPort)
+	return nil
+}
+
+// TCP request ka requester (signature se verified peer ID) wahi checks paas kare jo sendFile mein hote hai:
+// file share ho rahi ho, peer banned na ho aur file ki allow list use allow kare
+func (c *Client) authorizeTCPRequest(fileIDStr string, requester peer.ID) (string, error) {
+	fileID, err := uuid.Parse(fileIDStr)
+	if err != nil {
+		return "", errors.New("invalid file ID")
+	}
+	path, ok := c.sharedFilePath(fileID)
+	if !ok {
+		return "", errors.New("file not shared")
+	}
+	if c.peerManager.IsBanned(requester.String()) {
+		return "", errors.New("peer is banned")
+	}
+	if !c.peerAllowed(requester, fileID) {
+		return "", errors.New("access denied")
+	}
+	return path, nil
+}
+
+// `get` ke baad tcpFallbackTimeout tak rukta hai; agar tracker ke raaste ek bhi chunk nahi aaya toh download
+// seeder ke TCP server se karta hai. Seeder ne TCP port announce nahi kiya ho toh kuch nahi hota.
+func (c *Client) watchDownloadForTCPFallback(initiated p2p.FileRequestInitiatedPayload, outputPath string) {
+	if initiated.SeederTCPPort <= 0 || initiated.SeederIP == "" {
+		return
+	}
+	time.Sleep(tcpFallbackTimeout)
+
+	fileID := initiated.FileID
+	c.downloadsMux.Lock()
+	outputFile, active := c.activeDownloads[fileID]
+	if !active || c.downloadedBytes[fileID] > 0 {
+		c.downloadsMux.Unlock()
+		return
+	}
+	// download ab TCP ka hai, der se aaye relay chunks ignore ho jaayenge
+	delete(c.activeDownloads, fileID)
+	delete(c.downloadedBytes, fileID)
+	c.downloadsMux.Unlock()
+	outputFile.Close()
+
+	filename := filepath.Base(outputPath)
+	logger.Warn("No data from seeder, falling back to direct TCP", "file", filename, "seeder", initiated.SeederIP, "port", initiated.SeederTCPPort)
+	err := tcptransfer.DownloadViaTCP(context.Background(), initiated.SeederIP, strconv.Itoa(initiated.SeederTCPPort), fileID.String(), outputPath, c.host.Peerstore().PrivKey(c.host.ID()))
+	if err != nil {
+		logger.Error("TCP fallback download failed", "file", filename, "error", err)
+		c.downloads.Untrack(fileID)
+		c.emitTransferEvent(torrentiumWebRTC.TransferEvent{Type: torrentiumWebRTC.TransferFailed, Filename: filename})
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(outputPath); err == nil {
+		size = info.Size()
+	}
+	api.BytesDownloaded.Add(float64(size))
+	c.emitTransferEvent(torrentiumWebRTC.TransferEvent{Type: torrentiumWebRTC.TransferComplete, Filename: filename, BytesDone: size, TotalBytes: size})
+	c.verifyDownload(fileID, filename)
+}
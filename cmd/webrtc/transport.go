@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"torrentium/quictransport"
+	torrentiumWebRTC "torrentium/webRTC"
+)
+
+// FileTransport woh common API hai jo WebRTCPeer aur QUIC fallback (QuicTransfer) dono dete hai,
+// taaki file bhejne/lene ka code transport ki parwah kiye bina kaam kare.
+type FileTransport interface {
+	SendTextData(data interface{}) error
+	SendBinaryData(data []byte) error
+	RequestFile(fileID string) error
+	RequestFileRange(fileID string, start, end int64) error
+	SendFileWithContext(ctx context.Context, filename string, pieceSize int) error
+	SendFileRange(ctx context.Context, filename string, start, end int64) error
+	SendDirectory(ctx context.Context, dirPath string) error
+	SendStream(ctx context.Context, r io.Reader, name string) error
+	ReceiveFile(filename string) (io.WriteCloser, error)
+	ReceiveStream(filename string) (io.WriteCloser, error)
+	ReceiveFileAt(filename string, offset int64) (io.WriteCloser, error)
+	ReceiveDirectory() (io.WriteCloser, error)
+	CheckReceiveSpace(size int64) error
+	SendCancel(filename, reason string) error
+	SendNack(fileHash, filename string, piece int, pieceLength int64) error
+	SendPiece(ctx context.Context, filename, fileHash string, piece int, pieceLength int64) error
+	SetFileWriter(writer io.WriteCloser)
+	GetFileWriter() io.WriteCloser
+	SetTransferInfo(filename string, totalBytes int64)
+	CompleteTransfer()
+	Events() <-chan torrentiumWebRTC.TransferEvent
+	Close() error
+}
+
+var (
+	_ FileTransport = (*torrentiumWebRTC.WebRTCPeer)(nil)
+	_ FileTransport = (*quictransport.QuicTransfer)(nil)
+	_ FileTransport = (*torrentiumWebRTC.MockWebRTCPeer)(nil)
+)
+
+// QUIC fallback listener start karta hai. UDP port wahi hota hai jo libp2p WebSocket ka TCP port hai,
+// isliye dusre peers hamare multiaddr se hi QUIC address nikal lete hai.
+func (c *Client) startQuicListener() error {
+	port := hostTCPPort(c.host)
+	if port == 0 {
+		return errors.New("no TCP listen port found for QUIC fallback")
+	}
+
+	l, err := quictransport.Listen(fmt.Sprintf(":%d", port), c.host.ID().String(), c.onQuicMessage)
+	if err != nil {
+		return err
+	}
+	logger.Info("QUIC fallback listener started", "addr", l.Addr())
+
+	go func() {
+		for {
+			t, err := l.Accept(context.Background())
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				logger.Warn("Failed to accept QUIC connection", "error", err)
+				continue
+			}
+			id, err := peer.Decode(t.RemotePeerID())
+			if err != nil {
+				logger.Warn("QUIC peer sent an invalid peer ID", "peer_id", t.RemotePeerID())
+				t.Close()
+				continue
+			}
+			logger.Info("Accepted QUIC fallback connection", "peer", id)
+			c.addQuicPeer(id, t)
+		}
+	}()
+	return nil
+}
+
+// WebRTC fail hone ke baad peer ke known multiaddrs par ek-ek karke QUIC dial try karta hai
+func (c *Client) dialQuic(id peer.ID) (*quictransport.QuicTransfer, error) {
+	addrs := c.host.Peerstore().Addrs(id)
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no known addresses for %s", id)
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		target, err := quictransport.AddrFromMultiaddr(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		t, err := quictransport.Dial(ctx, target, c.host.ID().String(), c.onQuicMessage)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if t.RemotePeerID() != id.String() {
+			t.Close()
+			lastErr = fmt.Errorf("QUIC peer at %s is %s, expected %s", target, t.RemotePeerID(), id)
+			continue
+		}
+		c.addQuicPeer(id, t)
+		return t, nil
+	}
+	return nil, lastErr
+}
+
+// QUIC stream par aaye messages bhi wahi handler process karta hai jo WebRTC data channel ke liye hai
+func (c *Client) onQuicMessage(data []byte, isString bool, t *quictransport.QuicTransfer) {
+	c.handleTransportMessage(t, data, isString)
+}
+
+// ek naye QUIC peer ko thread-safe tarike se map mein add karta hai
+func (c *Client) addQuicPeer(id peer.ID, t *quictransport.QuicTransfer) {
+	c.peersMux.Lock()
+	defer c.peersMux.Unlock()
+	if old, ok := c.quicPeers[id]; ok && old != t {
+		old.Close()
+		c.stopForwardingEventsLocked(old)
+	}
+	c.quicPeers[id] = t
+	t.MaxReceiveFileSize = c.maxFileSize
+	c.startForwardingEventsLocked(t)
+}
+
+// map se QUIC peer ko thread-safe tarike se fetch karta hai
+func (c *Client) getQuicPeer(id peer.ID) (*quictransport.QuicTransfer, bool) {
+	c.peersMux.RLock()
+	defer c.peersMux.RUnlock()
+	t, ok := c.quicPeers[id]
+	return t, ok
+}
+
+// host ke pehle TCP listen address ka port, koi na ho toh 0. QUIC fallback isi number ke UDP port par sunta hai.
+func hostTCPPort(h host.Host) int {
+	port := 0
+	for _, addr := range h.Addrs() {
+		if p, err := addr.ValueForProtocol(ma.P_TCP); err == nil {
+			fmt.Sscanf(p, "%d", &port)
+			break
+		}
+	}
+	return port
+}
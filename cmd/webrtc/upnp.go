@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+
+	"torrentium/nat"
+)
+
+// upnpTimeout tak gateway discovery aur mappings poori na ho toh UPnP chhod dete hai
+const upnpTimeout = 10 * time.Second
+
+// startPortMapping router par UPnP se libp2p ka TCP port aur QUIC fallback ka UDP port (dono ek hi number) map karta
+// hai aur external address log karta hai. Gateway shutdown par Close karna hai taaki mappings hat jaye.
+func startPortMapping(h host.Host) (*nat.Gateway, error) {
+	port := hostTCPPort(h)
+	if port == 0 {
+		return nil, errors.New("no TCP listen port to map")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), upnpTimeout)
+	defer cancel()
+	gw, err := nat.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mapped := 0
+	for _, proto := range []string{"TCP", "UDP"} {
+		m, err := gw.Map(ctx, proto, port)
+		if err != nil {
+			logger.Warn("UPnP port mapping failed", "protocol", proto, "port", port, "error", err)
+			continue
+		}
+		mapped++
+		logger.Info("UPnP port mapped", "protocol", m.Protocol, "external_ip", valueOrNone(m.ExternalIP), "external_port", m.ExternalPort)
+	}
+	if mapped == 0 {
+		gw.Close(ctx)
+		return nil, errors.New("gateway refused every port mapping")
+	}
+	return gw, nil
+}
+
+// mappings hata deta hai; shutdown par chalta hai, gw nil ho toh kuch nahi
+func releasePortMappings(gw *nat.Gateway) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := gw.Close(ctx); err != nil {
+		logger.Warn("Failed to release UPnP port mappings", "error", err)
+	}
+}
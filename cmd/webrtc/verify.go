@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"torrentium/db"
+	"torrentium/p2p"
+)
+
+// verifyFile disk par padi file ka hash dobara calculate karke tracker ke database record se match karta hai.
+// Mismatch hone par user se poochta hai ki database update karna hai ya file ko share karna band karna hai.
+func (c *Client) verifyFile(out io.Writer, filePath string) error {
+	hash, _, err := calculateFileHash(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", filePath, err)
+	}
+
+	filename := filepath.Base(filePath)
+	resp, err := c.trackerRequest("GET_FILE_BY_NAME", p2p.GetFileByNamePayload{Filename: filename})
+	if err != nil {
+		return err
+	}
+	var record db.File
+	if err := json.Unmarshal(resp.Payload, &record); err != nil {
+		return fmt.Errorf("failed to parse file info: %w", err)
+	}
+
+	if record.FileHash == hash {
+		fmt.Fprintf(out, "✅ %s is intact (hash %s)\n", filename, hash)
+		return nil
+	}
+
+	fmt.Fprintf(out, "❌ Hash mismatch for %s\n  Expected: %s\n  On disk:  %s\n", filename, record.FileHash, hash)
+	switch c.ask(out, "[u]pdate database entry, [r]emove from announcements, or [s]kip? ") {
+	case "u", "update":
+		return c.addFile(out, filePath)
+	case "r", "remove":
+		// disk wali file alag ID se share ho rahi ho sakti hai, isliye path se bhi hatate hai
+		c.stopSharing(record.FileHash, filePath)
+		remaining, err := c.retractAnnouncement(record)
+		if err != nil {
+			return fmt.Errorf("stopped sharing %s, but removing its announcement failed: %w", filename, err)
+		}
+		fmt.Fprintf(out, "Stopped sharing %s and removed its announcement.\n", filename)
+		warnRemainingPeers(out, remaining)
+	default:
+		fmt.Fprintln(out, "No changes made.")
+	}
+	return nil
+}
+
+// file ko local sharing maps se hata deta hai taaki woh peers ko serve na ho
+func (c *Client) stopSharing(fileHash, filePath string) {
+	c.filesMux.Lock()
+	for id, path := range c.sharingFiles {
+		if path == filePath {
+			delete(c.sharingFiles, id)
+		}
+	}
+	delete(c.localFiles, fileHash)
+	c.filesMux.Unlock()
+	c.updateFilesAnnouncedMetric()
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// announceDir config ki watch directory ki saari regular files tracker par announce karta hai.
+// .torrent files aur hidden files skip hoti hai.
+func (c *Client) announceDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Warn("Could not read watch directory", "dir", dir, "error", err)
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".torrent") {
+			continue
+		}
+		if err := c.addFile(os.Stdout, filepath.Join(dir, name)); err != nil {
+			logger.Warn("Failed to announce file from watch directory", "file", name, "error", err)
+		}
+	}
+}
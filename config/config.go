@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"torrentium/torrentfile"
+)
+
+// TURNServer ek TURN relay server ki details hai
+type TURNServer struct {
+	URLs       []string `yaml:"urls"`
+	Username   string   `yaml:"username"`
+	Credential string   `yaml:"credential"`
+}
+
+// Config client aur tracker dono ke saare tunable parameters rakhta hai.
+// Precedence (kam se zyada): defaults < config file < environment variables < command-line flags.
+type Config struct {
+	DatabaseURL     string       `yaml:"database_url"`      // tracker ka Postgres DSN
+	TrackerURL      string       `yaml:"tracker_url"`       // client kis tracker se connect kare
+	TrackerAddr     string       `yaml:"tracker_addr"`      // tracker kis address par listen kare
+	TURNServers     []TURNServer `yaml:"turn_servers"`      // set ho toh default TURN servers ki jagah use hote hai
+	ListenAddrs     []string     `yaml:"listen_addrs"`      // libp2p listen multiaddrs
+	APIPort         int          `yaml:"api_port"`          // 0 ho toh API server band rehta hai
+	WatchDir        string       `yaml:"watch_dir"`         // is directory ki files startup par announce hoti hai
+	PieceLength     int64        `yaml:"piece_length"`      // .torrent files ka piece size (bytes)
+	LogLevel        string       `yaml:"log_level"`         // debug, info, warn ya error
+	LogFormat       string       `yaml:"log_format"`        // text ya json
+	BootstrapPeers  []string     `yaml:"bootstrap_peers"`   // startup par in multiaddrs se connect karte hai
+	BlockStoreDir   string       `yaml:"block_store_dir"`   // downloaded blocks ka content-addressable store
+	TransferHMAC    bool         `yaml:"transfer_hmac"`     // WebRTC file chunks par HMAC, dono peers par on hona chahiye
+	DataChannelPool int          `yaml:"data_channel_pool"` // retransmit pieces ke liye extra data channels, 0 = band
+}
+
+// Default woh values return karta hai jo config file na hone par use hoti hai
+func Default() *Config {
+	return &Config{
+		TrackerURL:    "ws://localhost:8080/ws",
+		TrackerAddr:   ":8080",
+		ListenAddrs:   []string{"/ip4/0.0.0.0/tcp/0/ws", "/ip6/::/tcp/0/ws"},
+		PieceLength:   torrentfile.DefaultPieceLength,
+		LogLevel:      "info",
+		LogFormat:     "text",
+		BlockStoreDir: filepath.Join(filepath.Dir(DefaultPath()), "blocks"),
+	}
+}
+
+// DefaultPath default config file ka path hai: ~/.torrentium/config.yaml
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".torrentium", "config.yaml")
+	}
+	return filepath.Join(home, ".torrentium", "config.yaml")
+}
+
+// Load defaults ke upar config file aur phir environment variables apply karta hai.
+// path empty ho toh DefaultPath use hota hai, aur default file na mile toh koi error nahi hota.
+// Flags ka override caller khud karta hai, kyunki flags har binary ke alag hai.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	case errors.Is(err, fs.ErrNotExist) && !explicit:
+		// default config file optional hai
+	default:
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if cfg.BlockStoreDir == "" {
+		cfg.BlockStoreDir = Default().BlockStoreDir
+	}
+	return cfg, nil
+}
+
+// environment variables file ki values ko override karte hai
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		c.DatabaseURL = v
+	}
+	if v := os.Getenv("TRACKER_WS_URL"); v != "" {
+		c.TrackerURL = v
+	}
+	if v := os.Getenv("TRACKER_WS_ADDR"); v != "" {
+		c.TrackerAddr = v
+	}
+	if v := os.Getenv("TORRENTIUM_LISTEN_ADDRS"); v != "" {
+		c.ListenAddrs = splitList(v)
+	}
+	if v := os.Getenv("TORRENTIUM_API_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid TORRENTIUM_API_PORT %q: %w", v, err)
+		}
+		c.APIPort = port
+	}
+	if v := os.Getenv("TORRENTIUM_WATCH_DIR"); v != "" {
+		c.WatchDir = v
+	}
+	if v := os.Getenv("TORRENTIUM_PIECE_LENGTH"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid TORRENTIUM_PIECE_LENGTH %q: %w", v, err)
+		}
+		c.PieceLength = n
+	}
+	if v := os.Getenv("TORRENTIUM_LOG_LEVEL"); v != "" {
+		c.LogLevel = v
+	}
+	if v := os.Getenv("TORRENTIUM_BLOCK_STORE_DIR"); v != "" {
+		c.BlockStoreDir = v
+	}
+	if v := os.Getenv("TORRENTIUM_BOOTSTRAP_PEERS"); v != "" {
+		c.BootstrapPeers = splitList(v)
+	}
+	if v := os.Getenv("TORRENTIUM_TRANSFER_HMAC"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid TORRENTIUM_TRANSFER_HMAC %q: %w", v, err)
+		}
+		c.TransferHMAC = on
+	}
+	if v := os.Getenv("TORRENTIUM_DATA_CHANNEL_POOL"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid TORRENTIUM_DATA_CHANNEL_POOL %q: %w", v, err)
+		}
+		c.DataChannelPool = n
+	}
+	return nil
+}
+
+// comma-separated list ko todta hai, khaali entries hata deta hai
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// config file ka template jo `config init` likhta hai
+const template = `# Torrentium configuration
+# Environment variables aur command-line flags in values ko override karte hai.
+
+# Tracker ka Postgres connection string (env: DATABASE_URL)
+database_url: ""
+
+# Client kis tracker se connect kare (env: TRACKER_WS_URL)
+tracker_url: "ws://localhost:8080/ws"
+
+# Tracker kis address par listen kare (env: TRACKER_WS_ADDR)
+tracker_addr: ":8080"
+
+# TURN relay servers; set karne par default TURN servers ki jagah use hote hai
+turn_servers: []
+#  - urls: ["turn:turn.example.com:3478"]
+#    username: "user"
+#    credential: "secret"
+
+# libp2p listen addresses (env: TORRENTIUM_LISTEN_ADDRS, comma-separated)
+listen_addrs:
+  - "/ip4/0.0.0.0/tcp/0/ws"
+  - "/ip6/::/tcp/0/ws"
+
+# API server port, 0 = disabled (env: TORRENTIUM_API_PORT)
+api_port: 0
+
+# Is directory ki files startup par announce hoti hai (env: TORRENTIUM_WATCH_DIR)
+watch_dir: ""
+
+# .torrent files ka piece size bytes mein (env: TORRENTIUM_PIECE_LENGTH)
+piece_length: 262144
+
+# debug, info, warn ya error (env: TORRENTIUM_LOG_LEVEL, flag: --log-level)
+log_level: "info"
+
+# text ya json (flag: --log-format)
+log_format: "text"
+
+# Startup par in peers se connect karte hai (env: TORRENTIUM_BOOTSTRAP_PEERS, comma-separated)
+bootstrap_peers: []
+
+# Downloaded blocks ka store, empty = ~/.torrentium/blocks (env: TORRENTIUM_BLOCK_STORE_DIR)
+block_store_dir: ""
+
+# WebRTC file chunks par libp2p identity keys se bani HMAC lagao, taaki raste mein badle chunks pakde jaye.
+# Dono peers par on hona chahiye, warna transfers fail honge (env: TORRENTIUM_TRANSFER_HMAC)
+transfer_hmac: false
+
+# Offer karte waqt itne extra "data-0".."data-N-1" data channels kholo, taaki kharab pieces ek saath dobara aa sake.
+# 0 = band. Dono peers naye version ke hone chahiye; HMAC on ho toh pool use nahi hota (env: TORRENTIUM_DATA_CHANNEL_POOL)
+data_channel_pool: 0
+`
+
+// WriteTemplate diye gaye path par template config file likhta hai. File pehle se ho toh overwrite nahi karta.
+func WriteTemplate(path string) error {
+	if path == "" {
+		path = DefaultPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("config file %s already exists", path)
+		}
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(template)
+	return err
+}
+
+// RunSubcommand `config init` jaise config sub-commands chalata hai. handled false ho toh args config command nahi the.
+func RunSubcommand(args []string, path string) (handled bool, err error) {
+	if len(args) == 0 || args[0] != "config" {
+		return false, nil
+	}
+	if len(args) < 2 || args[1] != "init" {
+		return true, errors.New("usage: config init")
+	}
+	if path == "" {
+		path = DefaultPath()
+	}
+	if err := WriteTemplate(path); err != nil {
+		return true, err
+	}
+	fmt.Printf("Wrote config template to %s\n", path)
+	return true, nil
+}
@@ -0,0 +1,176 @@
+// Package control chalte hue client ko Unix domain socket par commands bhejne ka protocol hai.
+// Har request aur response ek line ka JSON hota hai, taaki scripts bina stdin ke client chala sake.
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"torrentium/logging"
+)
+
+var logger = logging.For("control")
+
+// maxRequestSize ek request line ki maximum length hai
+const maxRequestSize = 64 * 1024
+
+// Request ek command hai, jaise {"command": "add", "args": ["foo.txt"]}.
+// Stream true ho toh request line ke baad connection ka baaki data (client ke write band karne tak)
+// command ka input hai, jaise `pipe` ke liye stdin; response ke baad connection band ho jaata hai.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Stream  bool     `json:"stream,omitempty"`
+}
+
+// Response command ka output hai; Error empty ho toh command successful tha
+type Response struct {
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Handler ek command chala kar uska output return karta hai. input sirf stream requests ke liye non-nil hai.
+type Handler func(cmd string, args []string, input io.Reader) (output string, err error)
+
+// DefaultSocketPath default control socket hai: ~/.torrentium/torrentium.sock
+func DefaultSocketPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".torrentium", "torrentium.sock")
+	}
+	return filepath.Join(home, ".torrentium", "torrentium.sock")
+}
+
+// Listen path par Unix socket kholta hai. Pichle run ki bachi hui socket file hata di jaati hai, aur socket
+// sirf owner ke liye (0600) hota hai kyunki us par koi bhi command chal sakta hai.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve l par aane wale connections accept karta hai aur har request line ko handle se chala kar response line likhta hai.
+// l band hone par laut aata hai.
+func Serve(l net.Listener, handle Handler) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Warn("Control socket accept failed", "error", err)
+			}
+			return
+		}
+		go serveConn(conn, handle)
+	}
+}
+
+// ek connection par requests ek-ek karke chalata hai jab tak client band na kare.
+// Scanner ki jagah bufio.Reader hai, kyunki stream request ka input request line ke turant baad se padhna hai.
+func serveConn(conn net.Conn, handle Handler) {
+	defer conn.Close()
+	r := bufio.NewReaderSize(conn, maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for {
+		line, err := r.ReadSlice('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Warn("Control socket read failed", "error", err)
+			}
+			return
+		}
+
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else if req.Command == "" {
+			resp.Error = "missing command"
+		} else {
+			var input io.Reader
+			if req.Stream {
+				input = r
+			}
+			out, err := handle(req.Command, req.Args, input)
+			resp.Output = out
+			if err != nil {
+				resp.Error = err.Error()
+			}
+		}
+		if err := enc.Encode(resp); err != nil {
+			logger.Warn("Failed to write control response", "error", err)
+			return
+		}
+		if req.Stream {
+			return
+		}
+	}
+}
+
+// Send path wale socket par ek command bhejta hai aur uska response laata hai
+func Send(path, cmd string, args []string) (*Response, error) {
+	conn, err := dial(path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Command: cmd, Args: args}); err != nil {
+		return nil, err
+	}
+	return readResponse(conn)
+}
+
+// SendStream Send jaisa hai, bas request ke baad input EOF tak command ke input ki tarah jaata hai.
+// Input alag goroutine se jaata hai, taaki command beech mein fail ho toh bhi uska response padha ja sake.
+func SendStream(path, cmd string, args []string, input io.Reader) (*Response, error) {
+	conn, err := dial(path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Command: cmd, Args: args, Stream: true}); err != nil {
+		return nil, err
+	}
+	go func() {
+		if _, err := io.Copy(conn, input); err != nil {
+			logger.Debug("Control stream input stopped", "error", err)
+		}
+		conn.CloseWrite()
+	}()
+	return readResponse(conn)
+}
+
+func dial(path string) (*net.UnixConn, error) {
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("client is not running (no control socket at %s): %w", path, err)
+	}
+	return conn, nil
+}
+
+func readResponse(conn net.Conn) (*Response, error) {
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &resp, nil
+}
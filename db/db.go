@@ -2,47 +2,86 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/joho/godotenv"
+
+	"torrentium/logging"
 )
 
-// DB ek global variable hai jo database connection pool ko hold karta hai.
-var DB *pgxpool.Pool
+// db package ka logger
+var logger = logging.For("db")
 
-func InitDB() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: Could not load .env file: %v. Proceeding with environment variables.", err)
-	}
+// maxRetryDelay InitDBWithRetry ke do attempts ke beech ka maximum intezaar hai
+const maxRetryDelay = 30 * time.Second
+
+// InitDB diye gaye DSN se naya connection pool banata hai, use ping karta hai aur migrations chalata hai.
+// Pool caller ko return hota hai, koi package-level state set nahi hoti. ctx cancel hone par connect/migrations ruk jaate hai.
+func InitDB(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
+	return InitDBWithRetry(ctx, dsn, 1, 0)
+}
 
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
+// InitDBWithRetry InitDB jaisa hai, bas database tak connection (pool banana aur ping) maxAttempts baar tak try hota hai,
+// har fail attempt ke baad exponential backoff (base, 2*base, 4*base, ..., max 30s) ke saath. Docker Compose mein
+// Postgres tracker ke baad ready hota hai, isliye yeh zaroori hai. ctx ki deadline ya cancel retries ko pehle hi rok deti hai.
+// Galat DSN aur migrations ke errors retry nahi hote.
+func InitDBWithRetry(ctx context.Context, dsn string, maxAttempts int, base time.Duration) (*pgxpool.Pool, error) {
+	if dsn == "" {
+		return nil, errors.New("database DSN is empty")
+	}
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database DSN: %w", err)
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
 
-	if user == "" || password == "" || host == "" || port == "" || dbname == "" {
-		log.Fatal("Error: One or more database environment variables are not set (DB_USER, DB_PASSWORD, DB_HOST, DB_PORT, DB_NAME).")
+	var pool *pgxpool.Pool
+	delay := base
+	for attempt := 1; ; attempt++ {
+		if pool, err = connect(ctx, cfg); err == nil {
+			break
+		}
+		if maxAttempts == 1 {
+			return nil, err
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		logger.Warn("Database not reachable, retrying", "attempt", attempt, "max_attempts", maxAttempts, "retry_in", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
 	}
 
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		user, password, host, port, dbname)
+	logger.Info("Successfully connected to DB")
 
-	ctx := context.Background()
-	var err error
-	// pgxpool ka use karke naya connection pool banate hain.
-	DB, err = pgxpool.New(ctx, dbURL)
+	// Schema ko latest version tak le aate hain
+	if err := RunMigrations(ctx, pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("error running DB migrations: %w", err)
+	}
+	return pool, nil
+}
+
+// pool banata hai aur ping karke check karta hai ki database sach mein reachable hai
+func connect(ctx context.Context, cfg *pgxpool.Config) (*pgxpool.Pool, error) {
+	// pgxpool ka use karke naya connection pool banate hain. Config copy karte hai kyunki pool use apna maan leta hai.
+	pool, err := pgxpool.NewWithConfig(ctx, cfg.Copy())
 	if err != nil {
-		log.Fatalf("Error creating DB pool: %v\n", err)
+		return nil, fmt.Errorf("error creating DB pool: %w", err)
 	}
 
 	// Database ko ping karke connection check karte hain.
-	if err = DB.Ping(ctx); err != nil {
-		log.Fatalf("Error connecting to DB: %v\n", err)
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("error connecting to DB: %w", err)
 	}
-
-	log.Println("-> Successfully connected to DB")
-}
\ No newline at end of file
+	return pool, nil
+}
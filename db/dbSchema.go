@@ -1,6 +1,7 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,18 +12,24 @@ type Peer struct {
 	PeerID     string    `db:"peer_id"`
 	Name       string    `db:"name"`
 	Multiaddrs []string  `db:"multiaddrs"`
+	IPAddress  string    `db:"ip_address"`   // best IPv4 address, empty agar nahi hai
+	IPv6       string    `db:"ipv6_address"` // best IPv6 address, empty agar nahi hai
+	TCPPort    int       `db:"tcp_port"`     // direct TCP download fallback ka port, 0 agar band hai
 	IsOnline   bool      `db:"is_online"`
 	LastSeen   time.Time `db:"last_seen"`
 	CreatedAt  time.Time `db:"created_at"`
 }
 
 type File struct {
-	ID          uuid.UUID `db:"id"`
-	FileHash    string    `db:"file_hash"`
-	Filename    string    `db:"filename"`
-	FileSize    int64     `db:"file_size"`
-	ContentType *string   `db:"content_type"` // Changed to *string to handle NULL values
-	CreatedAt   time.Time `db:"created_at"`
+	ID          uuid.UUID  `db:"id"`
+	FileHash    string     `db:"file_hash"`
+	Filename    string     `db:"filename"`
+	FileSize    int64      `db:"file_size"`
+	ContentType *string    `db:"content_type"` // Changed to *string to handle NULL values
+	InfoHash    *string    `db:"info_hash"`    // BitTorrent info-hash (hex), purani files ke liye NULL
+	Tags        []string   `db:"tags"`         // category tags jaise video, audio, document
+	CreatedAt   time.Time  `db:"created_at"`
+	AnnouncedAt *time.Time `db:"announced_at"` // sirf peer-specific queries mein set hota hai (peer_files se)
 }
 
 // ek peer aur ek file ke beech ke link ki table hai
@@ -53,3 +60,91 @@ type ActiveConnection struct {
 	StartedAt   time.Time  `db:"started_at"`
 	CompletedAt *time.Time `db:"completed_at"` // pointer taaki NULL point kar sake
 }
+
+// .torrent file ka ek piece, taaki downloader har piece alag se verify kar sake
+type FilePiece struct {
+	FileHash   string `db:"file_hash"`
+	PieceIndex int    `db:"piece_index"`
+	PieceHash  []byte `db:"piece_hash"` // piece ka SHA-1 hash
+	Length     int64  `db:"length"`     // last piece chhota ho sakta hai
+}
+
+// network health ke liye ek seeder ki summary (GetTopSeeders)
+type SeederRecord struct {
+	PeerID        string    `db:"peer_id"` // libp2p peer ID
+	FilesShared   int       `db:"files_shared"`
+	BytesUploaded int64     `db:"bytes_uploaded"`
+	LastSeen      time.Time `db:"last_seen"`
+}
+
+// dashboard ke liye poore network ka ek snapshot (StatsSnapshot)
+type NetworkStats struct {
+	TotalFiles            int   `db:"total_files"`
+	TotalSize             int64 `db:"total_size"` // saari files ka kul size bytes mein
+	OnlinePeers           int   `db:"online_peers"`
+	OfflinePeers          int   `db:"offline_peers"`
+	TotalBytesTransferred int64 `db:"total_bytes_transferred"` // saare peers ke uploaded bytes (peer_stats) ka jod
+}
+
+// BatchUpsertPeers ke liye ek peer ki entry (UpsertPeer ke arguments jaisa)
+type PeerRecord struct {
+	PeerID     string
+	Name       string
+	Multiaddrs []string
+}
+
+// ek peer ka doosre peer par lagaya ban (peer_bans table)
+type PeerBan struct {
+	BannedBy     string     `db:"banned_by"`      // ban lagane wale peer ka libp2p ID
+	BannedPeerID string     `db:"banned_peer_id"` // banned peer ka libp2p ID
+	Reason       string     `db:"reason"`
+	BannedAt     time.Time  `db:"banned_at"`
+	ExpiresAt    *time.Time `db:"expires_at"` // nil ho toh ban permanent hai
+}
+
+// ek file jiske saare announcing peers threshold se zyada der se offline hai (GetFilesNeedingReseed)
+type AtRiskFile struct {
+	FileHash string    `db:"file_hash"`
+	Filename string    `db:"filename"`
+	FileSize int64     `db:"file_size"`
+	Seeders  int       `db:"seeders"`   // file announce karne wale (offline) peers
+	LastSeen time.Time `db:"last_seen"` // unmein se sabse haal mein dikha peer kab dikha tha
+}
+
+// peer audit events ke types (events table ka event_type)
+const (
+	EventPeerConnected     = "peer_connected"
+	EventPeerDisconnected  = "peer_disconnected"
+	EventFileAdded         = "file_added"
+	EventFileRemoved       = "file_removed"
+	EventTransferCompleted = "transfer_completed"
+	EventTransferFailed    = "transfer_failed"
+)
+
+// gossip se kisi peer ko pata chali file (gossip_files table)
+type GossipFile struct {
+	FileHash     string    `db:"file_hash"`
+	Filename     string    `db:"filename"`
+	FileSize     int64     `db:"file_size"`
+	OriginPeerID string    `db:"origin_peer_id"` // jis peer ke paas file hai, gossip bhejne wale ke hisab se
+	LearnedAt    time.Time `db:"learned_at"`
+}
+
+// peer ka ek audit event (events table, GetPeerHistory)
+type PeerHistoryRecord struct {
+	ID         int             `db:"id"`
+	PeerID     string          `db:"peer_id"` // peer ka libp2p ID
+	EventType  string          `db:"event_type"`
+	Metadata   json.RawMessage `db:"metadata"` // event ki details, jaise file hash ya naam; na ho toh null
+	OccurredAt time.Time       `db:"occurred_at"`
+}
+
+// catalog export ki ek row: ek file aur uska ek announcing peer (ListAllFiles)
+type CatalogEntry struct {
+	FileHash    string    `db:"file_hash"`
+	Filename    string    `db:"filename"`
+	FileSize    int64     `db:"file_size"`
+	PeerID      string    `db:"peer_id"`      // announcing peer ka libp2p ID, koi peer na ho toh empty
+	ContentType *string   `db:"content_type"` // MIME type, purani files ke liye NULL
+	CreatedAt   time.Time `db:"created_at"`
+}
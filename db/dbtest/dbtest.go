@@ -0,0 +1,83 @@
+// Package dbtest tests aur benchmarks ke liye Postgres database deta hai. EnvDatabaseURL set ho toh wahi database
+// use hota hai (jaise CI ka service container), warna dockertest se ek temporary Postgres container start hota hai
+// jo test khatam hone par hat jaata hai. Dono mein se kuch na mile toh test skip hota hai.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// EnvDatabaseURL ek khaali test database ka DSN hai. Tests is database ki tables badalte aur khaali karte hai,
+// isliye yeh kabhi asli tracker ka database nahi hona chahiye.
+const EnvDatabaseURL = "TORRENTIUM_TEST_DATABASE_URL"
+
+// Postgres image jo dockertest chalata hai
+const (
+	postgresRepository = "postgres"
+	postgresTag        = "16-alpine"
+)
+
+// containerTimeout container ke ready hone ka maximum intezaar hai (pehli baar image pull bhi isi mein)
+const containerTimeout = 2 * time.Minute
+
+// PostgresURL test ke liye Postgres ka DSN deta hai. Container bana ho toh tb.Cleanup use hata deta hai.
+// Migrations yahan nahi chalti; db.InitDB unhe chala deta hai.
+func PostgresURL(tb testing.TB) string {
+	tb.Helper()
+	if dsn := os.Getenv(EnvDatabaseURL); dsn != "" {
+		return dsn
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		tb.Skipf("no %s set and docker is not available: %v", EnvDatabaseURL, err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		tb.Skipf("no %s set and docker is not reachable: %v", EnvDatabaseURL, err)
+	}
+
+	res, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: postgresRepository,
+		Tag:        postgresTag,
+		Env:        []string{"POSTGRES_PASSWORD=torrentium", "POSTGRES_DB=torrentium"},
+	}, func(hc *docker.HostConfig) {
+		// test process crash ho jaye tab bhi container khud hat jaata hai
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		tb.Skipf("could not start %s:%s: %v", postgresRepository, postgresTag, err)
+	}
+	tb.Cleanup(func() {
+		if err := pool.Purge(res); err != nil {
+			tb.Logf("failed to remove postgres container: %v", err)
+		}
+	})
+	// Cleanup na chale (test binary kill ho jaye) tab bhi docker container ko 15 minute baad hata deta hai
+	res.Expire(15 * 60)
+
+	dsn := fmt.Sprintf("postgres://postgres:torrentium@%s/torrentium?sslmode=disable", res.GetHostPort("5432/tcp"))
+	pool.MaxWait = containerTimeout
+	err = pool.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := pgx.Connect(ctx, dsn)
+		if err != nil {
+			return err
+		}
+		defer conn.Close(ctx)
+		return conn.Ping(ctx)
+	})
+	if err != nil {
+		tb.Fatalf("postgres container did not become ready: %v", err)
+	}
+	return dsn
+}
@@ -0,0 +1,940 @@
+// Package memory tracker ke storage ka in-memory implementation hai, taaki tracker bina Postgres ke chal sake
+// (--no-db). Sirf testing ke liye hai: saara data process ki memory mein rehta hai aur exit par chala jaata hai.
+// Har method db.Repository jaisa hi behave karta hai, "nahi mila" par bhi wahi pgx.ErrNoRows aata hai.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"torrentium/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// peer_files ki ek row
+type peerFile struct {
+	id          uuid.UUID
+	peerID      uuid.UUID // peers.id
+	fileID      uuid.UUID
+	announcedAt time.Time
+	signature   []byte
+}
+
+type linkKey struct {
+	peerID, fileID uuid.UUID
+}
+
+type banKey struct {
+	bannedBy, peerID string
+}
+
+// piece_cache mein ek peer ki ek file
+type cacheKey struct {
+	peerID   uuid.UUID
+	fileHash string
+}
+
+type verifiedPieces struct {
+	modTime time.Time
+	pieces  map[int]time.Time // piece -> verified_at
+}
+
+// Repository saare tables ko maps mein rakhta hai, ek RWMutex ke peeche
+type Repository struct {
+	mu        sync.RWMutex
+	peers     map[string]*db.Peer // libp2p peer ID se
+	scores    map[uuid.UUID]float64
+	files     map[uuid.UUID]*db.File
+	links     map[linkKey]*peerFile
+	bans      map[banKey]db.PeerBan
+	access    map[string]map[string]bool // file hash -> allowed peer IDs
+	uploaded  map[uuid.UUID]int64        // peers.id -> bytes_uploaded
+	pieces    map[string]map[int]db.FilePiece
+	verified  map[cacheKey]*verifiedPieces
+	gossip    map[uuid.UUID][]db.GossipFile // peers.id -> gossip se mili files, save hone ke order mein
+	events    []db.PeerHistoryRecord
+	nextEvent int
+}
+
+// NewRepository ek khaali in-memory store banata hai
+func NewRepository() *Repository {
+	return &Repository{
+		peers:    make(map[string]*db.Peer),
+		scores:   make(map[uuid.UUID]float64),
+		files:    make(map[uuid.UUID]*db.File),
+		links:    make(map[linkKey]*peerFile),
+		bans:     make(map[banKey]db.PeerBan),
+		access:   make(map[string]map[string]bool),
+		uploaded: make(map[uuid.UUID]int64),
+		pieces:   make(map[string]map[int]db.FilePiece),
+		verified: make(map[cacheKey]*verifiedPieces),
+		gossip:   make(map[uuid.UUID][]db.GossipFile),
+	}
+}
+
+// caller ko copy dete hai taaki woh store ki state na badal sake
+func clonePeer(p *db.Peer) db.Peer {
+	c := *p
+	c.Multiaddrs = slices.Clone(p.Multiaddrs)
+	return c
+}
+
+func cloneFile(f *db.File) db.File {
+	c := *f
+	c.Tags = slices.Clone(f.Tags)
+	if f.ContentType != nil {
+		ct := *f.ContentType
+		c.ContentType = &ct
+	}
+	if f.InfoHash != nil {
+		ih := *f.InfoHash
+		c.InfoHash = &ih
+	}
+	return c
+}
+
+// files sabse nayi pehle, SQL ke ORDER BY created_at DESC, id jaisa
+func sortNewestFirst(files []db.File) {
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].CreatedAt.Equal(files[j].CreatedAt) {
+			return files[i].CreatedAt.After(files[j].CreatedAt)
+		}
+		return files[i].ID.String() < files[j].ID.String()
+	})
+}
+
+// r.mu pakad kar call karna hai
+func (r *Repository) peerByDBID(id uuid.UUID) *db.Peer {
+	for _, p := range r.peers {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// r.mu pakad kar call karna hai
+func (r *Repository) fileByHash(fileHash string) *db.File {
+	for _, f := range r.files {
+		if f.FileHash == fileHash {
+			return f
+		}
+	}
+	return nil
+}
+
+// file ko announce karne wale saare links; r.mu pakad kar call karna hai
+func (r *Repository) linksForFile(fileID uuid.UUID) []*peerFile {
+	var out []*peerFile
+	for _, l := range r.links {
+		if l.fileID == fileID {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// file aur uske saare links hata deta hai (ON DELETE CASCADE jaisa); r.mu pakad kar call karna hai
+func (r *Repository) deleteFile(fileID uuid.UUID) {
+	delete(r.files, fileID)
+	for k := range r.links {
+		if k.fileID == fileID {
+			delete(r.links, k)
+		}
+	}
+}
+
+// naya peer insert ya purana online mark karta hai, naye peer ka trust score 0.50
+func (r *Repository) UpsertPeer(ctx context.Context, peerID, name string, multiaddrs []string) (uuid.UUID, error) {
+	if err := db.ValidatePeerAddrs(peerID, multiaddrs); err != nil {
+		return uuid.Nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if p, ok := r.peers[peerID]; ok {
+		p.IsOnline = true
+		p.LastSeen = now
+		p.Multiaddrs = slices.Clone(multiaddrs)
+		return p.ID, nil
+	}
+	p := &db.Peer{ID: uuid.New(), PeerID: peerID, Name: name, Multiaddrs: slices.Clone(multiaddrs), IsOnline: true, LastSeen: now, CreatedAt: now}
+	r.peers[peerID] = p
+	r.scores[p.ID] = 0.50
+	return p.ID, nil
+}
+
+// FindPeersByIDs diye gaye libp2p IDs wale peers
+func (r *Repository) FindPeersByIDs(ctx context.Context, peerIDs []string) ([]db.Peer, error) {
+	if len(peerIDs) == 0 {
+		return []db.Peer{}, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var peers []db.Peer
+	seen := make(map[string]bool, len(peerIDs))
+	for _, id := range peerIDs {
+		if p, ok := r.peers[id]; ok && !seen[id] {
+			seen[id] = true
+			peers = append(peers, clonePeer(p))
+		}
+	}
+	return peers, nil
+}
+
+// currently online peers, pehle bane pehle
+func (r *Repository) FindOnlinePeers(ctx context.Context) ([]db.Peer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var peers []db.Peer
+	for _, p := range r.peers {
+		if p.IsOnline {
+			peers = append(peers, clonePeer(p))
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].CreatedAt.Before(peers[j].CreatedAt) })
+	return peers, nil
+}
+
+// peer ke IPv4 aur IPv6 addresses update karta hai
+func (r *Repository) UpdatePeerIPs(ctx context.Context, peerID, ipv4, ipv6 string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.peers[peerID]; ok {
+		p.IPAddress = ipv4
+		p.IPv6 = ipv6
+	}
+	return nil
+}
+
+// peer ka TCP fallback port update karta hai
+func (r *Repository) UpdatePeerTCPPort(ctx context.Context, peerID string, port int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.peers[peerID]; ok {
+		p.TCPPort = port
+	}
+	return nil
+}
+
+// peer ko offline mark karta hai
+func (r *Repository) SetPeerOffline(ctx context.Context, peerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.peers[peerID]; ok {
+		p.IsOnline = false
+		p.LastSeen = time.Now()
+	}
+	return nil
+}
+
+// HealthCheck memory store ke liye hamesha nil, koi connection nahi hai jo toot sake
+func (r *Repository) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// saare peers offline; memory store naya hi hota hai, phir bhi db.Repository jaisa rakha hai
+func (r *Repository) MarkAllPeersOffline(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.peers {
+		p.IsOnline = false
+	}
+	return nil
+}
+
+// libp2p peer ID se peer
+func (r *Repository) GetPeerByID(ctx context.Context, peerID string) (*db.Peer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.peers[peerID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	c := clonePeer(p)
+	return &c, nil
+}
+
+// IP se peer, multiple matches mein sabse recently seen wala
+func (r *Repository) GetPeerByIP(ctx context.Context, ip string) (*db.Peer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var best *db.Peer
+	for _, p := range r.peers {
+		if (p.IPAddress == ip || p.IPv6 == ip) && (best == nil || p.LastSeen.After(best.LastSeen)) {
+			best = p
+		}
+	}
+	if best == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := clonePeer(best)
+	return &c, nil
+}
+
+// DB ID se peer
+func (r *Repository) GetPeerInfoByDBID(ctx context.Context, peerDBID uuid.UUID) (*db.Peer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p := r.peerByDBID(peerDBID)
+	if p == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := clonePeer(p)
+	return &c, nil
+}
+
+// file insert karta hai, same hash ki file pehle se ho toh uska ID
+func (r *Repository) InsertFile(ctx context.Context, fileHash, filename string, fileSize int64, contentType string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f := r.fileByHash(fileHash); f != nil {
+		return f.ID, nil
+	}
+	f := &db.File{ID: uuid.New(), FileHash: fileHash, Filename: filename, FileSize: fileSize, Tags: []string{}, CreatedAt: time.Now()}
+	if contentType != "" {
+		f.ContentType = &contentType
+	}
+	r.files[f.ID] = f
+	return f.ID, nil
+}
+
+// file ka BitTorrent info-hash set karta hai
+func (r *Repository) SetFileInfoHash(ctx context.Context, fileID uuid.UUID, infoHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.files[fileID]; ok {
+		f.InfoHash = &infoHash
+	}
+	return nil
+}
+
+// ID se file
+func (r *Repository) GetFileByID(ctx context.Context, fileID uuid.UUID) (*db.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.files[fileID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	c := cloneFile(f)
+	return &c, nil
+}
+
+// content hash se file
+func (r *Repository) GetFileByHash(ctx context.Context, fileHash string) (*db.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f := r.fileByHash(fileHash)
+	if f == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := cloneFile(f)
+	return &c, nil
+}
+
+// filename se file, same naam ki kai files mein sabse nayi
+func (r *Repository) GetFileByName(ctx context.Context, filename string) (*db.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var best *db.File
+	for _, f := range r.files {
+		if f.Filename == filename && (best == nil || f.CreatedAt.After(best.CreatedAt)) {
+			best = f
+		}
+	}
+	if best == nil {
+		return nil, pgx.ErrNoRows
+	}
+	c := cloneFile(best)
+	return &c, nil
+}
+
+// peer ne yeh file announce ki hai ya nahi
+func (r *Repository) IsFileAnnouncedBy(ctx context.Context, fileID uuid.UUID, peerLibp2pID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return false, nil
+	}
+	_, ok = r.links[linkKey{p.ID, fileID}]
+	return ok, nil
+}
+
+// saari files, sabse nayi pehle
+func (r *Repository) FindAllFiles(ctx context.Context) ([]db.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var files []db.File
+	for _, f := range r.files {
+		files = append(files, cloneFile(f))
+	}
+	sortNewestFirst(files)
+	return files, nil
+}
+
+// har file aur uske har announcing peer ki ek row; bina peer wali file empty peer ID ke saath
+func (r *Repository) ListAllFiles(ctx context.Context) ([]db.CatalogEntry, error) {
+	files, _ := r.FindAllFiles(ctx)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []db.CatalogEntry
+	for _, f := range files {
+		var peerIDs []string
+		for _, l := range r.linksForFile(f.ID) {
+			if p := r.peerByDBID(l.peerID); p != nil {
+				peerIDs = append(peerIDs, p.PeerID)
+			}
+		}
+		if len(peerIDs) == 0 {
+			peerIDs = []string{""}
+		}
+		sort.Strings(peerIDs)
+		for _, id := range peerIDs {
+			entries = append(entries, db.CatalogEntry{FileHash: f.FileHash, Filename: f.Filename, FileSize: f.FileSize, PeerID: id, ContentType: f.ContentType, CreatedAt: f.CreatedAt})
+		}
+	}
+	return entries, nil
+}
+
+// files ka ek page (sabse nayi pehle) aur total files ka count
+func (r *Repository) ListFilesPage(ctx context.Context, offset, limit int) ([]db.File, int, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	files, _ := r.FindAllFiles(ctx)
+	total := len(files)
+	if offset >= total {
+		return nil, total, nil
+	}
+	return files[offset:min(offset+limit, total)], total, nil
+}
+
+// peer ki announce ki hui files, sabse recently announced pehle
+func (r *Repository) GetFilesByPeer(ctx context.Context, peerLibp2pID string) ([]db.File, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return nil, nil
+	}
+	var files []db.File
+	for k, l := range r.links {
+		if k.peerID != p.ID {
+			continue
+		}
+		if f, ok := r.files[k.fileID]; ok {
+			c := cloneFile(f)
+			announced := l.announcedAt
+			c.AnnouncedAt = &announced
+			files = append(files, c)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].AnnouncedAt.After(*files[j].AnnouncedAt) })
+	return files, nil
+}
+
+// peer aur file ka link banata hai, pehle se ho toh wahi link ka ID
+func (r *Repository) InsertPeerFile(ctx context.Context, peerLibp2pID string, fileID uuid.UUID) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("failed to find peer with peer_id=%s: %w", peerLibp2pID, pgx.ErrNoRows)
+	}
+	if _, ok := r.files[fileID]; !ok {
+		return uuid.Nil, fmt.Errorf("file %s not found", fileID)
+	}
+	key := linkKey{p.ID, fileID}
+	if l, ok := r.links[key]; ok {
+		return l.id, nil
+	}
+	l := &peerFile{id: uuid.New(), peerID: p.ID, fileID: fileID, announcedAt: time.Now()}
+	r.links[key] = l
+	return l.id, nil
+}
+
+// peer-file link par announcement ka signature store karta hai
+func (r *Repository) SetAnnouncementSignature(ctx context.Context, fileID uuid.UUID, peerLibp2pID string, sig []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.peers[peerLibp2pID]; ok {
+		if l, ok := r.links[linkKey{p.ID, fileID}]; ok {
+			l.signature = slices.Clone(sig)
+		}
+	}
+	return nil
+}
+
+// peer ke announcement ka signature, signature na ho toh nil
+func (r *Repository) GetAnnouncementSignature(ctx context.Context, fileHash, peerLibp2pID string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.peers[peerLibp2pID]
+	f := r.fileByHash(fileHash)
+	if !ok || f == nil {
+		return nil, pgx.ErrNoRows
+	}
+	l, ok := r.links[linkKey{p.ID, f.ID}]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return slices.Clone(l.signature), nil
+}
+
+// peer ka announcement hatata hai, koi aur peer na bache toh file bhi. Baaki peers ki ginti return karta hai.
+func (r *Repository) RemoveFile(ctx context.Context, fileHash, peerLibp2pID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	f := r.fileByHash(fileHash)
+	if !ok || f == nil || r.links[linkKey{p.ID, f.ID}] == nil {
+		return 0, fmt.Errorf("file %s is not announced by peer %s", fileHash, peerLibp2pID)
+	}
+	delete(r.links, linkKey{p.ID, f.ID})
+	remaining := len(r.linksForFile(f.ID))
+	if remaining == 0 {
+		r.deleteFile(f.ID)
+	}
+	return remaining, nil
+}
+
+// un files ko hatata hai jinhe koi online peer announce nahi kar raha
+func (r *Repository) CleanupOrphanedFiles(ctx context.Context, grace time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-grace)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed int64
+	for id := range r.files {
+		recent := false
+		for _, l := range r.linksForFile(id) {
+			if p := r.peerByDBID(l.peerID); p != nil && (p.IsOnline || !p.LastSeen.Before(cutoff)) {
+				recent = true
+				break
+			}
+		}
+		if !recent {
+			r.deleteFile(id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// woh files jinke saare peers offline hai aur threshold se zyada der se nahi dikhe, sabse purane pehle
+func (r *Repository) GetFilesNeedingReseed(ctx context.Context, threshold time.Duration) ([]db.AtRiskFile, error) {
+	cutoff := time.Now().Add(-threshold)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var files []db.AtRiskFile
+	for id, f := range r.files {
+		links := r.linksForFile(id)
+		if len(links) == 0 {
+			continue
+		}
+		risk := db.AtRiskFile{FileHash: f.FileHash, Filename: f.Filename, FileSize: f.FileSize}
+		online := false
+		for _, l := range links {
+			p := r.peerByDBID(l.peerID)
+			if p == nil {
+				continue
+			}
+			risk.Seeders++
+			online = online || p.IsOnline
+			if p.LastSeen.After(risk.LastSeen) {
+				risk.LastSeen = p.LastSeen
+			}
+		}
+		if !online && risk.LastSeen.Before(cutoff) {
+			files = append(files, risk)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].LastSeen.Before(files[j].LastSeen) })
+	return files, nil
+}
+
+// file ke online peers unke trust score ke saath
+func (r *Repository) FindOnlineFilePeersByID(ctx context.Context, fileID uuid.UUID) ([]db.PeerFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []db.PeerFile
+	for _, l := range r.linksForFile(fileID) {
+		p := r.peerByDBID(l.peerID)
+		if p == nil || !p.IsOnline {
+			continue
+		}
+		score, ok := r.scores[p.ID]
+		if !ok {
+			score = 0.5
+		}
+		out = append(out, db.PeerFile{ID: l.id, PeerID: l.peerID, FileID: l.fileID, AnnouncedAt: l.announcedAt, Score: score})
+	}
+	return out, nil
+}
+
+// file par tag lagata hai, pehle se ho toh kuch nahi
+func (r *Repository) AddTag(ctx context.Context, fileHash, tag string) error {
+	tag, err := db.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.fileByHash(fileHash)
+	if f == nil {
+		return fmt.Errorf("file %s not found", fileHash)
+	}
+	if !slices.Contains(f.Tags, tag) {
+		f.Tags = append(f.Tags, tag)
+	}
+	return nil
+}
+
+// file se tag hatata hai
+func (r *Repository) RemoveTag(ctx context.Context, fileHash, tag string) error {
+	tag, err := db.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.fileByHash(fileHash)
+	if f == nil {
+		return fmt.Errorf("file %s not found", fileHash)
+	}
+	f.Tags = slices.DeleteFunc(f.Tags, func(t string) bool { return t == tag })
+	return nil
+}
+
+// tag wali saari files, sabse nayi pehle
+func (r *Repository) SearchByTag(ctx context.Context, tag string) ([]db.File, error) {
+	tag, err := db.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var files []db.File
+	for _, f := range r.files {
+		if slices.Contains(f.Tags, tag) {
+			files = append(files, cloneFile(f))
+		}
+	}
+	sortNewestFirst(files)
+	return files, nil
+}
+
+// bannedBy ki ban list mein peerID daalta hai (duration <= 0 ho toh permanent), pehle se ho toh update
+func (r *Repository) BanPeer(ctx context.Context, bannedBy, peerID, reason string, duration time.Duration) error {
+	ban := db.PeerBan{BannedBy: bannedBy, BannedPeerID: peerID, Reason: reason, BannedAt: time.Now()}
+	if duration > 0 {
+		t := ban.BannedAt.Add(duration)
+		ban.ExpiresAt = &t
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bans[banKey{bannedBy, peerID}] = ban
+	return nil
+}
+
+// bannedBy ki ban list se peerID hatata hai
+func (r *Repository) UnbanPeer(ctx context.Context, bannedBy, peerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := banKey{bannedBy, peerID}
+	if _, ok := r.bans[key]; !ok {
+		return fmt.Errorf("peer %s is not banned", peerID)
+	}
+	delete(r.bans, key)
+	return nil
+}
+
+// bannedBy ke active bans, purane pehle
+func (r *Repository) ListBans(ctx context.Context, bannedBy string) ([]db.PeerBan, error) {
+	now := time.Now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var bans []db.PeerBan
+	for _, b := range r.bans {
+		if b.BannedBy == bannedBy && (b.ExpiresAt == nil || b.ExpiresAt.After(now)) {
+			bans = append(bans, b)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].BannedAt.Before(bans[j].BannedAt) })
+	return bans, nil
+}
+
+// peerID ko fileHash ki allow list mein daalta hai
+func (r *Repository) AllowPeer(ctx context.Context, fileHash, peerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.access[fileHash] == nil {
+		r.access[fileHash] = make(map[string]bool)
+	}
+	r.access[fileHash][peerID] = true
+	return nil
+}
+
+// peerID ko fileHash ki allow list se hatata hai
+func (r *Repository) DenyPeer(ctx context.Context, fileHash, peerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.access[fileHash][peerID] {
+		return fmt.Errorf("peer %s is not in the allow list", peerID)
+	}
+	delete(r.access[fileHash], peerID)
+	if len(r.access[fileHash]) == 0 {
+		delete(r.access, fileHash)
+	}
+	return nil
+}
+
+// allow list khaali ho ya peerID usmein ho toh true
+func (r *Repository) IsAllowed(ctx context.Context, fileHash, peerID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := r.access[fileHash]
+	return len(list) == 0 || list[peerID], nil
+}
+
+// peer ke uploaded bytes mein n jodta hai
+func (r *Repository) AddBytesUploaded(ctx context.Context, peerLibp2pID string, n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return fmt.Errorf("peer %s not found", peerLibp2pID)
+	}
+	r.uploaded[p.ID] += n
+	return nil
+}
+
+// StatsSnapshot files, peers aur uploaded bytes ke totals
+func (r *Repository) StatsSnapshot(ctx context.Context) (*db.NetworkStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s := &db.NetworkStats{TotalFiles: len(r.files)}
+	for _, f := range r.files {
+		s.TotalSize += f.FileSize
+	}
+	for _, p := range r.peers {
+		if p.IsOnline {
+			s.OnlinePeers++
+		} else {
+			s.OfflinePeers++
+		}
+	}
+	for _, n := range r.uploaded {
+		s.TotalBytesTransferred += n
+	}
+	return s, nil
+}
+
+// sabse zyada upload karne wale peers, sabse zyada pehle
+func (r *Repository) GetTopSeeders(ctx context.Context, limit int) ([]db.SeederRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var seeders []db.SeederRecord
+	for id, bytes := range r.uploaded {
+		p := r.peerByDBID(id)
+		if p == nil {
+			continue
+		}
+		s := db.SeederRecord{PeerID: p.PeerID, BytesUploaded: bytes, LastSeen: p.LastSeen}
+		if s.LastSeen.IsZero() {
+			s.LastSeen = p.CreatedAt
+		}
+		for k := range r.links {
+			if k.peerID == id {
+				s.FilesShared++
+			}
+		}
+		seeders = append(seeders, s)
+	}
+	sort.Slice(seeders, func(i, j int) bool { return seeders[i].BytesUploaded > seeders[j].BytesUploaded })
+	if limit >= 0 && len(seeders) > limit {
+		seeders = seeders[:limit]
+	}
+	return seeders, nil
+}
+
+// file ke saare pieces store karta hai, purane pieces replace ho jaate hai
+func (r *Repository) SetFilePieces(ctx context.Context, fileHash string, pieces []db.FilePiece) error {
+	m := make(map[int]db.FilePiece, len(pieces))
+	for _, p := range pieces {
+		p.FileHash = fileHash
+		p.PieceHash = slices.Clone(p.PieceHash)
+		m[p.PieceIndex] = p
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pieces[fileHash] = m
+	return nil
+}
+
+// file ka ek piece
+func (r *Repository) GetFilePiece(ctx context.Context, fileHash string, pieceIndex int) (*db.FilePiece, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.pieces[fileHash][pieceIndex]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	p.PieceHash = slices.Clone(p.PieceHash)
+	return &p, nil
+}
+
+// file ke ek piece ka SHA-1 hash
+func (r *Repository) GetPieceHash(ctx context.Context, fileHash string, pieceIndex int) ([]byte, error) {
+	piece, err := r.GetFilePiece(ctx, fileHash, pieceIndex)
+	if err != nil {
+		return nil, err
+	}
+	return piece.PieceHash, nil
+}
+
+// file ke woh piece indices jo have mein nahi hai, order mein
+func (r *Repository) GetMissingPieces(ctx context.Context, fileHash string, have []int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var missing []int
+	for idx := range r.pieces[fileHash] {
+		if !slices.Contains(have, idx) {
+			missing = append(missing, idx)
+		}
+	}
+	slices.Sort(missing)
+	return missing, nil
+}
+
+// GetVerifiedPieces peer ke pehle verify kiye pieces; modTime alag ho toh file ka cache hat jaata hai
+func (r *Repository) GetVerifiedPieces(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return nil, nil
+	}
+	key := cacheKey{peerID: p.ID, fileHash: fileHash}
+	v, ok := r.verified[key]
+	if !ok {
+		return nil, nil
+	}
+	if !v.modTime.Equal(modTime.UTC().Truncate(time.Microsecond)) {
+		delete(r.verified, key)
+		return nil, nil
+	}
+	var pieces []int
+	for idx := range v.pieces {
+		pieces = append(pieces, idx)
+	}
+	slices.Sort(pieces)
+	return pieces, nil
+}
+
+// MarkPiecesVerified peer ke verify kiye pieces modTime ke saath yaad rakhta hai
+func (r *Repository) MarkPiecesVerified(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time, pieces []int) error {
+	if len(pieces) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return fmt.Errorf("peer %s not found", peerLibp2pID)
+	}
+	modTime = modTime.UTC().Truncate(time.Microsecond)
+	key := cacheKey{peerID: p.ID, fileHash: fileHash}
+	v, ok := r.verified[key]
+	if !ok {
+		v = &verifiedPieces{pieces: make(map[int]time.Time)}
+		r.verified[key] = v
+	}
+	// naye modTime par purane pieces bekaar hai (SQL mein woh agle GetVerifiedPieces par delete hote hai)
+	if !v.modTime.Equal(modTime) {
+		v.modTime = modTime
+		v.pieces = make(map[int]time.Time)
+	}
+	now := time.Now()
+	for _, idx := range pieces {
+		v.pieces[idx] = now
+	}
+	return nil
+}
+
+// AddGossipFiles peer ki gossip files mein naye hashes jodta hai, kul limit tak
+func (r *Repository) AddGossipFiles(ctx context.Context, peerLibp2pID string, files []db.GossipFile, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return 0, nil
+	}
+	saved := r.gossip[p.ID]
+	added := 0
+	now := time.Now()
+	for _, f := range files {
+		if len(saved) >= limit {
+			break
+		}
+		if slices.ContainsFunc(saved, func(g db.GossipFile) bool { return g.FileHash == f.FileHash }) {
+			continue
+		}
+		f.LearnedAt = now
+		saved = append(saved, f)
+		added++
+	}
+	r.gossip[p.ID] = saved
+	return added, nil
+}
+
+// ListGossipFiles peer ki gossip files, sabse purani pehle
+func (r *Repository) ListGossipFiles(ctx context.Context, peerLibp2pID string) ([]db.GossipFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.peers[peerLibp2pID]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(r.gossip[p.ID]), nil
+}
+
+// peer ka audit event likhta hai
+func (r *Repository) RecordPeerEvent(ctx context.Context, peerID, eventType string, metadata interface{}) error {
+	var data json.RawMessage
+	if metadata != nil {
+		var err error
+		if data, err = json.Marshal(metadata); err != nil {
+			return fmt.Errorf("failed to encode event metadata: %w", err)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextEvent++
+	r.events = append(r.events, db.PeerHistoryRecord{ID: r.nextEvent, PeerID: peerID, EventType: eventType, Metadata: data, OccurredAt: time.Now()})
+	return nil
+}
+
+// peer ke sabse naye limit events, naye pehle
+func (r *Repository) GetPeerHistory(ctx context.Context, peerID string, limit int) ([]db.PeerHistoryRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var history []db.PeerHistoryRecord
+	for i := len(r.events) - 1; i >= 0 && (limit < 0 || len(history) < limit); i-- {
+		if r.events[i].PeerID == peerID {
+			history = append(history, r.events[i])
+		}
+	}
+	return history, nil
+}
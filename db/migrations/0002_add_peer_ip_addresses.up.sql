@@ -0,0 +1,2 @@
+ALTER TABLE peers ADD COLUMN IF NOT EXISTS ip_address TEXT;
+ALTER TABLE peers ADD COLUMN IF NOT EXISTS ipv6_address TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS info_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_files_info_hash ON files(info_hash);
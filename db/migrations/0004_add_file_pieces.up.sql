@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS file_pieces (
+    file_hash TEXT NOT NULL,
+    piece_index INT NOT NULL,
+    piece_hash BYTEA NOT NULL,
+    length BIGINT NOT NULL,
+    PRIMARY KEY (file_hash, piece_index)
+);
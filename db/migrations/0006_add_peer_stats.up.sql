@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS peer_stats (
+    peer_id UUID PRIMARY KEY REFERENCES peers(id) ON DELETE CASCADE,
+    bytes_uploaded BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_peer_stats_bytes_uploaded ON peer_stats(bytes_uploaded DESC);
@@ -0,0 +1,2 @@
+-- peer ka apne (file_hash + peer_id) par signature, announce ke time verify hota hai
+ALTER TABLE peer_files ADD COLUMN IF NOT EXISTS signature BYTEA;
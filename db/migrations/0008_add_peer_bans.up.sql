@@ -0,0 +1,9 @@
+-- har peer apni ban list rakhta hai; expires_at NULL ho toh ban permanent hai
+CREATE TABLE IF NOT EXISTS peer_bans (
+    banned_by TEXT NOT NULL,
+    banned_peer_id TEXT NOT NULL,
+    reason TEXT,
+    banned_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (banned_by, banned_peer_id)
+);
@@ -0,0 +1,9 @@
+-- peers ka audit trail: connect/disconnect, file announce/remove aur transfers
+CREATE TABLE IF NOT EXISTS events (
+    id SERIAL PRIMARY KEY,
+    peer_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    metadata JSONB,
+    occurred_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_events_peer_id_occurred_at ON events(peer_id, occurred_at DESC);
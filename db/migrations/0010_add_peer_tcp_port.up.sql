@@ -0,0 +1 @@
+ALTER TABLE peers ADD COLUMN IF NOT EXISTS tcp_port INTEGER;
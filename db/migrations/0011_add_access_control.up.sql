@@ -0,0 +1,7 @@
+-- file ki allow list; kisi file_hash ki koi row na ho toh file sab ke liye khuli hai
+CREATE TABLE IF NOT EXISTS access_control (
+    file_hash TEXT NOT NULL,
+    allowed_peer_id TEXT NOT NULL,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (file_hash, allowed_peer_id)
+);
@@ -0,0 +1,9 @@
+-- peer ne apni disk par jo pieces verify kar liye hai; file_mtime badle toh rows purani maani jaati hai
+CREATE TABLE IF NOT EXISTS piece_cache (
+    peer_id UUID NOT NULL REFERENCES peers(id) ON DELETE CASCADE,
+    file_hash TEXT NOT NULL,
+    piece_index INTEGER NOT NULL,
+    file_mtime TIMESTAMP WITH TIME ZONE NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (peer_id, file_hash, piece_index)
+);
@@ -0,0 +1,11 @@
+-- GOSSIP_FILES se peer ko pata chali files (jo usne khud announce nahi ki), taaki `list` network ka bada view dikhaye.
+-- origin_peer_id woh peer hai jiske paas file hai, jaisa gossip bhejne wale ne bataya; verify nahi hota.
+CREATE TABLE IF NOT EXISTS gossip_files (
+    peer_id UUID NOT NULL REFERENCES peers(id) ON DELETE CASCADE,
+    file_hash TEXT NOT NULL,
+    filename TEXT NOT NULL,
+    file_size BIGINT NOT NULL,
+    origin_peer_id TEXT NOT NULL,
+    learned_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (peer_id, file_hash)
+);
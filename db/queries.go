@@ -2,30 +2,52 @@ package db
 
 import (
 	"context"
-
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// repository struct mein saare DB operations hai
+// DBTX pgx ke woh methods hai jo Repository ki queries use karti hai. *pgxpool.Pool ise satisfy karta hai,
+// aur tests mein pgxmock ka pool, taaki queries bina Postgres ke check ho sake.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// repository struct mein saare DB operations hai.
+// Saari queries seedha pgx par chalti hai (database/sql ka stdlib wrapper use nahi hota).
 type Repository struct {
-	DB *pgxpool.Pool
+	DB DBTX
 }
 
-// ek naya repo bna rha hai (say for a new user)
+// ek naya repo bna rha hai (say for a new user); pool InitDB se aata hai
 func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{DB: db}
 }
 
+// HealthCheck database par `SELECT 1` chala kar dekhta hai ki connection zinda hai (liveness probes ke liye)
+func (r *Repository) HealthCheck(ctx context.Context) error {
+	var one int
+	if err := r.DB.QueryRow(ctx, `SELECT 1`).Scan(&one); err != nil {
+		return fmt.Errorf("database health check failed: %w", err)
+	}
+	return nil
+}
+
 // yeh combined function hai insert + update = upsert (insert new peer and update if already exists)
 func (r *Repository) UpsertPeer(ctx context.Context, peerID, name string, multiaddrs []string) (uuid.UUID, error) {
+	if err := ValidatePeerAddrs(peerID, multiaddrs); err != nil {
+		return uuid.Nil, err
+	}
 	now := time.Now()
 	var peerUUID uuid.UUID
 
@@ -83,6 +105,56 @@ func (r *Repository) UpsertPeer(ctx context.Context, peerID, name string, multia
 	return peerUUID, nil
 }
 
+// BatchUpsertPeers bahut saare peers ek hi query mein upsert karta hai (UpsertPeer jaisa: naye peers insert,
+// purane online mark + multiaddrs update, naye peers ka trust score 0.50). Har peer ke liye alag round-trip nahi hota:
+// saare columns arrays ki tarah jaate hai aur UNNEST se rows bante hai. Ek peer_id do baar ho toh aakhri entry jeet-ti hai.
+func (r *Repository) BatchUpsertPeers(ctx context.Context, peers []PeerRecord) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	// ON CONFLICT ek statement mein ek row ko do baar update nahi kar sakta, isliye duplicates pehle hata dete hai
+	index := make(map[string]int, len(peers))
+	var peerIDs, names, multiaddrs []string
+	for _, p := range peers {
+		// text[][] rectangular hona chahiye, isliye har peer ke multiaddrs JSON array ki tarah jaate hai
+		addrs := p.Multiaddrs
+		if addrs == nil {
+			addrs = []string{}
+		}
+		addrsJSON, err := json.Marshal(addrs)
+		if err != nil {
+			return fmt.Errorf("failed to encode multiaddrs for %s: %w", p.PeerID, err)
+		}
+		if i, ok := index[p.PeerID]; ok {
+			names[i] = p.Name
+			multiaddrs[i] = string(addrsJSON)
+			continue
+		}
+		index[p.PeerID] = len(peerIDs)
+		peerIDs = append(peerIDs, p.PeerID)
+		names = append(names, p.Name)
+		multiaddrs = append(multiaddrs, string(addrsJSON))
+	}
+
+	query := `
+		WITH upserted AS (
+			INSERT INTO peers (peer_id, name, multiaddrs, is_online, last_seen, created_at)
+			SELECT u.peer_id, u.name, ARRAY(SELECT jsonb_array_elements_text(u.multiaddrs::jsonb)), true, $4, $4
+			FROM UNNEST($1::text[], $2::text[], $3::text[]) AS u(peer_id, name, multiaddrs)
+			ON CONFLICT (peer_id) DO UPDATE SET is_online = true, last_seen = EXCLUDED.last_seen, multiaddrs = EXCLUDED.multiaddrs
+			RETURNING id
+		)
+		INSERT INTO trust_scores (peer_id, score, updated_at)
+		SELECT id, 0.50, $4 FROM upserted
+		ON CONFLICT (peer_id) DO NOTHING
+	`
+	if _, err := r.DB.Exec(ctx, query, peerIDs, names, multiaddrs, time.Now()); err != nil {
+		return fmt.Errorf("failed to batch upsert %d peers: %w", len(peerIDs), err)
+	}
+	return nil
+}
+
 // FindPeersByIDs returns peer details for specific peer IDs
 func (r *Repository) FindPeersByIDs(ctx context.Context, peerIDs []string) ([]Peer, error) {
 	if len(peerIDs) == 0 {
@@ -98,7 +170,7 @@ func (r *Repository) FindPeersByIDs(ctx context.Context, peerIDs []string) ([]Pe
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, peer_id, name, multiaddrs, is_online, last_seen, created_at 
+		SELECT id, peer_id, name, multiaddrs, COALESCE(ip_address, ''), COALESCE(ipv6_address, ''), COALESCE(tcp_port, 0), is_online, last_seen, created_at 
 		FROM peers 
 		WHERE peer_id IN (%s)
 	`, strings.Join(placeholders, ","))
@@ -112,7 +184,7 @@ func (r *Repository) FindPeersByIDs(ctx context.Context, peerIDs []string) ([]Pe
 	var peers []Peer
 	for rows.Next() {
 		var peer Peer
-		if err := rows.Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt); err != nil {
+		if err := rows.Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IPAddress, &peer.IPv6, &peer.TCPPort, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt); err != nil {
 			return nil, err
 		}
 		peers = append(peers, peer)
@@ -123,7 +195,7 @@ func (r *Repository) FindPeersByIDs(ctx context.Context, peerIDs []string) ([]Pe
 
 // currently online peers ko return karta hai
 func (r *Repository) FindOnlinePeers(ctx context.Context) ([]Peer, error) {
-	query := `SELECT id, peer_id, name, multiaddrs, is_online, last_seen, created_at FROM peers WHERE is_online = true`
+	query := `SELECT id, peer_id, name, multiaddrs, COALESCE(ip_address, ''), COALESCE(ipv6_address, ''), COALESCE(tcp_port, 0), is_online, last_seen, created_at FROM peers WHERE is_online = true`
 	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -133,7 +205,7 @@ func (r *Repository) FindOnlinePeers(ctx context.Context) ([]Peer, error) {
 	var peers []Peer
 	for rows.Next() {
 		var peer Peer
-		if err := rows.Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt); err != nil {
+		if err := rows.Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IPAddress, &peer.IPv6, &peer.TCPPort, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt); err != nil {
 			return nil, err
 		}
 		peers = append(peers, peer)
@@ -141,6 +213,20 @@ func (r *Repository) FindOnlinePeers(ctx context.Context) ([]Peer, error) {
 	return peers, rows.Err()
 }
 
+// peer ke IPv4 aur IPv6 addresses update karta hai (empty string NULL store hoti hai)
+func (r *Repository) UpdatePeerIPs(ctx context.Context, peerID, ipv4, ipv6 string) error {
+	_, err := r.DB.Exec(ctx,
+		`UPDATE peers SET ip_address = NULLIF($1, ''), ipv6_address = NULLIF($2, '') WHERE peer_id = $3`,
+		ipv4, ipv6, peerID)
+	return err
+}
+
+// peer ka TCP fallback port update karta hai (0 NULL store hota hai, yaani TCP fallback band hai)
+func (r *Repository) UpdatePeerTCPPort(ctx context.Context, peerID string, port int) error {
+	_, err := r.DB.Exec(ctx, `UPDATE peers SET tcp_port = NULLIF($1, 0) WHERE peer_id = $2`, port, peerID)
+	return err
+}
+
 // Jab koi peer disconnect kare, use offline mark karne ke liye
 func (r *Repository) SetPeerOffline(ctx context.Context, peerID string) error {
 	now := time.Now()
@@ -157,10 +243,30 @@ func (r *Repository) MarkAllPeersOffline(ctx context.Context) error {
 	return err
 }
 
+// Peer ki full info return karta hai libp2p peer ID ke basis par
+func (r *Repository) GetPeerByID(ctx context.Context, peerID string) (*Peer, error) {
+	var peer Peer
+	err := r.DB.QueryRow(ctx, `SELECT id, peer_id, name, multiaddrs, COALESCE(ip_address, ''), COALESCE(ipv6_address, ''), COALESCE(tcp_port, 0), is_online, last_seen, created_at FROM peers WHERE peer_id = $1`, peerID).Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IPAddress, &peer.IPv6, &peer.TCPPort, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &peer, nil
+}
+
+// IP address (IPv4 ya IPv6) se peer dhundhta hai, multiple matches mein sabse recently seen wala return hota hai
+func (r *Repository) GetPeerByIP(ctx context.Context, ip string) (*Peer, error) {
+	var peer Peer
+	err := r.DB.QueryRow(ctx, `SELECT id, peer_id, name, multiaddrs, COALESCE(ip_address, ''), COALESCE(ipv6_address, ''), COALESCE(tcp_port, 0), is_online, last_seen, created_at FROM peers WHERE ip_address = $1 OR ipv6_address = $1 ORDER BY last_seen DESC NULLS LAST LIMIT 1`, ip).Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IPAddress, &peer.IPv6, &peer.TCPPort, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &peer, nil
+}
+
 // Peer ki full info return karta hai DB ID ke basis par
 func (r *Repository) GetPeerInfoByDBID(ctx context.Context, peerDBID uuid.UUID) (*Peer, error) {
 	var peer Peer
-	err := r.DB.QueryRow(ctx, `SELECT id, peer_id, name, multiaddrs, is_online, last_seen, created_at FROM peers WHERE id = $1`, peerDBID).Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt)
+	err := r.DB.QueryRow(ctx, `SELECT id, peer_id, name, multiaddrs, COALESCE(ip_address, ''), COALESCE(ipv6_address, ''), COALESCE(tcp_port, 0), is_online, last_seen, created_at FROM peers WHERE id = $1`, peerDBID).Scan(&peer.ID, &peer.PeerID, &peer.Name, &peer.Multiaddrs, &peer.IPAddress, &peer.IPv6, &peer.TCPPort, &peer.IsOnline, &peer.LastSeen, &peer.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -190,9 +296,74 @@ func (r *Repository) InsertFile(ctx context.Context, fileHash, filename string,
 	return fileID, err
 }
 
+// file ka BitTorrent info-hash set karta hai (pehle se set ho toh overwrite ho jaata hai)
+func (r *Repository) SetFileInfoHash(ctx context.Context, fileID uuid.UUID, infoHash string) error {
+	_, err := r.DB.Exec(ctx, `UPDATE files SET info_hash = $1 WHERE id = $2`, infoHash, fileID)
+	return err
+}
+
+// info-hash se file dhundhta hai, taaki standard BitTorrent clients ke announces match ho sake
+func (r *Repository) GetFileByInfoHash(ctx context.Context, infoHash string) (*File, error) {
+	var file File
+	err := r.DB.QueryRow(ctx,
+		`SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files WHERE info_hash = $1`,
+		infoHash).Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// ID se file dhundhta hai
+func (r *Repository) GetFileByID(ctx context.Context, fileID uuid.UUID) (*File, error) {
+	var file File
+	err := r.DB.QueryRow(ctx,
+		`SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files WHERE id = $1`,
+		fileID).Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// content hash se file dhundhta hai
+func (r *Repository) GetFileByHash(ctx context.Context, fileHash string) (*File, error) {
+	var file File
+	err := r.DB.QueryRow(ctx,
+		`SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files WHERE file_hash = $1`,
+		fileHash).Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// check karta hai ki peer ne yeh file announce ki hai ya nahi
+func (r *Repository) IsFileAnnouncedBy(ctx context.Context, fileID uuid.UUID, peerLibp2pID string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM peer_files pf JOIN peers p ON p.id = pf.peer_id
+            WHERE pf.file_id = $1 AND p.peer_id = $2
+        )`, fileID, peerLibp2pID).Scan(&exists)
+	return exists, err
+}
+
+// filename se file dhundhta hai, agar same naam ki multiple files hai toh sabse nayi return karta hai
+func (r *Repository) GetFileByName(ctx context.Context, filename string) (*File, error) {
+	var file File
+	err := r.DB.QueryRow(ctx,
+		`SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files WHERE filename = $1 ORDER BY created_at DESC LIMIT 1`,
+		filename).Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
 // Tracker par available saari files ka list deta hai
 func (r *Repository) FindAllFiles(ctx context.Context) ([]File, error) {
-	query := `SELECT id, file_hash, filename, file_size, content_type, created_at FROM files ORDER BY created_at DESC`
+	query := `SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files ORDER BY created_at DESC`
 	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -202,7 +373,103 @@ func (r *Repository) FindAllFiles(ctx context.Context) ([]File, error) {
 	var files []File
 	for rows.Next() {
 		var file File
-		if err := rows.Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.CreatedAt); err != nil {
+		if err := rows.Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// poora file catalog bina pagination ke: har file aur uske har announcing peer ki ek row.
+// Jis file ka koi peer nahi hai woh bhi empty peer ID ke saath aati hai.
+func (r *Repository) ListAllFiles(ctx context.Context) ([]CatalogEntry, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT f.file_hash, f.filename, f.file_size, COALESCE(p.peer_id, ''), f.content_type, f.created_at
+        FROM files f
+        LEFT JOIN peer_files pf ON pf.file_id = f.id
+        LEFT JOIN peers p ON p.id = pf.peer_id
+        ORDER BY f.created_at DESC, p.peer_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []CatalogEntry
+	for rows.Next() {
+		var e CatalogEntry
+		if err := rows.Scan(&e.FileHash, &e.Filename, &e.FileSize, &e.PeerID, &e.ContentType, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// files ka ek page deta hai (sabse nayi pehle) aur saath mein total files ka count.
+// Count aur page query dono alag connections par ek saath chalte hai.
+func (r *Repository) ListFilesPage(ctx context.Context, offset, limit int) ([]File, int, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var total int
+	countErr := make(chan error, 1)
+	go func() {
+		countErr <- r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM files`).Scan(&total)
+	}()
+
+	query := `SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files
+        ORDER BY created_at DESC, id LIMIT $1 OFFSET $2`
+	rows, err := r.DB.Query(ctx, query, limit, offset)
+	if err != nil {
+		<-countErr
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var files []File
+	for rows.Next() {
+		var file File
+		if err := rows.Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt); err != nil {
+			<-countErr
+			return nil, 0, err
+		}
+		files = append(files, file)
+	}
+	if err := rows.Err(); err != nil {
+		<-countErr
+		return nil, 0, err
+	}
+	if err := <-countErr; err != nil {
+		return nil, 0, err
+	}
+	return files, total, nil
+}
+
+// ek peer ne jo files announce ki hai unki list deta hai, sabse recently announced pehle
+func (r *Repository) GetFilesByPeer(ctx context.Context, peerLibp2pID string) ([]File, error) {
+	query := `
+        SELECT f.id, f.file_hash, f.filename, f.file_size, f.content_type, f.info_hash, f.tags, f.created_at, pf.announced_at
+        FROM files f
+        JOIN peer_files pf ON pf.file_id = f.id
+        JOIN peers p ON pf.peer_id = p.id
+        WHERE p.peer_id = $1
+        ORDER BY pf.announced_at DESC
+    `
+	rows, err := r.DB.Query(ctx, query, peerLibp2pID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []File
+	for rows.Next() {
+		var file File
+		if err := rows.Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt, &file.AnnouncedAt); err != nil {
 			return nil, err
 		}
 		files = append(files, file)
@@ -233,13 +500,523 @@ func (r *Repository) InsertPeerFile(ctx context.Context, peerLibp2pID string, fi
     `
 	err = r.DB.QueryRow(ctx, query, peerUUID, fileID, time.Now()).Scan(&peerFileID)
 	if err != nil {
-		log.Printf("[Repository] InsertPeerFile error for peerUUID %s and fileID %s: %v", peerUUID, fileID, err)
+		logger.Error("InsertPeerFile failed", "peer_uuid", peerUUID, "file_id", fileID, "error", err)
 		return uuid.Nil, err
 	}
 
 	return peerFileID, nil
 }
 
+// peer-file link par announcement ka signature store karta hai
+func (r *Repository) SetAnnouncementSignature(ctx context.Context, fileID uuid.UUID, peerLibp2pID string, sig []byte) error {
+	_, err := r.DB.Exec(ctx, `
+        UPDATE peer_files SET signature = $3
+        WHERE file_id = $1 AND peer_id = (SELECT id FROM peers WHERE peer_id = $2)`,
+		fileID, peerLibp2pID, sig)
+	return err
+}
+
+// peer ke announcement ka stored signature deta hai, signature na ho toh nil
+func (r *Repository) GetAnnouncementSignature(ctx context.Context, fileHash, peerLibp2pID string) ([]byte, error) {
+	var sig []byte
+	err := r.DB.QueryRow(ctx, `
+        SELECT pf.signature FROM peer_files pf
+        JOIN files f ON f.id = pf.file_id
+        JOIN peers p ON p.id = pf.peer_id
+        WHERE f.file_hash = $1 AND p.peer_id = $2`, fileHash, peerLibp2pID).Scan(&sig)
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// peer ka kisi file ka announcement hata deta hai (sirf wahi peer apni entry hata sakta hai).
+// Agar koi aur peer file announce nahi kar raha toh file ka record bhi delete ho jaata hai.
+// Return value batata hai ki ab kitne dusre peers is file ko announce kar rahe hai.
+func (r *Repository) RemoveFile(ctx context.Context, fileHash, peerLibp2pID string) (int, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var fileID uuid.UUID
+	err = tx.QueryRow(ctx, `
+        DELETE FROM peer_files pf
+        USING files f, peers p
+        WHERE pf.file_id = f.id AND pf.peer_id = p.id
+          AND f.file_hash = $1 AND p.peer_id = $2
+        RETURNING pf.file_id`, fileHash, peerLibp2pID).Scan(&fileID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("file %s is not announced by peer %s", fileHash, peerLibp2pID)
+	} else if err != nil {
+		return 0, err
+	}
+
+	var remaining int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM peer_files WHERE file_id = $1`, fileID).Scan(&remaining); err != nil {
+		return 0, err
+	}
+	if remaining == 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM files WHERE id = $1`, fileID); err != nil {
+			return 0, err
+		}
+	}
+	return remaining, tx.Commit(ctx)
+}
+
+// CleanupOrphanedFiles un files ko delete karta hai jinka koi announcing peer online nahi hai aur grace period
+// ke andar dikha bhi nahi (jaise peer bina cleanup ke hamesha ke liye chala gaya), ya jinka koi announcing peer hi nahi.
+// grace at-risk threshold se lamba hona chahiye, warna GetFilesNeedingReseed ki files dikhne se pehle hi hat jaati hai.
+// Delete hui files ki sankhya return karta hai.
+func (r *Repository) CleanupOrphanedFiles(ctx context.Context, grace time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-grace)
+	tag, err := r.DB.Exec(ctx, `
+        DELETE FROM files f
+        WHERE NOT EXISTS (
+            SELECT 1 FROM peer_files pf
+            JOIN peers p ON pf.peer_id = p.id
+            WHERE pf.file_id = f.id AND (p.is_online = true OR p.last_seen >= $1)
+        )`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetFilesNeedingReseed woh files return karta hai jinke saare announcing peers offline hai aur threshold se
+// zyada der se nahi dikhe. Aisi files jaldi unavailable ho sakti hai, isliye operators inhe dobara seed kar sakte hai.
+// Jin files ka koi peer hi nahi hai woh yahan nahi aati; grace period se zyada purani files CleanupOrphanedFiles hata deta hai.
+func (r *Repository) GetFilesNeedingReseed(ctx context.Context, threshold time.Duration) ([]AtRiskFile, error) {
+	cutoff := time.Now().Add(-threshold)
+	rows, err := r.DB.Query(ctx, `
+        SELECT f.file_hash, f.filename, f.file_size, COUNT(p.id) AS seeders, MAX(p.last_seen) AS last_seen
+        FROM files f
+        JOIN peer_files pf ON pf.file_id = f.id
+        JOIN peers p ON p.id = pf.peer_id
+        GROUP BY f.id, f.file_hash, f.filename, f.file_size
+        HAVING NOT bool_or(p.is_online) AND MAX(p.last_seen) < $1
+        ORDER BY last_seen`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []AtRiskFile
+	for rows.Next() {
+		var f AtRiskFile
+		if err := rows.Scan(&f.FileHash, &f.Filename, &f.FileSize, &f.Seeders, &f.LastSeen); err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// file par ek tag lagata hai, tag pehle se ho toh kuch nahi hota
+func (r *Repository) AddTag(ctx context.Context, fileHash, tag string) error {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	res, err := r.DB.Exec(ctx,
+		`UPDATE files SET tags = array_append(tags, $2) WHERE file_hash = $1 AND NOT (tags @> ARRAY[$2::text])`,
+		fileHash, tag)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		// ho sakta hai tag pehle se laga ho, ya file hi na ho
+		var exists bool
+		if err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM files WHERE file_hash = $1)`, fileHash).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("file %s not found", fileHash)
+		}
+	}
+	return nil
+}
+
+// file se ek tag hata deta hai
+func (r *Repository) RemoveTag(ctx context.Context, fileHash, tag string) error {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	res, err := r.DB.Exec(ctx, `UPDATE files SET tags = array_remove(tags, $2) WHERE file_hash = $1`, fileHash, tag)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("file %s not found", fileHash)
+	}
+	return nil
+}
+
+// diye gaye tag wali saari files, sabse nayi pehle (tags par GIN index use hota hai)
+func (r *Repository) SearchByTag(ctx context.Context, tag string) ([]File, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.DB.Query(ctx,
+		`SELECT id, file_hash, filename, file_size, content_type, info_hash, tags, created_at FROM files WHERE tags @> ARRAY[$1::text] ORDER BY created_at DESC`,
+		tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []File
+	for rows.Next() {
+		var file File
+		if err := rows.Scan(&file.ID, &file.FileHash, &file.Filename, &file.FileSize, &file.ContentType, &file.InfoHash, &file.Tags, &file.CreatedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// NormalizeTag tag ko lowercase karta hai taaki "Video" aur "video" same ho; khaali ya space/comma wale tags invalid hai
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || strings.ContainsAny(tag, " \t,") {
+		return "", fmt.Errorf("invalid tag %q", tag)
+	}
+	return tag, nil
+}
+
+// bannedBy ki ban list mein peerID ko duration ke liye add karta hai (duration <= 0 ho toh permanent).
+// Pehle se ban ho toh reason aur expiry update ho jaate hai.
+func (r *Repository) BanPeer(ctx context.Context, bannedBy, peerID, reason string, duration time.Duration) error {
+	var expiresAt *time.Time
+	if duration > 0 {
+		t := time.Now().Add(duration)
+		expiresAt = &t
+	}
+	_, err := r.DB.Exec(ctx, `
+        INSERT INTO peer_bans (banned_by, banned_peer_id, reason, banned_at, expires_at)
+        VALUES ($1, $2, $3, NOW(), $4)
+        ON CONFLICT (banned_by, banned_peer_id) DO UPDATE
+        SET reason = EXCLUDED.reason, banned_at = NOW(), expires_at = EXCLUDED.expires_at`,
+		bannedBy, peerID, reason, expiresAt)
+	return err
+}
+
+// bannedBy ki ban list se peerID hata deta hai
+func (r *Repository) UnbanPeer(ctx context.Context, bannedBy, peerID string) error {
+	res, err := r.DB.Exec(ctx, `DELETE FROM peer_bans WHERE banned_by = $1 AND banned_peer_id = $2`, bannedBy, peerID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("peer %s is not banned", peerID)
+	}
+	return nil
+}
+
+// batata hai ki bannedBy ne peerID par abhi valid (expire na hua) ban lagaya hai ya nahi
+func (r *Repository) IsBanned(ctx context.Context, bannedBy, peerID string) (bool, error) {
+	var banned bool
+	err := r.DB.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM peer_bans
+            WHERE banned_by = $1 AND banned_peer_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+        )`, bannedBy, peerID).Scan(&banned)
+	return banned, err
+}
+
+// bannedBy ke saare active (expire na hue) bans
+func (r *Repository) ListBans(ctx context.Context, bannedBy string) ([]PeerBan, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT banned_by, banned_peer_id, COALESCE(reason, ''), banned_at, expires_at
+        FROM peer_bans
+        WHERE banned_by = $1 AND (expires_at IS NULL OR expires_at > NOW())
+        ORDER BY banned_at`, bannedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bans []PeerBan
+	for rows.Next() {
+		var b PeerBan
+		if err := rows.Scan(&b.BannedBy, &b.BannedPeerID, &b.Reason, &b.BannedAt, &b.ExpiresAt); err != nil {
+			return nil, err
+		}
+		bans = append(bans, b)
+	}
+	return bans, rows.Err()
+}
+
+// peerID ko fileHash ki allow list mein daalta hai. Pehli entry ke baad file sirf listed peers download kar sakte hai.
+func (r *Repository) AllowPeer(ctx context.Context, fileHash, peerID string) error {
+	_, err := r.DB.Exec(ctx, `
+        INSERT INTO access_control (file_hash, allowed_peer_id)
+        VALUES ($1, $2)
+        ON CONFLICT (file_hash, allowed_peer_id) DO NOTHING`,
+		fileHash, peerID)
+	return err
+}
+
+// peerID ko fileHash ki allow list se hata deta hai. List khaali ho jaye toh file phir se sab ke liye khuli hai.
+func (r *Repository) DenyPeer(ctx context.Context, fileHash, peerID string) error {
+	res, err := r.DB.Exec(ctx, `DELETE FROM access_control WHERE file_hash = $1 AND allowed_peer_id = $2`, fileHash, peerID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("peer %s is not in the allow list", peerID)
+	}
+	return nil
+}
+
+// batata hai ki peerID fileHash download kar sakta hai: file ki allow list khaali ho ya peerID usmein ho
+func (r *Repository) IsAllowed(ctx context.Context, fileHash, peerID string) (bool, error) {
+	var allowed bool
+	err := r.DB.QueryRow(ctx, `
+        SELECT NOT EXISTS (SELECT 1 FROM access_control WHERE file_hash = $1)
+            OR EXISTS (SELECT 1 FROM access_control WHERE file_hash = $1 AND allowed_peer_id = $2)`,
+		fileHash, peerID).Scan(&allowed)
+	return allowed, err
+}
+
+// peer ke uploaded bytes mein n jodta hai, peer_stats row na ho toh bana deta hai
+func (r *Repository) AddBytesUploaded(ctx context.Context, peerLibp2pID string, n int64) error {
+	res, err := r.DB.Exec(ctx, `
+        INSERT INTO peer_stats (peer_id, bytes_uploaded, updated_at)
+        SELECT id, $2, NOW() FROM peers WHERE peer_id = $1
+        ON CONFLICT (peer_id) DO UPDATE
+        SET bytes_uploaded = peer_stats.bytes_uploaded + EXCLUDED.bytes_uploaded, updated_at = NOW()`,
+		peerLibp2pID, n)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("peer %s not found", peerLibp2pID)
+	}
+	return nil
+}
+
+// StatsSnapshot files, peers aur uploaded bytes ke totals ek hi query mein nikalta hai
+func (r *Repository) StatsSnapshot(ctx context.Context) (*NetworkStats, error) {
+	var s NetworkStats
+	err := r.DB.QueryRow(ctx, `
+        SELECT f.total_files, f.total_size, p.online_peers, p.offline_peers, ps.total_bytes
+        FROM (SELECT COUNT(*) AS total_files, COALESCE(SUM(file_size), 0) AS total_size FROM files) f,
+             (SELECT COUNT(*) FILTER (WHERE COALESCE(is_online, false)) AS online_peers,
+                     COUNT(*) FILTER (WHERE NOT COALESCE(is_online, false)) AS offline_peers FROM peers) p,
+             (SELECT COALESCE(SUM(bytes_uploaded), 0) AS total_bytes FROM peer_stats) ps`).
+		Scan(&s.TotalFiles, &s.TotalSize, &s.OnlinePeers, &s.OfflinePeers, &s.TotalBytesTransferred)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network stats: %w", err)
+	}
+	return &s, nil
+}
+
+// sabse zyada upload karne wale peers, bytes_uploaded ke hisab se (sabse zyada pehle)
+func (r *Repository) GetTopSeeders(ctx context.Context, limit int) ([]SeederRecord, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT p.peer_id, COUNT(DISTINCT f.id), ps.bytes_uploaded, COALESCE(p.last_seen, p.created_at)
+        FROM peer_stats ps
+        JOIN peers p ON p.id = ps.peer_id
+        LEFT JOIN peer_files pf ON pf.peer_id = p.id
+        LEFT JOIN files f ON f.id = pf.file_id
+        GROUP BY p.id, ps.bytes_uploaded
+        ORDER BY ps.bytes_uploaded DESC
+        LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seeders []SeederRecord
+	for rows.Next() {
+		var s SeederRecord
+		if err := rows.Scan(&s.PeerID, &s.FilesShared, &s.BytesUploaded, &s.LastSeen); err != nil {
+			return nil, err
+		}
+		seeders = append(seeders, s)
+	}
+	return seeders, rows.Err()
+}
+
+// file ke saare pieces ek transaction mein store karta hai. Same file dobara announce ho toh purane pieces replace ho jaate hai.
+func (r *Repository) SetFilePieces(ctx context.Context, fileHash string, pieces []FilePiece) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM file_pieces WHERE file_hash = $1`, fileHash); err != nil {
+		return err
+	}
+	batch := &pgx.Batch{}
+	for _, p := range pieces {
+		batch.Queue(`INSERT INTO file_pieces (file_hash, piece_index, piece_hash, length) VALUES ($1, $2, $3, $4)`,
+			fileHash, p.PieceIndex, p.PieceHash, p.Length)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// file ka ek piece (hash aur length) fetch karta hai
+func (r *Repository) GetFilePiece(ctx context.Context, fileHash string, pieceIndex int) (*FilePiece, error) {
+	piece := FilePiece{FileHash: fileHash, PieceIndex: pieceIndex}
+	err := r.DB.QueryRow(ctx,
+		`SELECT piece_hash, length FROM file_pieces WHERE file_hash = $1 AND piece_index = $2`,
+		fileHash, pieceIndex).Scan(&piece.PieceHash, &piece.Length)
+	if err != nil {
+		return nil, err
+	}
+	return &piece, nil
+}
+
+// file ke ek piece ka SHA-1 hash return karta hai
+func (r *Repository) GetPieceHash(ctx context.Context, fileHash string, pieceIndex int) ([]byte, error) {
+	piece, err := r.GetFilePiece(ctx, fileHash, pieceIndex)
+	if err != nil {
+		return nil, err
+	}
+	return piece.PieceHash, nil
+}
+
+// file ke woh piece indices return karta hai jo have mein nahi hai, order mein
+func (r *Repository) GetMissingPieces(ctx context.Context, fileHash string, have []int) ([]int, error) {
+	if have == nil {
+		have = []int{}
+	}
+	rows, err := r.DB.Query(ctx,
+		`SELECT piece_index FROM file_pieces WHERE file_hash = $1 AND NOT (piece_index = ANY($2)) ORDER BY piece_index`,
+		fileHash, have)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []int
+	for rows.Next() {
+		var idx int
+		if err := rows.Scan(&idx); err != nil {
+			return nil, err
+		}
+		missing = append(missing, idx)
+	}
+	return missing, rows.Err()
+}
+
+// GetVerifiedPieces peer ke woh pieces return karta hai jo usne fileHash wali file ke liye pehle verify kiye the.
+// modTime disk par file ka abhi ka modification time hai; kisi aur modTime wali rows (file badal gayi) pehle delete
+// hoti hai, isliye unke pieces dobara verify hote hai.
+func (r *Repository) GetVerifiedPieces(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time) ([]int, error) {
+	// Postgres microseconds tak hi store karta hai
+	modTime = modTime.UTC().Truncate(time.Microsecond)
+	if _, err := r.DB.Exec(ctx, `
+        DELETE FROM piece_cache pc USING peers p
+        WHERE pc.peer_id = p.id AND p.peer_id = $1 AND pc.file_hash = $2 AND pc.file_mtime <> $3`,
+		peerLibp2pID, fileHash, modTime); err != nil {
+		return nil, fmt.Errorf("failed to invalidate piece cache: %w", err)
+	}
+
+	rows, err := r.DB.Query(ctx, `
+        SELECT pc.piece_index FROM piece_cache pc
+        JOIN peers p ON p.id = pc.peer_id
+        WHERE p.peer_id = $1 AND pc.file_hash = $2
+        ORDER BY pc.piece_index`, peerLibp2pID, fileHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pieces []int
+	for rows.Next() {
+		var idx int
+		if err := rows.Scan(&idx); err != nil {
+			return nil, err
+		}
+		pieces = append(pieces, idx)
+	}
+	return pieces, rows.Err()
+}
+
+// MarkPiecesVerified peer ke verify kiye pieces file ke modTime ke saath piece_cache mein likhta hai
+func (r *Repository) MarkPiecesVerified(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time, pieces []int) error {
+	if len(pieces) == 0 {
+		return nil
+	}
+	res, err := r.DB.Exec(ctx, `
+        INSERT INTO piece_cache (peer_id, file_hash, piece_index, file_mtime, verified_at)
+        SELECT p.id, $2, idx, $3, NOW() FROM peers p, UNNEST($4::int[]) AS idx
+        WHERE p.peer_id = $1
+        ON CONFLICT (peer_id, file_hash, piece_index) DO UPDATE
+        SET file_mtime = EXCLUDED.file_mtime, verified_at = NOW()`,
+		peerLibp2pID, fileHash, modTime.UTC().Truncate(time.Microsecond), pieces)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("peer %s not found", peerLibp2pID)
+	}
+	return nil
+}
+
+// AddGossipFiles gossip se peer ko pata chali files gossip_files mein likhta hai. Pehle se saved hash dobara nahi
+// likhte, aur peer ki kul rows limit se upar nahi jaati; zyada files chupchaap chhod di jaati hai.
+// Return value nayi likhi gayi rows ki ginti hai.
+func (r *Repository) AddGossipFiles(ctx context.Context, peerLibp2pID string, files []GossipFile, limit int) (int, error) {
+	if len(files) == 0 {
+		return 0, nil
+	}
+	hashes := make([]string, len(files))
+	names := make([]string, len(files))
+	sizes := make([]int64, len(files))
+	origins := make([]string, len(files))
+	for i, f := range files {
+		hashes[i], names[i], sizes[i], origins[i] = f.FileHash, f.Filename, f.FileSize, f.OriginPeerID
+	}
+	res, err := r.DB.Exec(ctx, `
+        INSERT INTO gossip_files (peer_id, file_hash, filename, file_size, origin_peer_id, learned_at)
+        SELECT p.id, g.file_hash, g.filename, g.file_size, g.origin_peer_id, NOW()
+        FROM peers p, UNNEST($2::text[], $3::text[], $4::bigint[], $5::text[]) AS g(file_hash, filename, file_size, origin_peer_id)
+        WHERE p.peer_id = $1
+          AND NOT EXISTS (SELECT 1 FROM gossip_files x WHERE x.peer_id = p.id AND x.file_hash = g.file_hash)
+        LIMIT GREATEST($6 - (SELECT COUNT(*) FROM gossip_files x JOIN peers q ON q.id = x.peer_id WHERE q.peer_id = $1), 0)
+        ON CONFLICT (peer_id, file_hash) DO NOTHING`,
+		peerLibp2pID, hashes, names, sizes, origins, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(res.RowsAffected()), nil
+}
+
+// ListGossipFiles peer ko gossip se pata chali files, sabse purani pehle
+func (r *Repository) ListGossipFiles(ctx context.Context, peerLibp2pID string) ([]GossipFile, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT g.file_hash, g.filename, g.file_size, g.origin_peer_id, g.learned_at
+        FROM gossip_files g JOIN peers p ON p.id = g.peer_id
+        WHERE p.peer_id = $1
+        ORDER BY g.learned_at, g.file_hash`, peerLibp2pID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []GossipFile
+	for rows.Next() {
+		var f GossipFile
+		if err := rows.Scan(&f.FileHash, &f.Filename, &f.FileSize, &f.OriginPeerID, &f.LearnedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
 // Kisi file ke liye saare online peers dikhata hai (abhi ke liye basic trust score dikhata hai)
 func (r *Repository) FindOnlineFilePeersByID(ctx context.Context, fileID uuid.UUID) ([]PeerFile, error) {
 	query := `
@@ -265,3 +1042,40 @@ func (r *Repository) FindOnlineFilePeersByID(ctx context.Context, fileID uuid.UU
 	}
 	return peerFiles, rows.Err()
 }
+
+// peer ka ek audit event events table mein likhta hai; metadata JSON mein store hota hai (nil ho toh NULL)
+func (r *Repository) RecordPeerEvent(ctx context.Context, peerID, eventType string, metadata interface{}) error {
+	var data []byte
+	if metadata != nil {
+		var err error
+		if data, err = json.Marshal(metadata); err != nil {
+			return fmt.Errorf("failed to encode event metadata: %w", err)
+		}
+	}
+	_, err := r.DB.Exec(ctx, `INSERT INTO events (peer_id, event_type, metadata) VALUES ($1, $2, $3)`, peerID, eventType, data)
+	return err
+}
+
+// peer ke sabse naye limit audit events deta hai, naye pehle
+func (r *Repository) GetPeerHistory(ctx context.Context, peerID string, limit int) ([]PeerHistoryRecord, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT id, peer_id, event_type, metadata, occurred_at
+        FROM events
+        WHERE peer_id = $1
+        ORDER BY occurred_at DESC, id DESC
+        LIMIT $2`, peerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []PeerHistoryRecord
+	for rows.Next() {
+		var h PeerHistoryRecord
+		if err := rows.Scan(&h.ID, &h.PeerID, &h.EventType, &h.Metadata, &h.OccurredAt); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"torrentium/db/dbtest"
+)
+
+var peerColumns = []string{"id", "peer_id", "name", "multiaddrs", "ip_address", "ipv6_address", "tcp_port", "is_online", "last_seen", "created_at"}
+
+// pgxmock pool ke upar Repository banata hai; test ke end mein saari expectations poori honi chahiye
+func newMockRepository(t *testing.T) (*Repository, pgxmock.PgxPoolIface) {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		mock.Close()
+	})
+	return &Repository{DB: mock}, mock
+}
+
+func TestGetPeerLookups(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := Peer{
+		ID:         uuid.MustParse("6f1c2a5e-8d1b-4a57-9b5e-1f2d3c4b5a69"),
+		PeerID:     "12D3KooWGzPpd9bq7C7xQ3Bv4FfXr9JqQdZyV2xHkQw4V7gW1k2N",
+		Name:       "alice",
+		Multiaddrs: []string{"/ip4/192.0.2.10/tcp/4001", "/ip6/2001:db8::10/tcp/4001"},
+		IPAddress:  "192.0.2.10",
+		IPv6:       "2001:db8::10",
+		TCPPort:    4002,
+		IsOnline:   true,
+		LastSeen:   seen,
+		CreatedAt:  seen.Add(-time.Hour),
+	}
+	peerRow := func() *pgxmock.Rows {
+		return pgxmock.NewRows(peerColumns).AddRow(want.ID, want.PeerID, want.Name, want.Multiaddrs, want.IPAddress,
+			want.IPv6, want.TCPPort, want.IsOnline, want.LastSeen, want.CreatedAt)
+	}
+	errDown := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		lookup  func(r *Repository) (*Peer, error)
+		query   string
+		arg     string
+		rows    *pgxmock.Rows
+		dbErr   error
+		wantErr error
+	}{
+		{
+			name:   "by ID found",
+			lookup: func(r *Repository) (*Peer, error) { return r.GetPeerByID(context.Background(), want.PeerID) },
+			query:  `FROM peers WHERE peer_id = \$1`,
+			arg:    want.PeerID,
+			rows:   peerRow(),
+		},
+		{
+			name:    "by ID not found",
+			lookup:  func(r *Repository) (*Peer, error) { return r.GetPeerByID(context.Background(), "12D3KooWUnknown") },
+			query:   `FROM peers WHERE peer_id = \$1`,
+			arg:     "12D3KooWUnknown",
+			rows:    pgxmock.NewRows(peerColumns),
+			wantErr: pgx.ErrNoRows,
+		},
+		{
+			name:    "by ID database error",
+			lookup:  func(r *Repository) (*Peer, error) { return r.GetPeerByID(context.Background(), want.PeerID) },
+			query:   `FROM peers WHERE peer_id = \$1`,
+			arg:     want.PeerID,
+			dbErr:   errDown,
+			wantErr: errDown,
+		},
+		{
+			name:   "by IPv4 found",
+			lookup: func(r *Repository) (*Peer, error) { return r.GetPeerByIP(context.Background(), "192.0.2.10") },
+			query:  `FROM peers WHERE ip_address = \$1 OR ipv6_address = \$1 ORDER BY last_seen DESC`,
+			arg:    "192.0.2.10",
+			rows:   peerRow(),
+		},
+		{
+			name:   "by IPv6 found",
+			lookup: func(r *Repository) (*Peer, error) { return r.GetPeerByIP(context.Background(), "2001:db8::10") },
+			query:  `FROM peers WHERE ip_address = \$1 OR ipv6_address = \$1 ORDER BY last_seen DESC`,
+			arg:    "2001:db8::10",
+			rows:   peerRow(),
+		},
+		{
+			name:    "by IP not found",
+			lookup:  func(r *Repository) (*Peer, error) { return r.GetPeerByIP(context.Background(), "198.51.100.7") },
+			query:   `FROM peers WHERE ip_address = \$1 OR ipv6_address = \$1`,
+			arg:     "198.51.100.7",
+			rows:    pgxmock.NewRows(peerColumns),
+			wantErr: pgx.ErrNoRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newMockRepository(t)
+			expect := mock.ExpectQuery(tt.query).WithArgs(tt.arg)
+			if tt.dbErr != nil {
+				expect.WillReturnError(tt.dbErr)
+			} else {
+				expect.WillReturnRows(tt.rows)
+			}
+
+			got, err := tt.lookup(repo)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if got != nil {
+					t.Fatalf("got peer %+v along with an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Fatalf("got %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestAddGossipFiles(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	ctx := context.Background()
+	peerID := "12D3KooWGzPpd9bq7C7xQ3Bv4FfXr9JqQdZyV2xHkQw4V7gW1k2N"
+	files := []GossipFile{
+		{FileHash: "aa11", Filename: "a.iso", FileSize: 10, OriginPeerID: "12D3KooWOriginA"},
+		{FileHash: "bb22", Filename: "b.mkv", FileSize: 20, OriginPeerID: "12D3KooWOriginB"},
+	}
+
+	// khaali list par database tak nahi jaate
+	if added, err := repo.AddGossipFiles(ctx, peerID, nil, 1000); err != nil || added != 0 {
+		t.Fatalf("AddGossipFiles(nil) = %d, %v", added, err)
+	}
+
+	// limit query mein jaati hai, taaki peer ki kul rows usse upar na jaaye
+	mock.ExpectExec(`INSERT INTO gossip_files .* NOT EXISTS .* LIMIT GREATEST\(\$6 - \(SELECT COUNT\(\*\)`).
+		WithArgs(peerID, []string{"aa11", "bb22"}, []string{"a.iso", "b.mkv"}, []int64{10, 20}, []string{"12D3KooWOriginA", "12D3KooWOriginB"}, 1000).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	added, err := repo.AddGossipFiles(ctx, peerID, files, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 {
+		t.Fatalf("added = %d, want the rows the insert reported (1)", added)
+	}
+}
+
+// dbtest ke Postgres par migrations chala kar Repository deta hai
+func newTestRepository(b *testing.B) *Repository {
+	b.Helper()
+	pool, err := InitDB(context.Background(), dbtest.PostgresURL(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(pool.Close)
+	return NewRepository(pool)
+}
+
+// n valid libp2p peer IDs wale records (ValidatePeerAddrs asli IDs maangta hai)
+func benchmarkPeers(b *testing.B, n int) []PeerRecord {
+	b.Helper()
+	peers := make([]PeerRecord, n)
+	for i := range peers {
+		_, pub, err := crypto.GenerateEd25519Key(rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		id, err := peer.IDFromPublicKey(pub)
+		if err != nil {
+			b.Fatal(err)
+		}
+		peers[i] = PeerRecord{
+			PeerID:     id.String(),
+			Name:       fmt.Sprintf("peer-%d", i),
+			Multiaddrs: []string{fmt.Sprintf("/ip4/10.0.%d.%d/tcp/4001/p2p/%s", i/256, i%256, id)},
+		}
+	}
+	return peers
+}
+
+// BenchmarkUpsertPeers 1000 peers ek-ek UpsertPeer se aur ek BatchUpsertPeers se insert karta hai.
+// Har iteration se pehle peers table khaali hoti hai, taaki dono naye inserts naap sake. Dono sub-benchmarks chale
+// toh batch ka speedup "x-faster" metric mein aata hai aur 10x se kam ho toh benchmark fail hota hai.
+func BenchmarkUpsertPeers(b *testing.B) {
+	const n = 1000
+	repo := newTestRepository(b)
+	peers := benchmarkPeers(b, n)
+	ctx := context.Background()
+	// b sub-benchmark ka hona chahiye, taaki timer aur Fatal usi par lage
+	reset := func(b *testing.B) {
+		b.StopTimer()
+		if _, err := repo.DB.Exec(ctx, `DELETE FROM peers`); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+
+	// har sub-benchmark ke aakhri run ka ns/op
+	var singleNs, batchNs float64
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reset(b)
+			for _, p := range peers {
+				if _, err := repo.UpsertPeer(ctx, p.PeerID, p.Name, p.Multiaddrs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		singleNs = float64(b.Elapsed()) / float64(b.N)
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reset(b)
+			if err := repo.BatchUpsertPeers(ctx, peers); err != nil {
+				b.Fatal(err)
+			}
+		}
+		batchNs = float64(b.Elapsed()) / float64(b.N)
+		if singleNs > 0 {
+			b.ReportMetric(singleNs/batchNs, "x-faster")
+		}
+	})
+
+	// -bench se sirf ek sub-benchmark chuna ho toh compare karne ko kuch nahi
+	if singleNs == 0 || batchNs == 0 {
+		return
+	}
+	ratio := singleNs / batchNs
+	b.Logf("%d peers: single %.0f ns/op, batch %.0f ns/op, batch is %.1fx faster", n, singleNs, batchNs, ratio)
+	if ratio < 10 {
+		b.Errorf("BatchUpsertPeers is only %.1fx faster than %d UpsertPeer calls, want at least 10x", ratio, n)
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// ValidationError tab aata hai jab peer record ka koi field database mein store karne layak nahi hai.
+// Field "peer_id", "multiaddr" ya "ip" hota hai, Value woh value jo reject hui.
+type ValidationError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Value, e.Err)
+	}
+	return fmt.Sprintf("invalid %s %q", e.Field, e.Value)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePeerRecord peer ka data store karne se pehle check karta hai: id peer.Decode se decode hona chahiye,
+// multiaddr go-multiaddr se parse aur ip net.ParseIP se. multiaddr aur ip optional hai (WebSocket peers ke paas
+// multiaddrs nahi hote, aur local IP pata na ho toh empty aata hai), empty hone par check nahi hote.
+// Pehla galat field *ValidationError ki tarah return hota hai.
+func ValidatePeerRecord(id, multiaddr, ip string) error {
+	if id == "" {
+		return &ValidationError{Field: "peer_id", Value: id, Err: errors.New("empty peer ID")}
+	}
+	if _, err := peer.Decode(id); err != nil {
+		return &ValidationError{Field: "peer_id", Value: id, Err: err}
+	}
+	if multiaddr != "" {
+		if _, err := ma.NewMultiaddr(multiaddr); err != nil {
+			return &ValidationError{Field: "multiaddr", Value: multiaddr, Err: err}
+		}
+	}
+	if ip != "" && net.ParseIP(ip) == nil {
+		return &ValidationError{Field: "ip", Value: ip}
+	}
+	return nil
+}
+
+// ValidatePeerAddrs UpsertPeer ke liye peer ID aur uske saare multiaddrs ko ValidatePeerRecord se check karta hai
+func ValidatePeerAddrs(id string, multiaddrs []string) error {
+	if len(multiaddrs) == 0 {
+		return ValidatePeerRecord(id, "", "")
+	}
+	for _, addr := range multiaddrs {
+		if err := ValidatePeerRecord(id, addr, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
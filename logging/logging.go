@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// current woh handler hai jispe saare package loggers ke records finally jaate hai.
+// Configure se isko runtime par (flags parse hone ke baad) badla ja sakta hai.
+var current atomic.Pointer[slog.Handler]
+
+func init() {
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+	current.Store(&h)
+}
+
+// New diye gaye level (debug, info, warn, error) aur format (text, json) ke saath naya logger banata hai
+func New(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "", "info":
+		lvl = slog.LevelInfo
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		return nil, fmt.Errorf("unknown log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// Configure saare package loggers ko diye gaye logger ke handler par switch kar deta hai
+func Configure(l *slog.Logger) {
+	h := l.Handler()
+	current.Store(&h)
+	slog.SetDefault(l)
+}
+
+// For ek component (package) ke liye logger return karta hai jo hamesha configured handler use karta hai
+func For(component string) *slog.Logger {
+	return slog.New(proxyHandler{}).With("component", component)
+}
+
+// proxyHandler har record ko current handler tak forward karta hai.
+// WithAttrs/WithGroup calls yaad rakhe jaate hai aur Handle ke time current handler par apply hote hai.
+type proxyHandler struct {
+	ops []func(slog.Handler) slog.Handler
+}
+
+func (p proxyHandler) resolve() slog.Handler {
+	h := *current.Load()
+	for _, op := range p.ops {
+		h = op(h)
+	}
+	return h
+}
+
+func (p proxyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return (*current.Load()).Enabled(ctx, level)
+}
+
+func (p proxyHandler) Handle(ctx context.Context, r slog.Record) error {
+	return p.resolve().Handle(ctx, r)
+}
+
+func (p proxyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return p.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (p proxyHandler) WithGroup(name string) slog.Handler {
+	return p.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (p proxyHandler) with(op func(slog.Handler) slog.Handler) proxyHandler {
+	ops := make([]func(slog.Handler) slog.Handler, len(p.ops), len(p.ops)+1)
+	copy(ops, p.ops)
+	return proxyHandler{ops: append(ops, op)}
+}
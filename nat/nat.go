@@ -0,0 +1,207 @@
+// Package nat home router par UPnP (IGD) se port mappings banata hai, taaki bahar ke peers seedha connect kar sake.
+package nat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/huin/goupnp"
+	"github.com/huin/goupnp/dcps/internetgateway2"
+
+	"torrentium/logging"
+)
+
+// nat package ka logger
+var logger = logging.For("nat")
+
+// mappingLease ke baad router mapping khud hata deta hai (crash ke baad port khule na rahe), isliye
+// renewInterval par mappings dobara add hoti hai
+const (
+	mappingLease  = 2 * time.Hour
+	renewInterval = time.Hour
+)
+
+// mappingDescription router ke UI mein mapping ke saath dikhta hai
+const mappingDescription = "Torrentium"
+
+// ErrNoGateway tab aata hai jab network par koi UPnP internet gateway nahi mila
+var ErrNoGateway = errors.New("no UPnP gateway found")
+
+// igdClient WANIPConnection1/2 aur WANPPPConnection1 ke woh methods jo hume chahiye
+type igdClient interface {
+	AddPortMappingCtx(ctx context.Context, remoteHost string, externalPort uint16, protocol string, internalPort uint16,
+		internalClient string, enabled bool, description string, leaseDuration uint32) error
+	DeletePortMappingCtx(ctx context.Context, remoteHost string, externalPort uint16, protocol string) error
+	GetExternalIPAddressCtx(ctx context.Context) (string, error)
+	GetServiceClient() *goupnp.ServiceClient
+}
+
+// Mapping gateway par bani ek port mapping hai. Protocol "TCP" ya "UDP" hai.
+type Mapping struct {
+	Protocol     string
+	InternalPort int
+	ExternalPort int
+	ExternalIP   string
+}
+
+// Gateway ek discovered UPnP internet gateway hai. Map se bani mappings Close tak renew hoti rehti hai.
+type Gateway struct {
+	client     igdClient
+	internalIP string
+
+	mu       sync.Mutex
+	mappings []Mapping
+	stop     chan struct{}
+	closed   bool
+}
+
+// Discover LAN par UPnP internet gateway dhoondhta hai (pehle IGDv2 WANIPConnection2, phir WANIPConnection1
+// aur WANPPPConnection1). Koi gateway na mile toh ErrNoGateway.
+func Discover(ctx context.Context) (*Gateway, error) {
+	var clients []igdClient
+	ip2, _, err := internetgateway2.NewWANIPConnection2ClientsCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("UPnP discovery failed: %w", err)
+	}
+	for _, c := range ip2 {
+		clients = append(clients, c)
+	}
+	if len(clients) == 0 {
+		ip1, _, err := internetgateway2.NewWANIPConnection1ClientsCtx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("UPnP discovery failed: %w", err)
+		}
+		for _, c := range ip1 {
+			clients = append(clients, c)
+		}
+	}
+	if len(clients) == 0 {
+		ppp, _, err := internetgateway2.NewWANPPPConnection1ClientsCtx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("UPnP discovery failed: %w", err)
+		}
+		for _, c := range ppp {
+			clients = append(clients, c)
+		}
+	}
+	if len(clients) == 0 {
+		return nil, ErrNoGateway
+	}
+
+	client := clients[0]
+	internalIP, err := localIPFor(client.GetServiceClient())
+	if err != nil {
+		return nil, fmt.Errorf("could not determine local address towards gateway: %w", err)
+	}
+	g := &Gateway{client: client, internalIP: internalIP, stop: make(chan struct{})}
+	go g.renewLoop()
+	return g, nil
+}
+
+// gateway tak jaane wala local IP, yahi mapping ka internal client hai
+func localIPFor(sc *goupnp.ServiceClient) (string, error) {
+	host := sc.Location.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "80")
+	}
+	// UDP "dial" packet nahi bhejta, sirf route chun kar local address set karta hai
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
+
+// ExternalIP gateway ka public IP address hai
+func (g *Gateway) ExternalIP(ctx context.Context) (string, error) {
+	return g.client.GetExternalIPAddressCtx(ctx)
+}
+
+// Map gateway par protocol ("TCP" ya "UDP") ke liye port ko usi external port par is machine ki taraf map karta hai.
+// Mapping Close tak renew hoti hai. Returned Mapping mein gateway ka public IP hota hai (pata na chale toh empty).
+func (g *Gateway) Map(ctx context.Context, protocol string, port int) (Mapping, error) {
+	if protocol != "TCP" && protocol != "UDP" {
+		return Mapping{}, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	if port <= 0 || port > 65535 {
+		return Mapping{}, fmt.Errorf("invalid port %d", port)
+	}
+	if err := g.addMapping(ctx, protocol, port); err != nil {
+		return Mapping{}, fmt.Errorf("failed to map %s port %d: %w", protocol, port, err)
+	}
+	m := Mapping{Protocol: protocol, InternalPort: port, ExternalPort: port}
+	if ip, err := g.ExternalIP(ctx); err != nil {
+		logger.Debug("Could not get external IP from gateway", "error", err)
+	} else {
+		m.ExternalIP = ip
+	}
+
+	g.mu.Lock()
+	g.mappings = append(g.mappings, m)
+	g.mu.Unlock()
+	return m, nil
+}
+
+func (g *Gateway) addMapping(ctx context.Context, protocol string, port int) error {
+	return g.client.AddPortMappingCtx(ctx, "", uint16(port), protocol, uint16(port), g.internalIP, true,
+		mappingDescription, uint32(mappingLease/time.Second))
+}
+
+// lease khatam hone se pehle saari mappings dobara add karta hai, Close par ruk jaata hai
+func (g *Gateway) renewLoop() {
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			g.mu.Lock()
+			mappings := append([]Mapping(nil), g.mappings...)
+			g.mu.Unlock()
+			for _, m := range mappings {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := g.addMapping(ctx, m.Protocol, m.InternalPort); err != nil {
+					logger.Warn("Failed to renew port mapping", "protocol", m.Protocol, "port", m.ExternalPort, "error", err)
+				}
+				cancel()
+			}
+		}
+	}
+}
+
+// Close renewals rokta hai aur DeletePortMapping se saari mappings hata deta hai.
+// Pehla error return hota hai, baaki mappings phir bhi hatayi jaati hai. nil Gateway par kuch nahi karta.
+func (g *Gateway) Close(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	close(g.stop)
+	mappings := g.mappings
+	g.mappings = nil
+	g.mu.Unlock()
+
+	var firstErr error
+	for _, m := range mappings {
+		if err := g.client.DeletePortMappingCtx(ctx, "", uint16(m.ExternalPort), m.Protocol); err != nil {
+			logger.Warn("Failed to delete port mapping", "protocol", m.Protocol, "port", m.ExternalPort, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Info("Released port mapping", "protocol", m.Protocol, "port", m.ExternalPort)
+	}
+	return firstErr
+}
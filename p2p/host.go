@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 
@@ -70,13 +69,13 @@ func loadOrGeneratePrivateKey() (crypto.PrivKey, error) {
 		if err := os.WriteFile(privKeyFile, privBytes, 0600); err != nil {
 			return nil, fmt.Errorf("failed to write private key to file: %w", err)
 		}
-		log.Println("Generated new libp2p private key.")
+		logger.Info("Generated new libp2p private key")
 		return priv, nil
 	} else if err != nil {
 		return nil, err // Other error, e.g., permissions
 	}
 
-	log.Println("Loaded existing libp2p private key.")
+	logger.Info("Loaded existing libp2p private key")
 	return crypto.UnmarshalPrivateKey(privBytes)
 }
 
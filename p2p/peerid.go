@@ -0,0 +1,31 @@
+package p2p
+
+import (
+	"unicode/utf8"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// ShortPeerIDLength terminal output mein inline peer IDs ki default lambai hai
+const ShortPeerIDLength = 12
+
+// FormatPeerID peer ID ke pehle length characters return karta hai aur truncate hua ho toh "…" laga deta hai.
+// length ID jitni ya usse zyada ho (ya <= 0 ho) toh poori ID bina badle return hoti hai.
+func FormatPeerID(id peer.ID, length int) string {
+	return formatPeerIDString(id.String(), length)
+}
+
+// FormatPeerIDString FormatPeerID jaisa hai, lekin tracker/database se aayi string peer IDs ke liye
+func FormatPeerIDString(id string, length int) string {
+	if decoded, err := peer.Decode(id); err == nil {
+		return FormatPeerID(decoded, length)
+	}
+	return formatPeerIDString(id, length)
+}
+
+func formatPeerIDString(s string, length int) string {
+	if length <= 0 || utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length]) + "…"
+}
@@ -0,0 +1,41 @@
+package p2p
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func TestFormatPeerID(t *testing.T) {
+	_, pub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := id.String()
+
+	tests := []struct {
+		name   string
+		length int
+		want   string
+	}{
+		{name: "exact length", length: len(full), want: full},
+		{name: "longer than ID", length: len(full) + 10, want: full},
+		{name: "zero", length: 0, want: full},
+		{name: "negative", length: -1, want: full},
+		{name: "short", length: ShortPeerIDLength, want: full[:ShortPeerIDLength] + "…"},
+	}
+	for _, tt := range tests {
+		if got := FormatPeerID(id, tt.length); got != tt.want {
+			t.Errorf("%s: FormatPeerID(id, %d) = %q, want %q", tt.name, tt.length, got, tt.want)
+		}
+		if got := FormatPeerIDString(full, tt.length); got != tt.want {
+			t.Errorf("%s: FormatPeerIDString(id, %d) = %q, want %q", tt.name, tt.length, got, tt.want)
+		}
+	}
+}
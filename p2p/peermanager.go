@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxConnections ek saath kitne WebRTC peers allow hai, agar caller kuch aur set na kare
+const DefaultMaxConnections = 50
+
+// TooManyConnectionsMsg signaling stream par tab bheja jaata hai jab peer ke paas aur connections ki jagah nahi hai
+const TooManyConnectionsMsg = "ERROR:TOO_MANY_CONNECTIONS"
+
+// BannedMsg signaling stream par tab bheja jaata hai jab remote peer hamari ban list mein hai
+const BannedMsg = "ERROR:BANNED"
+
+// ErrBanned tab aata hai jab remote peer ne humein ban kar rakha hai aur signaling stream band kar diya
+var ErrBanned = errors.New("banned by peer")
+
+// ErrTooManyConnections tab aata hai jab remote peer ne connection limit ki wajah se signaling stream reject kar diya
+var ErrTooManyConnections = errors.New("peer has too many connections")
+
+// PeerManager active peer connections ginta hai taaki koi peer (ya bahut saare peers) goroutines aur
+// file descriptors khatam na kar sake. Count atomic counter hai, isliye signaling handler bina lock ke check kar sakta hai.
+// Saath hi banned peers ki list rakhta hai, jinke signaling streams turant band ho jaate hai.
+type PeerManager struct {
+	MaxConnections int // isse zyada connections par naye signaling streams reject hote hai (<= 0 ho toh koi limit nahi)
+	count          atomic.Int64
+
+	bansMu sync.RWMutex
+	bans   map[string]time.Time // peer ID -> ban expiry, zero time ho toh permanent
+}
+
+// NewPeerManager DefaultMaxConnections limit ke saath PeerManager banata hai
+func NewPeerManager() *PeerManager {
+	return &PeerManager{MaxConnections: DefaultMaxConnections, bans: make(map[string]time.Time)}
+}
+
+// Ban peerID ko until tak ban karta hai; until zero ho toh ban permanent hai
+func (m *PeerManager) Ban(peerID string, until time.Time) {
+	m.bansMu.Lock()
+	defer m.bansMu.Unlock()
+	m.bans[peerID] = until
+}
+
+// Unban peerID ka ban hata deta hai
+func (m *PeerManager) Unban(peerID string) {
+	m.bansMu.Lock()
+	defer m.bansMu.Unlock()
+	delete(m.bans, peerID)
+}
+
+// IsBanned batata hai ki peerID par abhi valid ban hai ya nahi; expire ho chuke bans false dete hai
+func (m *PeerManager) IsBanned(peerID string) bool {
+	m.bansMu.RLock()
+	defer m.bansMu.RUnlock()
+	until, ok := m.bans[peerID]
+	return ok && (until.IsZero() || time.Now().Before(until))
+}
+
+// Add ek naya connection count karta hai
+func (m *PeerManager) Add() {
+	m.count.Add(1)
+}
+
+// Remove ek band hue connection ko count se hatata hai
+func (m *PeerManager) Remove() {
+	m.count.Add(-1)
+}
+
+// Count abhi ke active connections ki sankhya hai
+func (m *PeerManager) Count() int {
+	return int(m.count.Load())
+}
+
+// Full batata hai ki connection limit poori ho chuki hai
+func (m *PeerManager) Full() bool {
+	return m.MaxConnections > 0 && m.Count() >= m.MaxConnections
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"time"
 
+	"torrentium/db"
+	"torrentium/logging"
 	"torrentium/tracker"
 
 	"github.com/google/uuid"
@@ -14,14 +16,18 @@ import (
 	"github.com/libp2p/go-libp2p/core/network"
 )
 
+// p2p package ka logger
+var logger = logging.For("p2p")
+
 // TrackerProtocolID ek unique string hai jo tracker ko network par pehchanta hai.
 // Isse peers ko pata chalta hai ki woh sahi tracker se baat kar rahe hain.
 const TrackerProtocolID = "/torrentium/tracker/1.0"
 
 // yeh struct tracker aur peer ke beech ke messaging ko define kar rha hai
 type Message struct {
-	Command string          `json:"command"`           // name of command jaise : ADD_PEER
-	Payload json.RawMessage `json:"payload,omitempty"` // according to command, payload mein data hai
+	Command   string          `json:"command"`              // name of command jaise : ADD_PEER
+	Payload   json.RawMessage `json:"payload,omitempty"`    // according to command, payload mein data hai
+	RequestID uint64          `json:"request_id,omitempty"` // tracker response mein wahi ID lautata hai, taaki client sahi request ko jawab de
 }
 
 // yeh struct peer ke tracker ya kisi au peer ke saath handshake ko define karta hai
@@ -29,14 +35,28 @@ type HandshakePayload struct {
 	Name        string   `json:"name"`
 	ListenAddrs []string `json:"listen_addrs"`
 	PeerID      string   `json:"peer_id"`
+	IPv4        string   `json:"ipv4,omitempty"`
+	IPv6        string   `json:"ipv6,omitempty"`
+	TCPPort     int      `json:"tcp_port,omitempty"`  // direct TCP download fallback ka port, 0 ho toh band
+	Signature   []byte   `json:"signature,omitempty"` // tracker.SignHandshake se tracker ke NONCE par bana signature
+}
+
+// NoncePayload WebSocket tracker connect hote hi NONCE message mein bhejta hai; peer HANDSHAKE mein ise sign karta hai
+type NoncePayload struct {
+	Nonce []byte `json:"nonce"`
 }
 
 // AnnounceFilePayload struct tab use hota hai jab peer announce karta hai tracker ko ki uske paas ek nayi file hai.
 type AnnounceFilePayload struct {
 	FileHash string `json:"file_hash"`
+	InfoHash string `json:"info_hash,omitempty"` // BitTorrent info-hash (hex)
 	Filename string `json:"filename"`
 	FileSize int64  `json:"file_size"`
 	PeerID   string `json:"peer_id"`
+
+	PieceLength int64    `json:"piece_length,omitempty"` // .torrent ka piece size
+	Pieces      [][]byte `json:"pieces,omitempty"`       // har piece ka SHA-1 hash, tracker ke file_pieces index ke liye
+	Signature   []byte   `json:"signature,omitempty"`    // tracker.SignAnnouncement se bana SHA256(file_hash + peer_id) ka signature
 }
 
 // AnnounceAckPayload struct tracker se peer ko file announce karne par acknowledgement bhejne ke liye use hota hai.
@@ -54,6 +74,185 @@ type GetPeerInfoPayload struct {
 	PeerDBID uuid.UUID `json:"peer_db_id"`
 }
 
+// GetPeerByIDPayload struct libp2p peer ID se peer ki DB info maangne ke liye use hota hai
+type GetPeerByIDPayload struct {
+	PeerID string `json:"peer_id"`
+}
+
+// GetFileByNamePayload struct filename se file ki DB info maangne ke liye use hota hai
+type GetFileByNamePayload struct {
+	Filename string `json:"filename"`
+}
+
+// RemoveFilePayload struct tab use hota hai jab peer apna file announcement wapas leta hai
+type RemoveFilePayload struct {
+	FileHash string `json:"file_hash"`
+	PeerID   string `json:"peer_id"`
+}
+
+// TagFilePayload file par tag lagane (ya Remove true ho toh hatane) ke liye use hota hai
+type TagFilePayload struct {
+	FileHash string `json:"file_hash"`
+	Tag      string `json:"tag"`
+	Remove   bool   `json:"remove,omitempty"`
+}
+
+// SearchTagPayload ek tag wali files maangne ke liye use hota hai, response TAGGED_FILES mein []db.File aata hai
+type SearchTagPayload struct {
+	Tag string `json:"tag"`
+}
+
+// CheckPeerFilePayload uploader sending se pehle poochta hai ki requester ke paas file pehle se hai ya nahi
+type CheckPeerFilePayload struct {
+	FileID uuid.UUID `json:"file_id"`
+	PeerID string    `json:"peer_id"` // requester ka libp2p peer ID
+}
+
+// PeerFileStatusPayload CHECK_PEER_FILE ka response hai
+type PeerFileStatusPayload struct {
+	FileHash string `json:"file_hash"`
+	Filename string `json:"filename"`
+	HasFile  bool   `json:"has_file"`
+}
+
+// PieceCachePayload peer ki disk par verify ho chuke pieces ke liye hai. GET_VERIFIED_PIECES ka response
+// VERIFIED_PIECES mein []int aata hai; MARK_PIECES_VERIFIED Pieces bhejta hai aur jawab PIECES_MARKED sirf log hota hai.
+// ModTime disk par file ka modification time hai, badle toh purana cache nahi maana jaata.
+type PieceCachePayload struct {
+	PeerID   string    `json:"peer_id"`
+	FileHash string    `json:"file_hash"`
+	ModTime  time.Time `json:"mod_time"`
+	Pieces   []int     `json:"pieces,omitempty"`
+}
+
+// GossipFilesPayload peer ko GOSSIP_FILES se mili files ke liye hai. ADD_GOSSIP_FILES Files bhejta hai aur jawab
+// GOSSIP_FILES_ADDED mein Added (nayi saved files) aata hai; GET_GOSSIP_FILES ka jawab GOSSIP_FILE_LIST mein Files aati hai.
+type GossipFilesPayload struct {
+	Files []FileRecord `json:"files,omitempty"`
+	Added int          `json:"added,omitempty"`
+}
+
+// ReportUploadPayload peer tracker ko batata hai ki usne direct (WebRTC/QUIC) transfer mein kitne bytes upload kiye
+type ReportUploadPayload struct {
+	PeerID string `json:"peer_id"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// ReportTransferPayload ek khatam hue direct (WebRTC/QUIC) transfer ko tracker ke audit log mein daalta hai.
+// Response TRANSFER_RECORDED hai. Outcome "complete", "error" ya "corrupt" (TransferEvent ke types) hai.
+type ReportTransferPayload struct {
+	PeerID       string `json:"peer_id"`
+	RemotePeerID string `json:"remote_peer_id,omitempty"`
+	Filename     string `json:"filename"`
+	Bytes        int64  `json:"bytes"`
+	Outcome      string `json:"outcome"`
+}
+
+// PeerHistoryPayload peer ke audit events maangta hai, response PEER_HISTORY mein []db.PeerHistoryRecord aata hai
+type PeerHistoryPayload struct {
+	PeerID string `json:"peer_id"`
+	Limit  int    `json:"limit"`
+}
+
+// TopSeedersPayload sabse active seeders maangne ke liye use hota hai, response TOP_SEEDERS_LIST mein []db.SeederRecord aata hai
+type TopSeedersPayload struct {
+	Limit int `json:"limit"`
+}
+
+// RemoveFileAckPayload batata hai ki remove ke baad kitne dusre peers file announce kar rahe hai
+type RemoveFileAckPayload struct {
+	RemainingPeers int `json:"remaining_peers"`
+}
+
+// AtRiskFilesPayload AT_RISK_FILES request hai: woh files jinke saare seeders ThresholdSeconds se zyada der se offline hai
+type AtRiskFilesPayload struct {
+	ThresholdSeconds int64 `json:"threshold_seconds"`
+}
+
+// BanPeerPayload PeerID (ban lagane wala) ki ban list mein BannedPeerID ko daalta hai.
+// DurationSeconds <= 0 ho toh ban permanent hai. UNBAN_PEER bhi yahi payload use karta hai (Reason aur duration ignore).
+type BanPeerPayload struct {
+	PeerID          string `json:"peer_id"`
+	BannedPeerID    string `json:"banned_peer_id"`
+	Reason          string `json:"reason,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+}
+
+// AccessControlPayload ALLOW_PEER aur DENY_PEER ka payload hai: PeerID (file ka seeder) FileHash ki allow list mein
+// TargetPeerID ko daalta ya hatata hai. Sirf woh peer list badal sakta hai jiska announcement signature valid hai.
+type AccessControlPayload struct {
+	PeerID       string `json:"peer_id"`
+	FileHash     string `json:"file_hash"`
+	TargetPeerID string `json:"target_peer_id"`
+}
+
+// CheckAccessPayload CHECK_ACCESS request hai: kya PeerID file FileID download kar sakta hai
+type CheckAccessPayload struct {
+	FileID uuid.UUID `json:"file_id"`
+	PeerID string    `json:"peer_id"`
+}
+
+// AccessCheckedPayload CHECK_ACCESS ka jawab (ACCESS_CHECKED) hai
+type AccessCheckedPayload struct {
+	Allowed bool `json:"allowed"`
+}
+
+// PeerOfflineAckPayload REPORT_PEER_OFFLINE ka response hai, Marked false ho toh peer abhi bhi tracker se connected hai
+type PeerOfflineAckPayload struct {
+	PeerID string `json:"peer_id"`
+	Marked bool   `json:"marked"`
+}
+
+// FileRequestInitiatedPayload REQUEST_FILE ka response hai. PieceLength 0 ho toh file ke pieces index nahi hai.
+// SeederIP aur SeederTCPPort chune gaye seeder ka TCP fallback address hai, port 0 ho toh seeder TCP serve nahi karta.
+type FileRequestInitiatedPayload struct {
+	FileID        uuid.UUID `json:"file_id"`
+	FileHash      string    `json:"file_hash"`
+	PieceLength   int64     `json:"piece_length,omitempty"`
+	SeederIP      string    `json:"seeder_ip,omitempty"`
+	SeederTCPPort int       `json:"seeder_tcp_port,omitempty"`
+}
+
+// GetPieceHashPayload file ke ek piece ka hash maangne ke liye use hota hai
+type GetPieceHashPayload struct {
+	FileHash   string `json:"file_hash"`
+	PieceIndex int    `json:"piece_index"`
+}
+
+// PieceHashPayload GET_PIECE_HASH ka response hai
+type PieceHashPayload struct {
+	FileHash    string `json:"file_hash"`
+	PieceIndex  int    `json:"piece_index"`
+	PieceHash   []byte `json:"piece_hash"`
+	PieceLength int64  `json:"piece_length,omitempty"` // file ka piece size (pehle piece ki length), offsets nikalne ke liye
+}
+
+// GetMissingPiecesPayload mein downloader batata hai ki uske paas kaunse verified pieces hai
+type GetMissingPiecesPayload struct {
+	FileHash string `json:"file_hash"`
+	Have     []int  `json:"have"`
+}
+
+// MissingPiecesPayload GET_MISSING_PIECES ka response hai
+type MissingPiecesPayload struct {
+	FileHash string `json:"file_hash"`
+	Missing  []int  `json:"missing"`
+}
+
+// ListFilesPagePayload struct files ka ek page maangne ke liye use hota hai (page 1 se shuru hota hai)
+type ListFilesPagePayload struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+}
+
+// FilePagePayload LIST_FILES_PAGE ka response hai
+type FilePagePayload struct {
+	Files   []db.File `json:"files"`
+	Total   int       `json:"total"`
+	Page    int       `json:"page"`
+	PerPage int       `json:"per_page"`
+}
+
 // RequestFilePayload struct file request ke liye use hota hai
 type RequestFilePayload struct {
 	FileID          uuid.UUID `json:"file_id"`
@@ -76,18 +275,49 @@ type ConnectToPeerPayload struct {
 	Port   int    `json:"port"` // WebSocket server port
 }
 
+// FileRecord struct ek file ki basic info hai jo peers gossip ke through ek dusre ko share karte hain.
+// OriginPeerID us peer ki ID hai jisne file originally announce ki thi.
+type FileRecord struct {
+	Hash         string `json:"hash"`
+	Name         string `json:"name"`
+	Size         int64  `json:"size"`
+	OriginPeerID string `json:"origin_peer_id"`
+	FileID       string `json:"file_id,omitempty"` // tracker ka file ID, isse `get` se file maangi jaati hai
+}
+
+// ChannelMessage struct WebRTC data channel par aane wale text (JSON) messages ko define karta hai.
+type ChannelMessage struct {
+	Command string       `json:"command,omitempty"` // jaise REQUEST_FILE, GOSSIP_FILES
+	Status  string       `json:"status,omitempty"`  // jaise TRANSFER_COMPLETE
+	FileID  string       `json:"file_id,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Files   []FileRecord `json:"files,omitempty"` // GOSSIP_FILES aur FILE_CATALOG ke saath aane wali file list
+
+	// FILE_START ke fields: file ka naam, original size aur compression ("gzip" ya empty)
+	Name     string `json:"name,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Compress string `json:"compress,omitempty"`
+	// directory archive ke liye "application/x-tar+gzip"
+	ContentType string `json:"content_type,omitempty"`
+
+	// BITFIELD ke fields: file hash, pieces ki ginti aur bitfield (JSON mein base64)
+	FileHash string `json:"file_hash,omitempty"`
+	Pieces   int    `json:"pieces,omitempty"`
+	Bitfield []byte `json:"bitfield,omitempty"`
+}
+
 // RegisterTrackerProtocol function host par ek stream handler set karta hai.
 // Jab bhi koi peer TrackerProtocolID ka use karke connect karta hai, toh yeh handler trigger hota hai.
 func RegisterTrackerProtocol(h host.Host, t *tracker.Tracker) {
 	h.SetStreamHandler(TrackerProtocolID, func(s network.Stream) {
-		log.Printf("New peer connected: %s", s.Conn().RemotePeer())
+		logger.Info("New peer connected", "peer", s.Conn().RemotePeer())
 		ctx := context.Background()
 		defer s.Close()
 		defer t.RemovePeerWithContext(ctx, s.Conn().RemotePeer().String())
 
 		if err := handleStream(ctx, s, t); err != nil {
 			if err != io.EOF {
-				log.Printf("Error handling stream for peer %s: %v", s.Conn().RemotePeer(), err)
+				logger.Error("Error handling stream", "peer", s.Conn().RemotePeer(), "error", err)
 			}
 		}
 	})
@@ -116,7 +346,7 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 
 	//yeh peer ko tracker aur database dono mein store karta hai
 	if err := t.AddPeerWithContext(ctx, remotePeerID, handshake.Name, handshake.ListenAddrs); err != nil {
-		log.Printf("CRITIC: failed to AddPeer to database: %v", err)
+		logger.Error("Failed to AddPeer to database", "peer", remotePeerID, "error", err)
 		return encoder.Encode(Message{Command: "ERROR", Payload: json.RawMessage(`"Failed to register with tracker"`)})
 	}
 
@@ -131,21 +361,21 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 			return err
 		}
 
-		log.Printf("Received command '%s' from peer %s", msg.Command, remotePeerID)
+		logger.Debug("Received command", "command", msg.Command, "peer", remotePeerID)
 
 		var response Message
 		switch msg.Command {
 		case "ANNOUNCE_FILE":
 			var p AnnounceFilePayload
 			if err := json.Unmarshal(msg.Payload, &p); err != nil {
-				log.Printf("ERROR in ANNOUNCE_FILE (unmarshal): %v", err)
+				logger.Warn("ANNOUNCE_FILE unmarshal failed", "error", err)
 				response.Command = "ERROR"
 				response.Payload = json.RawMessage(fmt.Sprintf(`"%s"`, err.Error()))
 			} else {
 				//announcedd filee ko database mein peer ke saath link karte hai
-				fileID, err := t.AddFileWithPeer(ctx, p.FileHash, p.Filename, p.FileSize, remotePeerID)
+				fileID, err := t.AddFileWithPeer(ctx, p.FileHash, p.InfoHash, p.Filename, p.FileSize, remotePeerID, p.Signature)
 				if err != nil {
-					log.Printf("ERROR in ANNOUNCE_FILE (db): %v", err)
+					logger.Error("ANNOUNCE_FILE db error", "error", err)
 					response.Command = "ERROR"
 					response.Payload = json.RawMessage(fmt.Sprintf(`"%s"`, err.Error()))
 				} else {
@@ -160,7 +390,7 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 		case "LIST_FILES":
 			files, err := t.GetAllFiles(ctx)
 			if err != nil {
-				log.Printf("ERROR in LIST_FILES (db): %v", err)
+				logger.Error("LIST_FILES db error", "error", err)
 				response.Command = "ERROR"
 			} else {
 				response.Command = "FILE_LIST"
@@ -171,12 +401,12 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 		case "GET_PEERS_FOR_FILE":
 			var p GetPeersPayload
 			if err := json.Unmarshal(msg.Payload, &p); err != nil {
-				log.Printf("ERROR in GET_PEERS_FOR_FILE (unmarshal): %v", err)
+				logger.Warn("GET_PEERS_FOR_FILE unmarshal failed", "error", err)
 				response.Command = "ERROR"
 			} else {
 				peers, err := t.GetOnlinePeersForFile(ctx, p.FileID)
 				if err != nil {
-					log.Printf("ERROR in GET_PEERS_FOR_FILE (db): %v", err)
+					logger.Error("GET_PEERS_FOR_FILE db error", "error", err)
 					response.Command = "ERROR"
 				} else {
 					response.Command = "PEER_LIST"
@@ -188,12 +418,12 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 		case "GET_PEER_INFO":
 			var p GetPeerInfoPayload
 			if err := json.Unmarshal(msg.Payload, &p); err != nil {
-				log.Printf("ERROR in GET_PEER_INFO (unmarshal): %v", err)
+				logger.Warn("GET_PEER_INFO unmarshal failed", "error", err)
 				response.Command = "ERROR"
 			} else {
 				peerInfo, err := t.GetPeerInfoByDBID(ctx, p.PeerDBID)
 				if err != nil {
-					log.Printf("ERROR in GET_PEER_INFO (db): %v", err)
+					logger.Error("GET_PEER_INFO db error", "error", err)
 					response.Command = "ERROR"
 				} else {
 					response.Command = "PEER_INFO"
@@ -205,7 +435,7 @@ func handleStream(ctx context.Context, s network.Stream, t *tracker.Tracker) err
 		case "LIST_PEERS":
 			peers, err := t.GetOnlinePeers(ctx)
 			if err != nil {
-				log.Printf("ERROR in LIST_PEERS (db): %v", err)
+				logger.Error("LIST_PEERS db error", "error", err)
 				response.Command = "ERROR"
 			} else {
 				response.Command = "PEER_LIST_ALL"
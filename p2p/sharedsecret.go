@@ -0,0 +1,90 @@
+package p2p
+
+import (
+	"crypto/ecdh"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// curve25519 ka field prime, 2^255 - 19
+var curve25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// SharedSecret local identity key aur remote peer ki identity key se X25519 ECDH shared secret nikalta hai.
+// Dono peers ko same secret milta hai, bina kuch extra bheje. Sirf Ed25519 identities (libp2p default) supported hai;
+// Ed25519 keys ko unke Montgomery (X25519) form mein badal kar ECDH hota hai.
+func SharedSecret(local crypto.PrivKey, remote peer.ID) ([]byte, error) {
+	if local.Type() != crypto.Ed25519 {
+		return nil, fmt.Errorf("unsupported local key type %s", local.Type())
+	}
+	remotePub, err := remote.ExtractPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("peer ID %s has no embedded public key: %w", remote, err)
+	}
+	if remotePub.Type() != crypto.Ed25519 {
+		return nil, fmt.Errorf("unsupported remote key type %s", remotePub.Type())
+	}
+
+	seed, err := local.Raw()
+	if err != nil {
+		return nil, err
+	}
+	// Ed25519 private key ka scalar seed ke SHA-512 ke pehle 32 bytes hai (clamping X25519 khud karta hai)
+	h := sha512.Sum512(seed[:32])
+	priv, err := ecdh.X25519().NewPrivateKey(h[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	edPub, err := remotePub.Raw()
+	if err != nil {
+		return nil, err
+	}
+	montPub, err := edwardsToMontgomery(edPub)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ecdh.X25519().NewPublicKey(montPub)
+	if err != nil {
+		return nil, err
+	}
+	return priv.ECDH(pub)
+}
+
+// Ed25519 public key (Edwards y coordinate) ko X25519 public key (Montgomery u = (1+y)/(1-y)) mein badalta hai
+func edwardsToMontgomery(edPub []byte) ([]byte, error) {
+	if len(edPub) != 32 {
+		return nil, fmt.Errorf("invalid Ed25519 public key length %d", len(edPub))
+	}
+	// little-endian y, top bit x ka sign hai jo u ke liye zaroori nahi
+	le := make([]byte, 32)
+	copy(le, edPub)
+	le[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(le))
+
+	one := big.NewInt(1)
+	den := new(big.Int).Sub(one, y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, errors.New("invalid Ed25519 public key")
+	}
+	u := new(big.Int).Add(one, y)
+	u.Mul(u, den.ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+
+	out := make([]byte, 32)
+	u.FillBytes(out)
+	return reverse(out), nil
+}
+
+// byte slice ko ulta karta hai (big-endian <-> little-endian), in place
+func reverse(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
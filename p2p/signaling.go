@@ -2,8 +2,10 @@ package p2p
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
@@ -11,29 +13,169 @@ import (
 )
 
 //ek unique id jo WebRTC signaling ke mein use hogi.Peer A ko peer B ke beech transfer mein
+// Yeh ID fix rehti hai; asli version stream ke andar VERSION message se negotiate hota hai.
 const SignalingProtocolID = "/torrentium/webrtc-signaling/1.0"
 
-// RegisterSignalingProtocol webRTC offer ke liye stream handler setup karta hai, jab koi peer protocolID pe join hota hai
-func RegisterSignalingProtocol(h host.Host, onOffer func(offer, remotePeerID string, s network.Stream) (string, error)) {
+// SignalingVersion signaling messages ka <major>.<minor> version hai. Same major wale peers compatible hai.
+const SignalingVersion = "1.0"
+
+// ErrLegacySignaling tab aata hai jab remote peer purana client hai jo VERSION message nahi samajhta.
+// Aise peer ke saath naye stream par seedha offer bhejna hai.
+var ErrLegacySignaling = errors.New("peer does not support signaling version negotiation")
+
+// VersionMismatchError batata hai ki remote peer ka signaling version hamare saath compatible nahi hai
+type VersionMismatchError struct {
+	Local, Remote string
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("signaling version mismatch: we run %s, peer runs %s", e.Local, e.Remote)
+}
+
+// maxSignalingMessage ek signaling message ki maximum length hai; SDP offers isse kaafi chhote hote hai
+const maxSignalingMessage = 64 * 1024
+
+// sdpMessageType parseSignalingLine ka type hai un messages ke liye jo SDP offer/answer JSON hai
+const sdpMessageType = "SDP"
+
+// ErrMalformedSignaling tab aata hai jab signaling stream par aaya message kisi jaane-pehchane format mein nahi hai
+var ErrMalformedSignaling = errors.New("malformed signaling message")
+
+// parseSignalingLine stream se decode hua ek message type aur data mein todta hai. `{` se shuru hone wala
+// message SDP JSON hai (type sdpMessageType, data poora message); baaki messages `TYPE` ya `TYPE:data` hai,
+// jahan TYPE sirf A-Z aur _ hai aur data mein control characters nahi ho sakte.
+func parseSignalingLine(s string) (msgType, data string, err error) {
+	if len(s) == 0 || len(s) > maxSignalingMessage || !utf8.ValidString(s) {
+		return "", "", ErrMalformedSignaling
+	}
+	if s[0] == '{' {
+		return sdpMessageType, s, nil
+	}
+
+	msgType, data, _ = strings.Cut(s, ":")
+	if msgType == "" {
+		return "", "", ErrMalformedSignaling
+	}
+	for _, r := range msgType {
+		if (r < 'A' || r > 'Z') && r != '_' {
+			return "", "", ErrMalformedSignaling
+		}
+	}
+	for _, r := range data {
+		if r < 0x20 || r == 0x7f {
+			return "", "", ErrMalformedSignaling
+		}
+	}
+	return msgType, data, nil
+}
+
+// do versions ka major part same ho toh woh compatible hai
+func versionCompatible(a, b string) bool {
+	majorA, _, _ := strings.Cut(a, ".")
+	majorB, _, _ := strings.Cut(b, ".")
+	return majorA != "" && majorA == majorB
+}
+
+// NegotiateSignalingVersion stream ka pehla message `VERSION:<major>.<minor>` bhejta hai aur remote ke
+// `VERSION_OK` ya `VERSION_MISMATCH:<version>` ka wait karta hai. Offer bhejne se pehle call karna hai.
+func NegotiateSignalingVersion(enc *json.Encoder, dec *json.Decoder) error {
+	if err := enc.Encode("VERSION:" + SignalingVersion); err != nil {
+		return err
+	}
+	var reply string
+	if err := dec.Decode(&reply); err != nil {
+		return err
+	}
+	msgType, data, err := parseSignalingLine(reply)
+	if err != nil {
+		return fmt.Errorf("unexpected version negotiation reply %q: %w", reply, err)
+	}
+	switch {
+	case reply == "VERSION_OK":
+		return nil
+	case reply == TooManyConnectionsMsg:
+		return ErrTooManyConnections
+	case reply == BannedMsg:
+		return ErrBanned
+	case msgType == "VERSION_MISMATCH":
+		return &VersionMismatchError{Local: SignalingVersion, Remote: data}
+	case msgType == "ERROR":
+		// purana client VERSION message ko offer samajh kar parse nahi kar paata
+		return ErrLegacySignaling
+	default:
+		return fmt.Errorf("unexpected version negotiation reply %q", reply)
+	}
+}
+
+// RegisterSignalingProtocol webRTC offer ke liye stream handler setup karta hai, jab koi peer protocolID pe join hota hai.
+// Banned peers ke streams BannedMsg, aur connection limit poori ho toh naye streams TooManyConnectionsMsg
+// bhej kar band kar diye jaate hai.
+func RegisterSignalingProtocol(h host.Host, peers *PeerManager, onOffer func(offer, remotePeerID string, s network.Stream) (string, error)) {
 	h.SetStreamHandler(SignalingProtocolID, func(s network.Stream) {
-		log.Printf("Received incoming signaling connection from %s", s.Conn().RemotePeer())
+		logger.Info("Received incoming signaling connection", "peer", s.Conn().RemotePeer())
 		// defer s.Close()
 
 		// JSON data ko stream se decode aur encode ke liye helpers
 		decoder := json.NewDecoder(s)
 		encoder := json.NewEncoder(s)
 
+		if peers != nil && peers.IsBanned(s.Conn().RemotePeer().String()) {
+			logger.Warn("Rejecting signaling stream from banned peer", "peer", s.Conn().RemotePeer())
+			encoder.Encode(BannedMsg)
+			s.Close()
+			return
+		}
+		if peers != nil && peers.Full() {
+			logger.Warn("Rejecting signaling stream, connection limit reached", "peer", s.Conn().RemotePeer(), "max", peers.MaxConnections)
+			encoder.Encode(TooManyConnectionsMsg)
+			s.Close()
+			return
+		}
+
 		var offer string
 		if err := decoder.Decode(&offer); err != nil {
-			log.Printf("Error decoding offer: %v", err)
+			logger.Warn("Error decoding offer", "error", err)
 			s.Reset()
 			return
 		}
+		msgType, remote, err := parseSignalingLine(offer)
+		if err != nil {
+			rejectMalformedSignaling(s, encoder, err)
+			return
+		}
+
+		// naye clients pehle VERSION bhejte hai, purane clients seedha offer (unhe 1.0 maan lete hai)
+		if msgType == "VERSION" {
+			if !versionCompatible(SignalingVersion, remote) {
+				logger.Warn("Signaling version mismatch", "peer", s.Conn().RemotePeer(), "local", SignalingVersion, "remote", remote)
+				encoder.Encode("VERSION_MISMATCH:" + SignalingVersion)
+				s.Close()
+				return
+			}
+			if err := encoder.Encode("VERSION_OK"); err != nil {
+				logger.Warn("Error sending version reply", "error", err)
+				s.Reset()
+				return
+			}
+			if err := decoder.Decode(&offer); err != nil {
+				logger.Warn("Error decoding offer", "error", err)
+				s.Reset()
+				return
+			}
+			if msgType, _, err = parseSignalingLine(offer); err != nil {
+				rejectMalformedSignaling(s, encoder, err)
+				return
+			}
+		}
+		if msgType != sdpMessageType {
+			rejectMalformedSignaling(s, encoder, fmt.Errorf("%w: expected an offer, got %s", ErrMalformedSignaling, msgType))
+			return
+		}
 
 		//yeh funcction offer ko proccess karke answer generate karta hai
 		answer, err := onOffer(offer, s.Conn().RemotePeer().String(), s)
 		if err != nil {
-			log.Printf("Error handling offer: %v", err)
+			logger.Error("Error handling offer", "error", err)
 			encoder.Encode(fmt.Sprintf("ERROR:%s", err.Error()))
 			s.Reset()
 			return
@@ -41,8 +183,15 @@ func RegisterSignalingProtocol(h host.Host, onOffer func(offer, remotePeerID str
 
 		//generated answer ko encode karke return kar dete hai
 		if err := encoder.Encode(answer); err != nil {
-			log.Printf("Error encoding answer: %v", err)
+			logger.Error("Error encoding answer", "error", err)
 			s.Reset()
 		}
 	})
-}
\ No newline at end of file
+}
+
+// kharab signaling message par remote ko ERROR bhej kar stream reset karta hai
+func rejectMalformedSignaling(s network.Stream, encoder *json.Encoder, err error) {
+	logger.Warn("Rejecting malformed signaling message", "peer", s.Conn().RemotePeer(), "error", err)
+	encoder.Encode("ERROR:" + err.Error())
+	s.Reset()
+}
@@ -0,0 +1,89 @@
+package p2p
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzSignalingParser parseSignalingLine ko remote se aaye kisi bhi string par chalata hai.
+// Parser panic na kare, aur jo message accept ho woh documented format mein hi ho.
+func FuzzSignalingParser(f *testing.F) {
+	seeds := []string{
+		`{"type":"offer","sdp":"v=0\r\no=- 4611731400430051336 2 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"}`,
+		`{"type":"answer","sdp":"v=0\r\no=- 1 2 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"}`,
+		"ERROR:peer connection limit reached",
+		"VERSION:1.0",
+		"VERSION_OK",
+		"",
+		"no colon here",
+		"OFFER:eyJ0eXBlIjoib2ZmZXIi=",
+		"ANSWER:eyJ0eXBlIjoiYW5zd2VyIn0",
+		":data without type",
+		"ERROR:line\nbreak",
+		"\xff\xfe",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		msgType, data, err := parseSignalingLine(s)
+		if err != nil {
+			if msgType != "" || data != "" {
+				t.Fatalf("parseSignalingLine(%q) returned %q, %q along with error %v", s, msgType, data, err)
+			}
+			return
+		}
+		if len(s) > maxSignalingMessage || !utf8.ValidString(s) {
+			t.Fatalf("parseSignalingLine accepted invalid input %q", s)
+		}
+		if msgType == sdpMessageType {
+			if !strings.HasPrefix(s, "{") || data != s {
+				t.Fatalf("parseSignalingLine(%q) = SDP with data %q", s, data)
+			}
+			return
+		}
+		if msgType == "" || strings.Trim(msgType, "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") != "" {
+			t.Fatalf("parseSignalingLine(%q) returned bad type %q", s, msgType)
+		}
+		for _, r := range data {
+			if r < 0x20 || r == 0x7f {
+				t.Fatalf("parseSignalingLine(%q) returned control character in data %q", s, data)
+			}
+		}
+		// type aur data wapas jodne par original message milna chahiye
+		if s != msgType && s != msgType+":"+data {
+			t.Fatalf("parseSignalingLine(%q) = %q, %q does not rebuild the message", s, msgType, data)
+		}
+	})
+}
+
+func TestParseSignalingLine(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantType string
+		wantData string
+		wantErr  bool
+	}{
+		{in: `{"type":"offer","sdp":"v=0"}`, wantType: sdpMessageType, wantData: `{"type":"offer","sdp":"v=0"}`},
+		{in: "ERROR:too many peers", wantType: "ERROR", wantData: "too many peers"},
+		{in: "VERSION_OK", wantType: "VERSION_OK"},
+		{in: "VERSION_MISMATCH:2.0", wantType: "VERSION_MISMATCH", wantData: "2.0"},
+		{in: "", wantErr: true},
+		{in: "offer:lowercase", wantErr: true},
+		{in: ":missing type", wantErr: true},
+		{in: "ERROR:tab\there", wantErr: true},
+		{in: strings.Repeat("A", maxSignalingMessage+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		msgType, data, err := parseSignalingLine(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSignalingLine(%.40q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if msgType != tt.wantType || data != tt.wantData {
+			t.Errorf("parseSignalingLine(%.40q) = %q, %q; want %q, %q", tt.in, msgType, data, tt.wantType, tt.wantData)
+		}
+	}
+}
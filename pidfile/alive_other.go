@@ -0,0 +1,11 @@
+//go:build !unix
+
+package pidfile
+
+import "os"
+
+// non-unix platforms par FindProcess tabhi successful hota hai jab process chal raha ho
+func processAlive(pid int) bool {
+	_, err := os.FindProcess(pid)
+	return err == nil
+}
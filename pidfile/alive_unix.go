@@ -0,0 +1,19 @@
+//go:build unix
+
+package pidfile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// signal 0 kuch nahi bhejta, bas batata hai ki process hai ya nahi. EPERM ka matlab process hai par kisi aur user ka.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
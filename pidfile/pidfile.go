@@ -0,0 +1,42 @@
+// Package pidfile background service ki tarah chal rahe binaries ke liye PID file likhta hai,
+// taaki operators process dhoondh kar signal bhej sake aur ek hi jagah do instances na chale.
+package pidfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrRunning tab aata hai jab PID file mein likha process abhi bhi chal raha hai
+var ErrRunning = errors.New("another instance is already running")
+
+// Write path par current process ka PID likhta hai. File pehle se ho aur uska PID zinda ho toh ErrRunning;
+// mare hue process ki bachi hui file overwrite ho jaati hai. Clean exit par caller Remove call kare.
+func Write(path string) error {
+	if data, err := os.ReadFile(path); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid != os.Getpid() && processAlive(pid) {
+			return fmt.Errorf("%w (pid %d, from %s)", ErrRunning, pid, path)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+}
+
+// Remove PID file hata deta hai, agar usmein abhi bhi humara hi PID hai
+func Remove(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		return nil
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,114 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"torrentium/p2p"
+	"torrentium/webRTC"
+)
+
+// progress bar ki width (characters mein)
+const barWidth = 30
+
+// kitni der mein ek baar bar ko redraw karna hai
+const redrawInterval = 100 * time.Millisecond
+
+// Renderer TransferEvent stream ko padh ke terminal par ek updating progress bar dikhata hai.
+type Renderer struct {
+	out    io.Writer
+	prompt string // transfer complete hone ke baad dobara print hone wala prompt
+
+	mu        sync.Mutex
+	startedAt map[string]time.Time
+	lastDraw  map[string]time.Time
+}
+
+// NewRenderer ek naya renderer banata hai jo out par likhta hai
+func NewRenderer(out io.Writer, prompt string) *Renderer {
+	return &Renderer{
+		out:       out,
+		prompt:    prompt,
+		startedAt: make(map[string]time.Time),
+		lastDraw:  make(map[string]time.Time),
+	}
+}
+
+// Run events channel band hone tak events render karta hai
+func (r *Renderer) Run(events <-chan webRTC.TransferEvent) {
+	for ev := range events {
+		r.Handle(ev)
+	}
+}
+
+// Handle ek single event ko render karta hai
+func (r *Renderer) Handle(ev webRTC.TransferEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	started, ok := r.startedAt[ev.Filename]
+	if !ok || ev.Type == webRTC.TransferStart {
+		started = now
+		r.startedAt[ev.Filename] = now
+	}
+
+	switch ev.Type {
+	case webRTC.TransferCorrupt:
+		fmt.Fprintf(r.out, "\r%s failed the integrity check and was deleted\n%s", ev.Filename, r.prompt)
+	case webRTC.TransferComplete, webRTC.TransferFailed:
+		r.draw(ev, started, now)
+		if ev.Type == webRTC.TransferFailed {
+			fmt.Fprint(r.out, " failed")
+			if ev.PeerID != "" {
+				fmt.Fprintf(r.out, " (peer %s)", p2p.FormatPeerIDString(ev.PeerID, p2p.ShortPeerIDLength))
+			}
+		}
+		// newline ke baad prompt dobara print karte hai taaki command line kharab na ho
+		fmt.Fprintf(r.out, "\n%s", r.prompt)
+		delete(r.startedAt, ev.Filename)
+		delete(r.lastDraw, ev.Filename)
+	default:
+		if now.Sub(r.lastDraw[ev.Filename]) < redrawInterval {
+			return
+		}
+		r.lastDraw[ev.Filename] = now
+		r.draw(ev, started, now)
+	}
+}
+
+// ek line mein filename, bar, percentage, bytes, speed aur ETA likhta hai
+func (r *Renderer) draw(ev webRTC.TransferEvent, started, now time.Time) {
+	elapsed := now.Sub(started).Seconds()
+	var speed float64
+	if elapsed > 0 {
+		speed = float64(ev.BytesDone) / elapsed
+	}
+
+	if ev.TotalBytes <= 0 {
+		// size pata nahi hai, toh sirf bytes aur speed dikhate hai
+		fmt.Fprintf(r.out, "\r%s %s %s/s   ", ev.Filename,
+			webRTC.FormatFileSizeIEC(ev.BytesDone), webRTC.FormatFileSizeIEC(int64(speed)))
+		return
+	}
+
+	fraction := float64(ev.BytesDone) / float64(ev.TotalBytes)
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction * barWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+
+	eta := "--"
+	if speed > 0 {
+		remaining := float64(ev.TotalBytes-ev.BytesDone) / speed
+		eta = (time.Duration(remaining) * time.Second).String()
+	}
+
+	fmt.Fprintf(r.out, "\r%s [%s] %5.1f%% %s/%s %s/s ETA %s   ", ev.Filename, bar, fraction*100,
+		webRTC.FormatFileSizeIEC(ev.BytesDone), webRTC.FormatFileSizeIEC(ev.TotalBytes),
+		webRTC.FormatFileSizeIEC(int64(speed)), eta)
+}
@@ -0,0 +1,436 @@
+package quictransport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/quic-go/quic-go"
+
+	"torrentium/logging"
+	"torrentium/webRTC"
+)
+
+// quictransport package ka logger
+var logger = logging.For("quic")
+
+// ALPN protocol jo dono side TLS handshake mein match karte hai
+const alpnProtocol = "torrentium-quic"
+
+// ek frame ka max payload size, isse bade frames ko corrupt maan kar connection band kar dete hai
+const maxFrameSize = 1 << 20
+
+// frame types: text (JSON commands) aur binary (file chunks)
+const (
+	frameText   byte = 't'
+	frameBinary byte = 'b'
+)
+
+// QUIC stream par aaye messages ko handle karta hai, isString true ho toh data JSON command hai
+type MessageHandler func(data []byte, isString bool, t *QuicTransfer)
+
+// QuicTransfer ek peer ke saath QUIC connection aur uske ek bidirectional stream ko represent karta hai.
+// WebRTC fail hone par yeh fallback transport ki tarah use hota hai, API WebRTCPeer jaisa hi hai.
+type QuicTransfer struct {
+	ReceiveDir         string // peer se aayi files sirf is directory mein likhi jaati hai (default ./downloads)
+	MaxReceiveFileSize int64  // isse badi aane wali files CANCEL ho jaati hai, 0 ho toh koi limit nahi
+	conn               quic.Connection
+	stream             quic.Stream
+	remotePeerID       string
+	onMessage          MessageHandler
+	writeMu            sync.Mutex // ek time par ek hi frame likha jaye
+	mu                 sync.RWMutex
+	fileWriter         io.WriteCloser
+	closed             bool
+
+	events        chan webRTC.TransferEvent // transfer progress ke events
+	transferName  string
+	transferTotal int64
+	transferDone  int64
+}
+
+// Listener aane wale QUIC connections accept karta hai
+type Listener struct {
+	ln        *quic.Listener
+	peerID    string
+	onMessage MessageHandler
+}
+
+// Listen diye gaye UDP address par QUIC listener start karta hai. localPeerID dialer ko hello mein bheja jaata hai.
+func Listen(addr, localPeerID string, onMessage MessageHandler) (*Listener, error) {
+	tlsConf, err := generateTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	ln, err := quic.ListenAddr(addr, tlsConf, &quic.Config{KeepAlivePeriod: 15 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for QUIC on %s: %w", addr, err)
+	}
+	return &Listener{ln: ln, peerID: localPeerID, onMessage: onMessage}, nil
+}
+
+// Addr listener ka local UDP address return karta hai
+func (l *Listener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Accept agle incoming connection ka wait karta hai aur hello exchange ke baad QuicTransfer return karta hai
+func (l *Listener) Accept(ctx context.Context) (*QuicTransfer, error) {
+	conn, err := l.ln.Accept(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.AcceptStream(ctx)
+	if err != nil {
+		conn.CloseWithError(0, "no stream")
+		return nil, err
+	}
+	t := newQuicTransfer(conn, stream, l.onMessage)
+	if err := t.exchangeHello(l.peerID); err != nil {
+		t.Close()
+		return nil, err
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+func (l *Listener) Close() error {
+	return l.ln.Close()
+}
+
+// Dial diye gaye UDP address par QUIC connection aur ek stream kholta hai
+func Dial(ctx context.Context, addr, localPeerID string, onMessage MessageHandler) (*QuicTransfer, error) {
+	tlsConf := &tls.Config{
+		// peers self-signed certificates use karte hai, peer ki pehchaan hello mein aaye peer ID se hoti hai
+		InsecureSkipVerify: true,
+		NextProtos:         []string{alpnProtocol},
+	}
+	conn, err := quic.DialAddr(ctx, addr, tlsConf, &quic.Config{KeepAlivePeriod: 15 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial QUIC %s: %w", addr, err)
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		conn.CloseWithError(0, "open stream failed")
+		return nil, err
+	}
+	t := newQuicTransfer(conn, stream, onMessage)
+	if err := t.exchangeHello(localPeerID); err != nil {
+		t.Close()
+		return nil, err
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+func newQuicTransfer(conn quic.Connection, stream quic.Stream, onMessage MessageHandler) *QuicTransfer {
+	return &QuicTransfer{
+		ReceiveDir: webRTC.DefaultReceiveDir,
+		conn:       conn,
+		stream:     stream,
+		onMessage:  onMessage,
+		events:     make(chan webRTC.TransferEvent, 64),
+	}
+}
+
+// hello frame mein dono side apna peer ID bhejte hai. Dialer ka pehla frame hi stream ko remote side par visible banata hai.
+func (t *QuicTransfer) exchangeHello(localPeerID string) error {
+	hello, _ := json.Marshal(map[string]string{"command": "HELLO", "peer_id": localPeerID})
+	if err := t.writeFrame(frameText, hello); err != nil {
+		return err
+	}
+	t.stream.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer t.stream.SetReadDeadline(time.Time{})
+
+	kind, data, err := t.readFrame()
+	if err != nil {
+		return fmt.Errorf("failed to read QUIC hello: %w", err)
+	}
+	var msg struct {
+		Command string `json:"command"`
+		PeerID  string `json:"peer_id"`
+	}
+	if kind != frameText || json.Unmarshal(data, &msg) != nil || msg.Command != "HELLO" {
+		return errors.New("invalid QUIC hello")
+	}
+	t.remotePeerID = msg.PeerID
+	return nil
+}
+
+// stream se frames padh kar onMessage ko deta hai, jab tak stream band nahi hota
+func (t *QuicTransfer) readLoop() {
+	defer t.Close()
+	for {
+		kind, data, err := t.readFrame()
+		if err != nil {
+			if !t.isClosed() && !errors.Is(err, io.EOF) {
+				logger.Warn("QUIC stream read failed", "peer", t.remotePeerID, "error", err)
+			}
+			return
+		}
+		if kind == frameBinary {
+			t.recordReceived(len(data))
+		}
+		t.onMessage(data, kind == frameText, t)
+	}
+}
+
+// frame format: 1 byte type + 4 byte big-endian length + payload
+func (t *QuicTransfer) writeFrame(kind byte, data []byte) error {
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("frame too large: %d bytes", len(data))
+	}
+	header := make([]byte, 5)
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(data)))
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stream.Write(header); err != nil {
+		return err
+	}
+	_, err := t.stream.Write(data)
+	return err
+}
+
+func (t *QuicTransfer) readFrame() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(t.stream, header); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame too large: %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(t.stream, data); err != nil {
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
+
+// RemotePeerID hello mein mila remote peer ka libp2p peer ID return karta hai
+func (t *QuicTransfer) RemotePeerID() string {
+	return t.remotePeerID
+}
+
+// data ko JSON mein serialize karke text frame ki tarah bhejta hai
+func (t *QuicTransfer) SendTextData(data interface{}) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return t.writeFrame(frameText, bytes)
+}
+
+// file data ko binary frame mein bhejta hai
+func (t *QuicTransfer) SendBinaryData(data []byte) error {
+	return t.writeFrame(frameBinary, data)
+}
+
+// remote peer se ek file maangta hai, file binary frames mein aati hai aur end mein TRANSFER_COMPLETE status
+func (t *QuicTransfer) RequestFile(fileID string) error {
+	return t.SendTextData(map[string]string{"command": "REQUEST_FILE", "file_id": fileID})
+}
+
+// SendFileWithContext WebRTCPeer jaisa hi file ko chunks mein bhejta hai, ctx cancel hone par ruk jaata hai
+func (t *QuicTransfer) SendFileWithContext(ctx context.Context, filename string, pieceSize int) error {
+	return webRTC.SendFile(ctx, filename, pieceSize, t.SendBinaryData, t.SendTextData)
+}
+
+// ReceiveFile FILE_START mein aaye filename ke liye ReceiveDir ke andar output file kholta hai
+func (t *QuicTransfer) ReceiveFile(filename string) (io.WriteCloser, error) {
+	return webRTC.CreateReceiveFile(t.ReceiveDir, filename)
+}
+
+// ReceiveStream size 0 wale FILE_START ke liye ReceiveDir mein file kholta hai, MaxReceiveFileSize ke budget ke saath
+func (t *QuicTransfer) ReceiveStream(filename string) (io.WriteCloser, error) {
+	return webRTC.CreateReceiveStream(t.ReceiveDir, filename, t.MaxReceiveFileSize)
+}
+
+// ReceiveFileAt range download ke liye file ko offset par kholta hai (truncate nahi karta)
+func (t *QuicTransfer) ReceiveFileAt(filename string, offset int64) (io.WriteCloser, error) {
+	return webRTC.OpenReceiveFileAt(t.ReceiveDir, filename, offset)
+}
+
+// RequestFileRange WebRTCPeer jaisa hi file ke [start, end) bytes maangta hai
+func (t *QuicTransfer) RequestFileRange(fileID string, start, end int64) error {
+	return t.SendTextData(webRTC.RangeRequest(fileID, start, end))
+}
+
+// SendFileRange WebRTCPeer jaisa hi file ke [start, end) bytes bhejta hai
+func (t *QuicTransfer) SendFileRange(ctx context.Context, filename string, start, end int64) error {
+	return webRTC.SendFileRange(ctx, filename, start, end, webRTC.DefaultPieceSize, t.SendBinaryData, t.SendTextData)
+}
+
+// ReceiveDirectory tar.gz directory stream ke liye writer deta hai jo ReceiveDir mein extract karta hai
+func (t *QuicTransfer) ReceiveDirectory() (io.WriteCloser, error) {
+	return webRTC.CreateReceiveDirectory(t.ReceiveDir, t.MaxReceiveFileSize)
+}
+
+// SendDirectory WebRTCPeer jaisa hi directory ko tar.gz stream karta hai
+func (t *QuicTransfer) SendDirectory(ctx context.Context, dirPath string) error {
+	return webRTC.SendDirectory(ctx, dirPath, webRTC.DefaultPieceSize, t.SendBinaryData, t.SendTextData)
+}
+
+// SendStream WebRTCPeer jaisa hi r ke bytes EOF tak name naam se bhejta hai
+func (t *QuicTransfer) SendStream(ctx context.Context, r io.Reader, name string) error {
+	return webRTC.SendStream(ctx, r, name, webRTC.DefaultPieceSize, t.SendBinaryData, t.SendTextData)
+}
+
+// CheckReceiveSpace size bytes ki file ko MaxReceiveFileSize se aur ReceiveDir ki free space se check karta hai
+func (t *QuicTransfer) CheckReceiveSpace(size int64) error {
+	if err := webRTC.CheckSizeLimit(t.MaxReceiveFileSize, size); err != nil {
+		return err
+	}
+	return webRTC.CheckDiskSpace(t.ReceiveDir, size)
+}
+
+// SendCancel WebRTCPeer jaisa hi sender ko filename ka transfer cancel karne ko kehta hai
+func (t *QuicTransfer) SendCancel(filename, reason string) error {
+	return t.SendTextData(webRTC.CancelMessage(filename, reason))
+}
+
+// SendNack WebRTCPeer jaisa hi ek kharab piece dobara maangta hai
+func (t *QuicTransfer) SendNack(fileHash, filename string, piece int, pieceLength int64) error {
+	return t.SendTextData(webRTC.NackMessage(fileHash, filename, piece, pieceLength))
+}
+
+// SendPiece WebRTCPeer jaisa hi NACK kiya gaya piece dobara bhejta hai
+func (t *QuicTransfer) SendPiece(ctx context.Context, filename, fileHash string, piece int, pieceLength int64) error {
+	return webRTC.SendPiece(ctx, filename, fileHash, piece, pieceLength, webRTC.DefaultPieceSize, t.SendBinaryData, t.SendTextData)
+}
+
+func (t *QuicTransfer) SetFileWriter(writer io.WriteCloser) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fileWriter = writer
+}
+
+func (t *QuicTransfer) GetFileWriter() io.WriteCloser {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.fileWriter
+}
+
+// Events channel return karta hai jispe is transfer ke progress events aate hain
+func (t *QuicTransfer) Events() <-chan webRTC.TransferEvent {
+	return t.events
+}
+
+// SetTransferInfo aane wali file ka naam aur size set karta hai aur "start" event bhejta hai
+func (t *QuicTransfer) SetTransferInfo(filename string, totalBytes int64) {
+	t.mu.Lock()
+	t.transferName = filename
+	t.transferTotal = totalBytes
+	t.transferDone = 0
+	t.mu.Unlock()
+
+	t.emit(webRTC.TransferEvent{Type: webRTC.TransferStart, Filename: filename, TotalBytes: totalBytes})
+}
+
+// CompleteTransfer current transfer ko complete mark karke "complete" event bhejta hai
+func (t *QuicTransfer) CompleteTransfer() {
+	t.mu.RLock()
+	ev := webRTC.TransferEvent{Type: webRTC.TransferComplete, Filename: t.transferName, BytesDone: t.transferDone, TotalBytes: t.transferTotal}
+	t.mu.RUnlock()
+
+	t.emit(ev)
+}
+
+func (t *QuicTransfer) recordReceived(n int) {
+	t.mu.Lock()
+	t.transferDone += int64(n)
+	ev := webRTC.TransferEvent{Type: webRTC.TransferProgress, Filename: t.transferName, BytesDone: t.transferDone, TotalBytes: t.transferTotal}
+	t.mu.Unlock()
+
+	t.emit(ev)
+}
+
+func (t *QuicTransfer) emit(ev webRTC.TransferEvent) {
+	ev.PeerID = t.RemotePeerID()
+	select {
+	case t.events <- ev:
+	default:
+	}
+}
+
+func (t *QuicTransfer) isClosed() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.closed
+}
+
+// stream aur connection dono band karta hai, dobara call karna safe hai
+func (t *QuicTransfer) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mu.Unlock()
+
+	t.stream.Close()
+	return t.conn.CloseWithError(0, "closed")
+}
+
+// AddrFromMultiaddr libp2p multiaddr (jaise /ip4/1.2.3.4/tcp/4001/ws) se QUIC dial ke liye host:port nikalta hai.
+// Peers QUIC ko apne WebSocket TCP port wale number par hi UDP mein listen karate hai.
+func AddrFromMultiaddr(addr ma.Multiaddr) (string, error) {
+	var host, port string
+	for _, c := range addr {
+		switch c.Protocol().Code {
+		case ma.P_IP4, ma.P_IP6, ma.P_DNS, ma.P_DNS4, ma.P_DNS6:
+			if host == "" {
+				host = c.Value()
+			}
+		case ma.P_TCP, ma.P_UDP:
+			if port == "" {
+				port = c.Value()
+			}
+		}
+	}
+	if host == "" || port == "" {
+		return "", fmt.Errorf("multiaddr %s has no host/port", addr)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p == 0 {
+		return "", fmt.Errorf("multiaddr %s has invalid port", addr)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// QUIC ke liye ek self-signed TLS certificate generate karta hai
+func generateTLSConfig() (*tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{certDER}, PrivateKey: key}},
+		NextProtos:   []string{alpnProtocol},
+	}, nil
+}
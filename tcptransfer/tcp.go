@@ -0,0 +1,262 @@
+// Package tcptransfer seedha TCP connection par ek file download karne ka fallback hai, un networks ke liye
+// jahan WebRTC (aur QUIC ka UDP) poori tarah block hai. Protocol line-based hai: server connect hote hi
+// "NONCE <hex>" bhejta hai, downloader "GET <file_id> <peer_id> <signature>" bhejta hai jismein signature
+// (base64) uski libp2p key se nonce, file ID aur peer ID par hota hai. Signature sahi ho aur Authorize peer ko
+// allow kare tabhi server "OK <size>" ke baad file ke bytes bhejta hai, warna "ERR <reason>" bhej kar connection band.
+package tcptransfer
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"torrentium/logging"
+)
+
+// tcptransfer package ka logger
+var logger = logging.For("tcp")
+
+// requestTimeout mein downloader ki request line aa jaani chahiye, warna connection band
+const requestTimeout = 10 * time.Second
+
+// maxRequestLine request line ki maximum length hai (file ID, peer ID aur base64 signature ke liye kaafi)
+const maxRequestLine = 512
+
+// nonceSize har connection ke challenge ke random bytes
+const nonceSize = 32
+
+// ErrInvalidSignature tab aata hai jab GET ka signature requester ke peer ID ki public key se verify nahi hota
+var ErrInvalidSignature = errors.New("invalid request signature")
+
+// Authorize verified requester ke liye file ID ko local share ki hui file ke path mein badalta hai.
+// Error ho toh file nahi milti aur error ka text "ERR" reason mein requester ko jaata hai.
+type Authorize func(fileID string, requester peer.ID) (path string, err error)
+
+// TCPServer share ki hui files TCP par serve karta hai
+type TCPServer struct {
+	ln        net.Listener
+	authorize Authorize
+}
+
+// StartServer port par TCP listener kholta hai (0 ho toh koi bhi free port) aur background mein connections serve karta hai.
+// Har request ka signature check hota hai aur file sirf tab milti hai jab authorize requester ko allow kare.
+// ctx cancel hone par ya Close par listener band hota hai.
+func StartServer(ctx context.Context, port int, authorize Authorize) (*TCPServer, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, err
+	}
+	s := &TCPServer{ln: ln, authorize: authorize}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go s.serve()
+	return s, nil
+}
+
+// Port woh port hai jis par server sach mein sun raha hai
+func (s *TCPServer) Port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+// Close listener band karta hai, chal rahe downloads poore hone dete hai
+func (s *TCPServer) Close() error {
+	return s.ln.Close()
+}
+
+func (s *TCPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Warn("TCP accept failed", "error", err)
+			}
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// ek connection par ek file bhejta hai
+func (s *TCPServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		logger.Warn("Failed to generate TCP nonce", "error", err)
+		return
+	}
+	if _, err := fmt.Fprintf(conn, "NONCE %s\n", hex.EncodeToString(nonce)); err != nil {
+		return
+	}
+
+	conn.SetReadDeadline(time.Now().Add(requestTimeout))
+	line, err := bufio.NewReaderSize(conn, maxRequestLine).ReadSlice('\n')
+	if err != nil {
+		logger.Debug("Bad TCP request", "remote", conn.RemoteAddr(), "error", err)
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	fileID, requester, err := parseRequest(strings.TrimSpace(string(line)), nonce)
+	if err != nil {
+		logger.Warn("Rejected TCP request", "remote", conn.RemoteAddr(), "error", err)
+		fmt.Fprintf(conn, "ERR %s\n", err)
+		return
+	}
+	path, err := s.authorize(fileID, requester)
+	if err != nil {
+		logger.Warn("Refused TCP request", "file_id", fileID, "peer", requester, "remote", conn.RemoteAddr(), "error", err)
+		fmt.Fprintf(conn, "ERR %s\n", err)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Warn("Failed to open shared file", "path", path, "error", err)
+		fmt.Fprintf(conn, "ERR file unavailable\n")
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		fmt.Fprintf(conn, "ERR file unavailable\n")
+		return
+	}
+
+	logger.Info("Serving file over TCP", "file_id", fileID, "bytes", info.Size(), "peer", requester, "remote", conn.RemoteAddr())
+	if _, err := fmt.Fprintf(conn, "OK %d\n", info.Size()); err != nil {
+		return
+	}
+	if _, err := io.Copy(conn, f); err != nil {
+		logger.Warn("TCP transfer failed", "file_id", fileID, "remote", conn.RemoteAddr(), "error", err)
+	}
+}
+
+// RequestDigest woh bytes hai jin par downloader sign karta hai: SHA256(nonce + file_id + peer_id).
+// Nonce har connection ka naya hai, isliye pakda gaya signature dobara use nahi ho sakta.
+func RequestDigest(nonce []byte, fileID, peerID string) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, nonce...), fileID+peerID...))
+	return sum[:]
+}
+
+// "GET <file_id> <peer_id> <signature>" line parse karke signature verify karta hai aur requester ka peer ID deta hai.
+// Public key peer ID se nikalti hai (Ed25519/secp256k1), isliye alag se key bhejni nahi padti.
+func parseRequest(line string, nonce []byte) (string, peer.ID, error) {
+	rest, ok := strings.CutPrefix(line, "GET ")
+	if !ok {
+		return "", "", errors.New("invalid request")
+	}
+	fields := strings.Fields(rest)
+	if len(fields) != 3 {
+		return "", "", errors.New("invalid request")
+	}
+	fileID, peerID := fields[0], fields[1]
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return "", "", errors.New("invalid peer ID")
+	}
+	sig, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return "", "", ErrInvalidSignature
+	}
+	pub, err := id.ExtractPublicKey()
+	if err != nil {
+		return "", "", errors.New("peer ID has no embedded public key")
+	}
+	if ok, err := pub.Verify(RequestDigest(nonce, fileID, peerID), sig); err != nil || !ok {
+		return "", "", ErrInvalidSignature
+	}
+	return fileID, id, nil
+}
+
+// DownloadViaTCP host:port wale server se fileID ki file download karke dest mein likhta hai. Request key (downloader
+// ki libp2p private key) se sign hoti hai, taaki seeder allow list aur bans us peer ID par check kar sake.
+// Poori file na aaye toh adhoori dest delete ho jaati hai. ctx cancel hone par connection turant band hota hai.
+func DownloadViaTCP(ctx context.Context, host, port string, fileID string, dest string, key crypto.PrivKey) error {
+	if key == nil {
+		return errors.New("no private key to sign the request with")
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	challenge, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read challenge: %w", err)
+	}
+	nonceHex, ok := strings.CutPrefix(strings.TrimSpace(challenge), "NONCE ")
+	if !ok {
+		return fmt.Errorf("unexpected challenge %q", strings.TrimSpace(challenge))
+	}
+	nonce, err := hex.DecodeString(nonceHex)
+	if err != nil || len(nonce) != nonceSize {
+		return fmt.Errorf("invalid nonce %q", nonceHex)
+	}
+	sig, err := key.Sign(RequestDigest(nonce, fileID, id.String()))
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(conn, "GET %s %s %s\n", fileID, id, base64.StdEncoding.EncodeToString(sig)); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	status, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	status = strings.TrimSpace(status)
+	if reason, ok := strings.CutPrefix(status, "ERR "); ok {
+		return fmt.Errorf("server refused: %s", reason)
+	}
+	sizeStr, ok := strings.CutPrefix(status, "OK ")
+	if !ok {
+		return fmt.Errorf("unexpected response %q", status)
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid size %q", sizeStr)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyN(f, r, size)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dest)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("download interrupted: %w", err)
+	}
+	return nil
+}
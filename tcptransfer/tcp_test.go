@@ -0,0 +1,121 @@
+package tcptransfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func newKey(t *testing.T) (crypto.PrivKey, peer.ID) {
+	t.Helper()
+	priv, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, id
+}
+
+func TestDownloadViaTCPAuthorizesRequester(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "shared.bin")
+	want := make([]byte, 64*1024)
+	rand.Read(want)
+	if err := os.WriteFile(src, want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	allowedKey, allowedID := newKey(t)
+	otherKey, _ := newKey(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, err := StartServer(ctx, 0, func(fileID string, requester peer.ID) (string, error) {
+		if fileID != "file-1" {
+			return "", errors.New("file not shared")
+		}
+		if requester != allowedID {
+			return "", errors.New("access denied")
+		}
+		return src, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	port := strconv.Itoa(srv.Port())
+
+	tests := []struct {
+		name    string
+		fileID  string
+		key     crypto.PrivKey
+		wantErr bool
+	}{
+		{name: "allowed peer", fileID: "file-1", key: allowedKey},
+		{name: "other peer", fileID: "file-1", key: otherKey, wantErr: true},
+		{name: "unknown file", fileID: "file-2", key: allowedKey, wantErr: true},
+		{name: "no key", fileID: "file-1", key: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "out.bin")
+			err := DownloadViaTCP(ctx, "127.0.0.1", port, tt.fileID, dest, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected download to be refused")
+				}
+				if _, statErr := os.Stat(dest); !os.IsNotExist(statErr) {
+					t.Fatalf("refused download left %s behind", dest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatal("downloaded bytes differ from the shared file")
+			}
+		})
+	}
+}
+
+func TestParseRequestRejectsBadSignature(t *testing.T) {
+	key, id := newKey(t)
+	nonce := []byte("0123456789abcdef0123456789abcdef")
+	sig, err := key.Sign(RequestDigest(nonce, "file-1", id.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	good := "GET file-1 " + id.String() + " " + base64.StdEncoding.EncodeToString(sig)
+
+	if _, got, err := parseRequest(good, nonce); err != nil || got != id {
+		t.Fatalf("parseRequest(valid) = %v, %v", got, err)
+	}
+	// dusre connection ka nonce ya badli hui file ID: purana signature kaam nahi aana chahiye
+	if _, _, err := parseRequest(good, []byte("another nonce another nonce 1234")); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("replayed signature: err = %v, want ErrInvalidSignature", err)
+	}
+	forged := "GET file-2 " + id.String() + " " + base64.StdEncoding.EncodeToString(sig)
+	if _, _, err := parseRequest(forged, nonce); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("signature for another file: err = %v, want ErrInvalidSignature", err)
+	}
+	if _, _, err := parseRequest("GET file-1", nonce); err == nil {
+		t.Fatal("old unsigned request was accepted")
+	}
+}
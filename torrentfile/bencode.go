@@ -0,0 +1,28 @@
+package torrentfile
+
+import (
+	"bytes"
+
+	bencode "github.com/jackpal/bencode-go"
+)
+
+// BencodeMarshal kisi bhi value (struct, map, slice, string, integer) ko bencoded bytes mein convert karta hai.
+// Struct fields ke naam `bencode` tags se liye jaate hai.
+func BencodeMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := bencode.Marshal(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BencodeUnmarshal bencoded data ko decode karke v mein bharta hai; v ek non-nil pointer hona chahiye
+func BencodeUnmarshal(data []byte, v interface{}) error {
+	return bencode.Unmarshal(bytes.NewReader(data), v)
+}
+
+// BencodeDecode bencoded data ko generic values mein decode karta hai: dict map[string]interface{}, list []interface{},
+// integer int64 aur string string banta hai. Jab data ka shape pehle se pata na ho (jaise tracker response) tab kaam aata hai.
+func BencodeDecode(data []byte) (interface{}, error) {
+	return bencode.Decode(bytes.NewReader(data))
+}
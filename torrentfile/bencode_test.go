@@ -0,0 +1,112 @@
+package torrentfile
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// bencodeValues mein bencode ke saare types hai: strings, integers, lists aur dicts
+type bencodeValues struct {
+	Str  string            `bencode:"str"`
+	Int  int64             `bencode:"int"`
+	List []string          `bencode:"list"`
+	Ints []int64           `bencode:"ints"`
+	Dict map[string]string `bencode:"dict"`
+	Raw  string            `bencode:"raw"` // binary data (jaise info ke pieces) string mein hi rakha jaata hai
+}
+
+// bencode mein khaali aur nil list/dict ek jaise encode hote hai, isliye compare se pehle dono ko nil kar dete hai
+func (v *bencodeValues) normalize() {
+	if len(v.List) == 0 {
+		v.List = nil
+	}
+	if len(v.Ints) == 0 {
+		v.Ints = nil
+	}
+	if len(v.Dict) == 0 {
+		v.Dict = nil
+	}
+}
+
+func TestBencodeRoundTrip(t *testing.T) {
+	roundTrip := func(in bencodeValues, raw []byte) bool {
+		in.Raw = string(raw)
+		data, err := BencodeMarshal(in)
+		if err != nil {
+			t.Logf("BencodeMarshal: %v", err)
+			return false
+		}
+		var out bencodeValues
+		if err := BencodeUnmarshal(data, &out); err != nil {
+			t.Logf("BencodeUnmarshal(%q): %v", data, err)
+			return false
+		}
+		in.normalize()
+		out.normalize()
+		return reflect.DeepEqual(in, out)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBencodeTorrentMetaRoundTrip(t *testing.T) {
+	roundTrip := func(info TorrentInfo, filename, comment string, length, createdAt int64) bool {
+		in := TorrentMeta{
+			Filename:  filename,
+			Length:    length,
+			Hash:      "3f786850e387550fdab836ed7e6dc881de23001b",
+			CreatedAt: createdAt,
+			Announce:  "http://tracker.example.com/announce",
+			AnnounceList: [][]string{
+				{"http://tracker.example.com/announce"},
+				{"http://backup.example.com/announce", "udp://backup.example.com:6969"},
+			},
+			Comment:   comment,
+			CreatedBy: "torrentium",
+			Info:      info,
+		}
+		data, err := BencodeMarshal(in)
+		if err != nil {
+			t.Logf("BencodeMarshal: %v", err)
+			return false
+		}
+		var out TorrentMeta
+		if err := BencodeUnmarshal(data, &out); err != nil {
+			t.Logf("BencodeUnmarshal: %v", err)
+			return false
+		}
+		// info dictionary badle bina wapas aaye toh info hash bhi wahi rehta hai
+		return reflect.DeepEqual(in, out)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBencodeDecodeTypes(t *testing.T) {
+	data, err := BencodeMarshal(map[string]interface{}{
+		"int":  int64(-42),
+		"list": []interface{}{"a", int64(1)},
+		"str":  "spam",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "d3:inti-42e4:listl1:ai1ee3:str4:spame"; string(data) != want {
+		t.Fatalf("BencodeMarshal = %q, want %q", data, want)
+	}
+	got, err := BencodeDecode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"int":  int64(-42),
+		"list": []interface{}{"a", int64(1)},
+		"str":  "spam",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BencodeDecode = %#v, want %#v", got, want)
+	}
+}
@@ -1,8 +1,10 @@
 package torrentfile
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"os"
 	"time"
@@ -10,51 +12,206 @@ import (
 	bencode "github.com/jackpal/bencode-go"
 )
 
+// DefaultPieceLength ek piece ka default size hai (256 KiB)
+const DefaultPieceLength = 256 * 1024
+
 //yeh struct .torrentl file ka metadata define karta hai.Bencode format mein encode hota hai.
 type TorrentMeta struct {
-	Filename  string    `bencode:"filename"`
-	Length    int64     `bencode:"length"`
-	Hash      string    `bencode:"hash"`
-	CreatedAt int64 `bencode:"created_at"`
+	Filename     string      `bencode:"filename"`
+	Length       int64       `bencode:"length"`
+	Hash         string      `bencode:"hash"`
+	CreatedAt    int64       `bencode:"created_at"`
+	Announce     string      `bencode:"announce,omitempty"`      // BEP 3 tracker URL
+	AnnounceList [][]string  `bencode:"announce-list,omitempty"` // BEP 12 tracker tiers
+	Comment      string      `bencode:"comment,omitempty"`
+	CreatedBy    string      `bencode:"created by,omitempty"`
+	Info         TorrentInfo `bencode:"info"` // BitTorrent (BEP 3) compatible info dictionary
+}
+
+// TorrentConfig .torrent file ke optional top-level keys hai. Empty fields file mein nahi likhe jaate.
+// AnnounceList BEP 12 ke tiers hai (har tier trackers ki list); Announce set ho aur AnnounceList khaali,
+// toh announce-list mein Announce hi ek tier ki tarah jaata hai.
+type TorrentConfig struct {
+	Announce     string
+	AnnounceList [][]string
+	Comment      string
+	CreatedBy    string
+}
+
+// cfg ke keys meta mein bharta hai
+func (cfg TorrentConfig) apply(meta *TorrentMeta) {
+	meta.Announce = cfg.Announce
+	meta.AnnounceList = cfg.AnnounceList
+	if len(meta.AnnounceList) == 0 && cfg.Announce != "" {
+		meta.AnnounceList = [][]string{{cfg.Announce}}
+	}
+	meta.Comment = cfg.Comment
+	meta.CreatedBy = cfg.CreatedBy
 }
 
+// TorrentInfo BitTorrent spec wali single-file `info` dictionary hai.
+// Pieces mein har piece ka 20-byte SHA-1 hash concatenated hota hai.
+type TorrentInfo struct {
+	Name        string `bencode:"name"`
+	Length      int64  `bencode:"length"`
+	PieceLength int64  `bencode:"piece length"`
+	Pieces      string `bencode:"pieces"`
+}
+
+// InfoHash bencoded `info` dictionary ka SHA-1 hash return karta hai, jaisa BitTorrent clients aur trackers use karte hai
+func InfoHash(meta *TorrentMeta) ([20]byte, error) {
+	encoded, err := BencodeMarshal(meta.Info)
+	if err != nil {
+		return [20]byte{}, err
+	}
+	return sha1.Sum(encoded), nil
+}
+
+// PieceCount filesize bytes ki file ke pieces ki ginti hai, yaani ceil(filesize / pieceLength).
+// size ya pieceLength <= 0 ho toh 0. size + pieceLength - 1 overflow kar sakta hai, isliye remainder se round up karte hai.
+func PieceCount(filesize, pieceLength int64) int {
+	if filesize <= 0 || pieceLength <= 0 {
+		return 0
+	}
+	n := filesize / pieceLength
+	if filesize%pieceLength != 0 {
+		n++
+	}
+	return int(n)
+}
+
+// PieceHashes info dictionary ke concatenated pieces ko har piece ke 20-byte SHA-1 hash mein tod deta hai
+func (m *TorrentMeta) PieceHashes() [][]byte {
+	pieces := []byte(m.Info.Pieces)
+	hashes := make([][]byte, 0, len(pieces)/sha1.Size)
+	for i := 0; i+sha1.Size <= len(pieces); i += sha1.Size {
+		hashes = append(hashes, pieces[i:i+sha1.Size])
+	}
+	return hashes
+}
 
 // CreateTorrentFile function di gayi file ke liye ek .torrent file banata hai.
-// Yeh file ka metadata (naam, size, hash) collect karta hai aur use bencode format mein save karta hai.
-func CreateTorrentFile(filename string) error {
+// Yeh file ka metadata (naam, size, hash, piece hashes) collect karta hai aur use bencode format mein save karta hai.
+// cfg ke announce, comment aur created by keys bhi file mein jaate hai.
+func CreateTorrentFile(filename string, cfg TorrentConfig) (*TorrentMeta, error) {
+	return CreateTorrentFileWithPieceLength(filename, DefaultPieceLength, cfg)
+}
+
+// CreateTorrentFileWithPieceLength CreateTorrentFile jaisa hi hai, bas piece size caller deta hai (<= 0 ho toh default)
+func CreateTorrentFileWithPieceLength(filename string, pieceLength int64, cfg TorrentConfig) (*TorrentMeta, error) {
+	meta, err := NewTorrentMeta(filename, pieceLength)
+	if err != nil {
+		return nil, err
+	}
+	cfg.apply(meta)
+	if err := WriteTorrentFile(meta, filename+".torrent"); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// NewTorrentMeta file ka metadata aur piece hashes calculate karta hai, lekin .torrent file disk par nahi likhta
+func NewTorrentMeta(filename string, pieceLength int64) (*TorrentMeta, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer file.Close()
 
 	//file ka data fetch kara hai yha
 	info, err := file.Stat()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return NewTorrentMetaFromReader(file, info.Name(), info.Size(), pieceLength)
+}
+
+// NewTorrentMetaFromReader NewTorrentMeta jaisa hai, bas data r se aata hai; name aur size file ke naam aur size hai.
+// r se size se kam ya zyada bytes aaye toh error aata hai.
+func NewTorrentMetaFromReader(r io.Reader, name string, size int64, pieceLength int64) (*TorrentMeta, error) {
+	if pieceLength <= 0 {
+		pieceLength = DefaultPieceLength
 	}
 
+	// ek hi pass mein poori file ka SHA-256 aur har piece ka SHA-1 calculate karte hai
 	hashCalc := sha256.New()
-	if _, err = io.Copy(hashCalc, file); err != nil {
-		return err
+	var pieces []byte
+	var total int64
+	buf := make([]byte, pieceLength)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			hashCalc.Write(buf[:n])
+			pieceHash := sha1.Sum(buf[:n])
+			pieces = append(pieces, pieceHash[:]...)
+			total += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if total != size {
+		return nil, fmt.Errorf("read %d bytes of %s, expected %d", total, name, size)
 	}
 	//hexadecimal string mein convert kardiya hash ko
 	hexHash := hex.EncodeToString(hashCalc.Sum(nil))
 
-	meta := TorrentMeta{
-		Filename:  info.Name(),
-		Length:    info.Size(),
+	meta := &TorrentMeta{
+		Filename:  name,
+		Length:    size,
 		Hash:      hexHash,
 		CreatedAt: time.Now().Unix(),
+		Info: TorrentInfo{
+			Name:        name,
+			Length:      size,
+			PieceLength: pieceLength,
+			Pieces:      string(pieces),
+		},
 	}
+	return meta, nil
+}
 
-	outputName := filename + ".torrent"
-	out, err := os.Create(outputName)
+// CreateTorrentFileFromReader r ke data se metadata banata hai aur bencoded .torrent w mein likhta hai.
+// Caller r ko io.TeeReader se wrap karke usi pass mein file ka apna hash bhi nikal sakta hai.
+func CreateTorrentFileFromReader(r io.Reader, name string, size int64, pieceLength int64, cfg TorrentConfig, w io.Writer) (*TorrentMeta, error) {
+	meta, err := NewTorrentMetaFromReader(r, name, size, pieceLength)
+	if err != nil {
+		return nil, err
+	}
+	cfg.apply(meta)
+	if err := bencode.Marshal(w, *meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// WriteTorrentFile metadata ko bencode format mein path par likhta hai
+func WriteTorrentFile(meta *TorrentMeta, path string) error {
+	out, err := os.Create(path)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
 	// Metadata struct ko bencode format mein encode karke output file mein likhte hain.
-	return bencode.Marshal(out, meta)
-}
\ No newline at end of file
+	if err := bencode.Marshal(out, *meta); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ParseTorrentFile path par rakhi .torrent file ko padh kar uska metadata decode karta hai
+func ParseTorrentFile(path string) (*TorrentMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var meta TorrentMeta
+	if err := BencodeUnmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
@@ -0,0 +1,37 @@
+package torrentfile
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestPieceCount(t *testing.T) {
+	const pieceLength = DefaultPieceLength
+	tests := []struct {
+		name        string
+		filesize    int64
+		pieceLength int64
+		want        int64
+	}{
+		{name: "empty file", filesize: 0, pieceLength: pieceLength, want: 0},
+		{name: "one full piece", filesize: pieceLength, pieceLength: pieceLength, want: 1},
+		{name: "one byte over", filesize: pieceLength + 1, pieceLength: pieceLength, want: 2},
+		{name: "one byte short", filesize: pieceLength - 1, pieceLength: pieceLength, want: 1},
+		// filesize + pieceLength - 1 yahan overflow karta; remainder wala round up sahi ginti deta hai
+		{name: "max int64", filesize: math.MaxInt64, pieceLength: pieceLength, want: math.MaxInt64/pieceLength + 1},
+		{name: "max int64 one-byte pieces", filesize: math.MaxInt64, pieceLength: 1, want: math.MaxInt64},
+		{name: "zero piece length", filesize: 10, pieceLength: 0, want: 0},
+		{name: "negative size", filesize: -1, pieceLength: pieceLength, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want > math.MaxInt32 && strconv.IntSize < 64 {
+				t.Skip("piece count does not fit in a 32-bit int")
+			}
+			if got := PieceCount(tt.filesize, tt.pieceLength); int64(got) != tt.want {
+				t.Errorf("PieceCount(%d, %d) = %d, want %d", tt.filesize, tt.pieceLength, got, tt.want)
+			}
+		})
+	}
+}
@@ -1 +1,50 @@
 package torrentfile
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"io"
+	"os"
+)
+
+// VerifyPieces disk par file ko PieceLength ke chunks mein padhkar har piece ka SHA-1 meta ke piece hashes se
+// compare karta hai, aur jin pieces ka hash match nahi karta unke indices return karta hai.
+// File metadata se chhoti ho toh bache hue pieces bhi failed maane jaate hai.
+func VerifyPieces(filename string, meta *TorrentMeta) ([]int, error) {
+	return VerifyPiecesSkipping(filename, meta, nil)
+}
+
+// VerifyPiecesSkipping VerifyPieces jaisa hai, bas skip wale pieces (jaise pichli baar verify ho chuke) disk se
+// padhe hi nahi jaate aur hamesha sahi maane jaate hai.
+func VerifyPiecesSkipping(filename string, meta *TorrentMeta, skip []int) ([]int, error) {
+	if meta.Info.PieceLength <= 0 {
+		return nil, errors.New("torrent metadata has no piece length")
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hashes := meta.PieceHashes()
+	skipped := make(map[int]bool, len(skip))
+	for _, idx := range skip {
+		skipped[idx] = true
+	}
+	var failed []int
+	buf := make([]byte, meta.Info.PieceLength)
+	for idx, want := range hashes {
+		if skipped[idx] {
+			continue
+		}
+		n, err := io.ReadFull(io.NewSectionReader(file, int64(idx)*meta.Info.PieceLength, meta.Info.PieceLength), buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, err
+		}
+		if sum := sha1.Sum(buf[:n]); n == 0 || !bytes.Equal(sum[:], want) {
+			failed = append(failed, idx)
+		}
+	}
+	return failed, nil
+}
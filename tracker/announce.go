@@ -0,0 +1,55 @@
+package tracker
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// ErrInvalidSignature tab aata hai jab announcement ka signature peer ki public key se verify nahi hota
+var ErrInvalidSignature = errors.New("invalid announcement signature")
+
+// AnnouncementDigest woh bytes hai jin par peer sign karta hai: SHA256(file_hash + peer_id)
+func AnnouncementDigest(fileHash, peerID string) []byte {
+	sum := sha256.Sum256([]byte(fileHash + peerID))
+	return sum[:]
+}
+
+// SignAnnouncement peer ki libp2p private key se file announcement sign karta hai
+func SignAnnouncement(priv crypto.PrivKey, fileHash, peerID string) ([]byte, error) {
+	if priv == nil {
+		return nil, errors.New("no private key to sign with")
+	}
+	return priv.Sign(AnnouncementDigest(fileHash, peerID))
+}
+
+// VerifyAnnouncementSignature peer ID se public key nikal kar signature check karta hai.
+// Ed25519/secp256k1 peer IDs mein public key embedded hoti hai; RSA jaise keys ke liye error aata hai.
+func VerifyAnnouncementSignature(fileHash, peerID string, sig []byte) (bool, error) {
+	if len(sig) == 0 {
+		return false, nil
+	}
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return false, fmt.Errorf("invalid peer ID %q: %w", peerID, err)
+	}
+	pub, err := id.ExtractPublicKey()
+	if err != nil {
+		return false, fmt.Errorf("no public key in peer ID %s: %w", peerID, err)
+	}
+	return pub.Verify(AnnouncementDigest(fileHash, peerID), sig)
+}
+
+// VerifyFileAnnouncement database mein stored signature se check karta hai ki peer ne yeh file sach mein announce ki thi.
+// Bina signature wale (purane clients ke) announcements false hote hai.
+func (t *Tracker) VerifyFileAnnouncement(ctx context.Context, fileHash, peerID string) (bool, error) {
+	sig, err := t.repo.GetAnnouncementSignature(ctx, fileHash, peerID)
+	if err != nil {
+		return false, err
+	}
+	return VerifyAnnouncementSignature(fileHash, peerID, sig)
+}
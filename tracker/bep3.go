@@ -0,0 +1,167 @@
+package tracker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"torrentium/torrentfile"
+)
+
+// BEP 3 announce ke event values; normal periodic announce mein event khaali hota hai
+const (
+	AnnounceStarted   = "started"
+	AnnounceStopped   = "stopped"
+	AnnounceCompleted = "completed"
+)
+
+// peerIDPrefix Azureus-style client ID hai jo BitTorrent peer ID ke shuru mein jaata hai ("-TM" Torrentium, version 0100)
+const peerIDPrefix = "-TM0100-"
+
+// announceTimeout tak BitTorrent tracker jawab na de toh announce fail
+const announceTimeout = 15 * time.Second
+
+// maxAnnounceResponse tracker response ki upper limit hai (compact peers ke saath kuch KB hi hota hai)
+const maxAnnounceResponse = 1 << 20
+
+// ErrTrackerFailure tab aata hai jab BitTorrent tracker "failure reason" ke saath jawab deta hai
+var ErrTrackerFailure = errors.New("tracker returned a failure")
+
+// NewPeerID ek naya BitTorrent peer ID banata hai: peerIDPrefix ke baad random bytes
+func NewPeerID() ([20]byte, error) {
+	var id [20]byte
+	copy(id[:], peerIDPrefix)
+	if _, err := rand.Read(id[len(peerIDPrefix):]); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// Announce BEP 3 HTTP tracker ko batata hai ki hum infoHash wale torrent ke liye port par peer hai, aur tracker ke
+// diye swarm ke doosre peers ke TCP addresses return karta hai. event "started", "stopped", "completed" ya khaali ho
+// sakta hai; uploaded, downloaded aur left bytes mein hai. Compact aur dictionary dono peer lists samajhta hai.
+func Announce(ctx context.Context, trackerURL string, infoHash, peerID [20]byte, port int, event string, uploaded, downloaded, left int64) ([]net.Addr, error) {
+	u, err := url.Parse(trackerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracker URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported tracker scheme %q (only http and https)", u.Scheme)
+	}
+	// info_hash aur peer_id raw bytes hai, url.Values unhe form-encode (space ko "+") kar deta, isliye haath se jodte hai
+	query := []string{
+		"info_hash=" + escapeBytes(infoHash[:]),
+		"peer_id=" + escapeBytes(peerID[:]),
+		"port=" + strconv.Itoa(port),
+		"uploaded=" + strconv.FormatInt(uploaded, 10),
+		"downloaded=" + strconv.FormatInt(downloaded, 10),
+		"left=" + strconv.FormatInt(left, 10),
+		"compact=1",
+	}
+	if event != "" {
+		query = append(query, "event="+url.QueryEscape(event))
+	}
+	if u.RawQuery != "" {
+		// private trackers URL mein passkey jaise params rakhte hai
+		query = append([]string{u.RawQuery}, query...)
+	}
+	u.RawQuery = strings.Join(query, "&")
+
+	ctx, cancel := context.WithTimeout(ctx, announceTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("announce failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("announce failed: tracker returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAnnounceResponse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read announce response: %w", err)
+	}
+	return parseAnnounceResponse(body)
+}
+
+// bencoded announce response se peers nikalta hai
+func parseAnnounceResponse(body []byte) ([]net.Addr, error) {
+	decoded, err := torrentfile.BencodeDecode(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid announce response: %w", err)
+	}
+	dict, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid announce response: not a dictionary")
+	}
+	if reason, ok := dict["failure reason"].(string); ok {
+		return nil, fmt.Errorf("%w: %s", ErrTrackerFailure, reason)
+	}
+
+	switch peers := dict["peers"].(type) {
+	case string:
+		// compact (BEP 23): har peer 4 byte IPv4 + 2 byte port, big-endian
+		if len(peers)%6 != 0 {
+			return nil, fmt.Errorf("invalid compact peer list of %d bytes", len(peers))
+		}
+		addrs := make([]net.Addr, 0, len(peers)/6)
+		for i := 0; i < len(peers); i += 6 {
+			ip := net.IPv4(peers[i], peers[i+1], peers[i+2], peers[i+3])
+			port := binary.BigEndian.Uint16([]byte(peers[i+4 : i+6]))
+			addrs = append(addrs, &net.TCPAddr{IP: ip, Port: int(port)})
+		}
+		return addrs, nil
+	case []interface{}:
+		// original BEP 3 format: {"peer id", "ip", "port"} dictionaries ki list; "ip" hostname bhi ho sakta hai
+		var addrs []net.Addr
+		for _, p := range peers {
+			entry, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			host, _ := entry["ip"].(string)
+			port, _ := entry["port"].(int64)
+			if host == "" || port <= 0 || port > 65535 {
+				continue
+			}
+			addr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(host, strconv.FormatInt(port, 10)))
+			if err != nil {
+				logger.Debug("Skipping unresolvable tracker peer", "host", host, "error", err)
+				continue
+			}
+			addrs = append(addrs, addr)
+		}
+		return addrs, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid peer list of type %T", peers)
+	}
+}
+
+// raw bytes ko URL ke liye percent-encode karta hai, sirf unreserved characters (RFC 3986) waise hi rehte hai
+func escapeBytes(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// HandshakeNonceSize tracker ke har connection par bheje challenge ke random bytes
+const HandshakeNonceSize = 32
+
+// handshake digest mein yeh label pehle aata hai, taaki handshake ka signature kisi aur protocol (jaise tcptransfer
+// ke GET) ke signature ki jagah use na ho sake; peer dono ek hi libp2p key se sign karta hai
+const handshakeLabel = "torrentium/tracker-handshake/v1"
+
+// ErrInvalidHandshake tab aata hai jab HANDSHAKE ka signature peer ID ki public key se verify nahi hota
+var ErrInvalidHandshake = errors.New("invalid handshake signature")
+
+// NewHandshakeNonce ek naye connection ke liye random challenge banata hai
+func NewHandshakeNonce() ([]byte, error) {
+	nonce := make([]byte, HandshakeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// HandshakeDigest woh bytes hai jin par peer sign karta hai: SHA256(label + nonce + peer_id).
+// Nonce har connection ka naya hai, isliye pakda gaya signature doosre connection par kaam nahi aata.
+func HandshakeDigest(nonce []byte, peerID string) []byte {
+	sum := sha256.Sum256(append(append([]byte(handshakeLabel), nonce...), peerID...))
+	return sum[:]
+}
+
+// SignHandshake peer ki libp2p private key se tracker ka nonce sign karta hai
+func SignHandshake(priv crypto.PrivKey, nonce []byte, peerID string) ([]byte, error) {
+	if priv == nil {
+		return nil, errors.New("no private key to sign with")
+	}
+	return priv.Sign(HandshakeDigest(nonce, peerID))
+}
+
+// VerifyHandshake check karta hai ki sig peerID ki key se nonce par bana hai, yaani connection us key ka maalik hai.
+// Public key peer ID se nikalti hai (Ed25519/secp256k1); RSA jaise peer IDs ke liye error aata hai.
+func VerifyHandshake(nonce []byte, peerID string, sig []byte) error {
+	if len(nonce) != HandshakeNonceSize || len(sig) == 0 {
+		return ErrInvalidHandshake
+	}
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID %q: %w", peerID, err)
+	}
+	pub, err := id.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("no public key in peer ID %s: %w", peerID, err)
+	}
+	if ok, err := pub.Verify(HandshakeDigest(nonce, peerID), sig); err != nil || !ok {
+		return ErrInvalidHandshake
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package tracker
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func newKey(t *testing.T) (crypto.PrivKey, peer.ID) {
+	t.Helper()
+	priv, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, id
+}
+
+func TestVerifyHandshakeRequiresKeyOfPeerID(t *testing.T) {
+	key, id := newKey(t)
+	_, victim := newKey(t)
+	nonce, err := NewHandshakeNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := SignHandshake(key, nonce, id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyHandshake(nonce, id.String(), sig); err != nil {
+		t.Fatalf("VerifyHandshake(valid) = %v", err)
+	}
+	// apni key se kisi aur ka peer ID claim karna
+	forged, err := SignHandshake(key, nonce, victim.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHandshake(nonce, victim.String(), forged); !errors.Is(err, ErrInvalidHandshake) {
+		t.Fatalf("handshake for another peer ID: err = %v, want ErrInvalidHandshake", err)
+	}
+	// dusre connection ka nonce: purana signature kaam nahi aana chahiye
+	other, err := NewHandshakeNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHandshake(other, id.String(), sig); !errors.Is(err, ErrInvalidHandshake) {
+		t.Fatalf("replayed signature: err = %v, want ErrInvalidHandshake", err)
+	}
+	// purane clients bina signature ke handshake karte the
+	if err := VerifyHandshake(nonce, id.String(), nil); !errors.Is(err, ErrInvalidHandshake) {
+		t.Fatalf("unsigned handshake: err = %v, want ErrInvalidHandshake", err)
+	}
+}
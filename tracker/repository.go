@@ -0,0 +1,81 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"torrentium/db"
+	"torrentium/db/memory"
+
+	"github.com/google/uuid"
+)
+
+// Repository woh saare storage operations hai jo tracker ko chahiye. Postgres ke liye *db.Repository,
+// aur bina database ke testing (--no-db) ke liye *memory.Repository.
+type Repository interface {
+	HealthCheck(ctx context.Context) error
+
+	// peers
+	UpsertPeer(ctx context.Context, peerID, name string, multiaddrs []string) (uuid.UUID, error)
+	FindPeersByIDs(ctx context.Context, peerIDs []string) ([]db.Peer, error)
+	FindOnlinePeers(ctx context.Context) ([]db.Peer, error)
+	UpdatePeerIPs(ctx context.Context, peerID, ipv4, ipv6 string) error
+	UpdatePeerTCPPort(ctx context.Context, peerID string, port int) error
+	SetPeerOffline(ctx context.Context, peerID string) error
+	MarkAllPeersOffline(ctx context.Context) error
+	GetPeerByID(ctx context.Context, peerID string) (*db.Peer, error)
+	GetPeerByIP(ctx context.Context, ip string) (*db.Peer, error)
+	GetPeerInfoByDBID(ctx context.Context, peerDBID uuid.UUID) (*db.Peer, error)
+
+	// files aur announcements
+	InsertFile(ctx context.Context, fileHash, filename string, fileSize int64, contentType string) (uuid.UUID, error)
+	SetFileInfoHash(ctx context.Context, fileID uuid.UUID, infoHash string) error
+	GetFileByID(ctx context.Context, fileID uuid.UUID) (*db.File, error)
+	GetFileByHash(ctx context.Context, fileHash string) (*db.File, error)
+	GetFileByName(ctx context.Context, filename string) (*db.File, error)
+	IsFileAnnouncedBy(ctx context.Context, fileID uuid.UUID, peerLibp2pID string) (bool, error)
+	FindAllFiles(ctx context.Context) ([]db.File, error)
+	ListAllFiles(ctx context.Context) ([]db.CatalogEntry, error)
+	ListFilesPage(ctx context.Context, offset, limit int) ([]db.File, int, error)
+	GetFilesByPeer(ctx context.Context, peerLibp2pID string) ([]db.File, error)
+	InsertPeerFile(ctx context.Context, peerLibp2pID string, fileID uuid.UUID) (uuid.UUID, error)
+	SetAnnouncementSignature(ctx context.Context, fileID uuid.UUID, peerLibp2pID string, sig []byte) error
+	GetAnnouncementSignature(ctx context.Context, fileHash, peerLibp2pID string) ([]byte, error)
+	RemoveFile(ctx context.Context, fileHash, peerLibp2pID string) (int, error)
+	CleanupOrphanedFiles(ctx context.Context, grace time.Duration) (int64, error)
+	GetFilesNeedingReseed(ctx context.Context, threshold time.Duration) ([]db.AtRiskFile, error)
+	FindOnlineFilePeersByID(ctx context.Context, fileID uuid.UUID) ([]db.PeerFile, error)
+
+	// tags
+	AddTag(ctx context.Context, fileHash, tag string) error
+	RemoveTag(ctx context.Context, fileHash, tag string) error
+	SearchByTag(ctx context.Context, tag string) ([]db.File, error)
+
+	// bans aur access control
+	BanPeer(ctx context.Context, bannedBy, peerID, reason string, duration time.Duration) error
+	UnbanPeer(ctx context.Context, bannedBy, peerID string) error
+	ListBans(ctx context.Context, bannedBy string) ([]db.PeerBan, error)
+	AllowPeer(ctx context.Context, fileHash, peerID string) error
+	DenyPeer(ctx context.Context, fileHash, peerID string) error
+	IsAllowed(ctx context.Context, fileHash, peerID string) (bool, error)
+
+	// stats, pieces aur audit events
+	AddBytesUploaded(ctx context.Context, peerLibp2pID string, n int64) error
+	GetTopSeeders(ctx context.Context, limit int) ([]db.SeederRecord, error)
+	StatsSnapshot(ctx context.Context) (*db.NetworkStats, error)
+	SetFilePieces(ctx context.Context, fileHash string, pieces []db.FilePiece) error
+	GetFilePiece(ctx context.Context, fileHash string, pieceIndex int) (*db.FilePiece, error)
+	GetPieceHash(ctx context.Context, fileHash string, pieceIndex int) ([]byte, error)
+	GetMissingPieces(ctx context.Context, fileHash string, have []int) ([]int, error)
+	GetVerifiedPieces(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time) ([]int, error)
+	MarkPiecesVerified(ctx context.Context, peerLibp2pID, fileHash string, modTime time.Time, pieces []int) error
+	AddGossipFiles(ctx context.Context, peerLibp2pID string, files []db.GossipFile, limit int) (int, error)
+	ListGossipFiles(ctx context.Context, peerLibp2pID string) ([]db.GossipFile, error)
+	RecordPeerEvent(ctx context.Context, peerID, eventType string, metadata interface{}) error
+	GetPeerHistory(ctx context.Context, peerID string, limit int) ([]db.PeerHistoryRecord, error)
+}
+
+var (
+	_ Repository = (*db.Repository)(nil)
+	_ Repository = (*memory.Repository)(nil)
+)
@@ -2,44 +2,58 @@ package tracker
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"sync"
+	"time"
 	"torrentium/db"
+	"torrentium/logging"
 
 	"github.com/google/uuid"
 )
 
+// tracker package ka logger
+var logger = logging.For("tracker")
+
 type Tracker struct {
 	peers    map[string]bool // (In-memory map )jo currently connected peers hai unke IDs ko store karta hai.
-	repo     *db.Repository
+	repo     Repository
 	peersMux sync.RWMutex // peers map ko concurrency clashes se bachane ke liye reead and write Mutex.
 }
 
 // ek naya tracker instance initialize karte hai
-func NewTracker() *Tracker {
+func NewTracker(repo Repository) *Tracker {
 	return &Tracker{
 		peers: make(map[string]bool),
-		repo:  db.NewRepository(db.DB),
+		repo:  repo,
 	}
 }
 
 // Yeh peer ko in-memory list mein aur database mein (upsert) add karta hai.
-func (t *Tracker) AddPeer(peerID, name, ip string) error {
+// ipv4 aur ipv6 peer ke local addresses hai, dono mein se koi bhi empty ho sakta hai. tcpPort peer ka
+// TCP fallback port hai, 0 ho toh peer TCP par files serve nahi karta.
+func (t *Tracker) AddPeer(ctx context.Context, peerID, name, ipv4, ipv6 string, tcpPort int) error {
 	// Map ko lock karte hain taaki race conditions na ho.
 	t.peersMux.Lock()
 	t.peers[peerID] = true
 	t.peersMux.Unlock()
 
-	ctx := context.Background()
 	// For WebSocket connections, we don't have multiaddrs, so pass empty slice
 	// UpsertPeer already sets is_online = true for both new and existing peers
 	_, err := t.repo.UpsertPeer(ctx, peerID, name, []string{})
 	if err != nil {
-		log.Printf("Failed to upsert peer %s: %v", peerID, err)
+		logger.Error("Failed to upsert peer", "peer", peerID, "error", err)
 		return err
 	}
 
-	log.Printf("Peer %s (%s) added and set online", name, peerID)
+	if err := t.repo.UpdatePeerIPs(ctx, peerID, ipv4, ipv6); err != nil {
+		logger.Warn("Failed to store IP addresses for peer", "peer", peerID, "error", err)
+	}
+	if err := t.repo.UpdatePeerTCPPort(ctx, peerID, tcpPort); err != nil {
+		logger.Warn("Failed to store TCP port for peer", "peer", peerID, "error", err)
+	}
+
+	logger.Info("Peer added and set online", "name", name, "peer", peerID)
+	t.recordEvent(ctx, peerID, db.EventPeerConnected, map[string]string{"name": name, "ipv4": ipv4, "ipv6": ipv6})
 	return nil
 }
 
@@ -60,22 +74,22 @@ func (t *Tracker) AddPeerWithContext(ctx context.Context, peerID, name string, m
 
 	_, err := t.repo.UpsertPeer(ctx, peerID, name, multiaddrs)
 	if err != nil {
-		log.Printf("Failed to upsert peer %s: %v", peerID, err)
+		logger.Error("Failed to upsert peer", "peer", peerID, "error", err)
 		return err
 	}
 	return nil
 }
 
 // Yeh use in-memory list se delete karta hai aur database mein offline mark karta hai.
-func (t *Tracker) RemovePeer(peerID string) {
+func (t *Tracker) RemovePeer(ctx context.Context, peerID string) {
 	t.peersMux.Lock()
 	delete(t.peers, peerID)
 	t.peersMux.Unlock()
 
-	ctx := context.Background()
 	if err := t.repo.SetPeerOffline(ctx, peerID); err != nil {
-		log.Printf("Failed to set peer %s offline in DB: %v", peerID, err)
+		logger.Error("Failed to set peer offline in DB", "peer", peerID, "error", err)
 	}
+	t.recordEvent(ctx, peerID, db.EventPeerDisconnected, nil)
 }
 
 // Yeh use in-memory list se delete karta hai aur database mein offline mark karta hai. (Context version)
@@ -85,10 +99,22 @@ func (t *Tracker) RemovePeerWithContext(ctx context.Context, peerID string) {
 	t.peersMux.Unlock()
 
 	if err := t.repo.SetPeerOffline(ctx, peerID); err != nil {
-		log.Printf("Failed to set peer %s offline in DB: %v", peerID, err)
+		logger.Error("Failed to set peer offline in DB", "peer", peerID, "error", err)
 	}
 }
 
+// MarkPeerOffline dusre peer ki report par peer ko database mein offline mark karta hai.
+// Jo peer abhi tracker se WebSocket par connected hai use offline nahi maana jaata, tab false return hota hai.
+func (t *Tracker) MarkPeerOffline(ctx context.Context, peerID string) (bool, error) {
+	if t.IsPeerConnected(peerID) {
+		return false, nil
+	}
+	if err := t.repo.SetPeerOffline(ctx, peerID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // ListPeers in-memory mein store kiye gaye sabhi online peers ke IDs ki list return karta hai.
 func (t *Tracker) ListPeers() []string {
 	t.peersMux.RLock()
@@ -129,64 +155,293 @@ func (t *Tracker) GetAllFiles(ctx context.Context) ([]db.File, error) {
 	return t.repo.FindAllFiles(ctx)
 }
 
+// GetFilesByPeer ek specific peer dwara announce ki gayi files database se fetch karta hai.
+func (t *Tracker) GetFilesByPeer(ctx context.Context, peerID string) ([]db.File, error) {
+	return t.repo.GetFilesByPeer(ctx, peerID)
+}
+
 // AddFileWithPeer ek file ko database mein add karta hai aur use ek peer ke saath link kar deta hai.
-func (t *Tracker) AddFileWithPeer(ctx context.Context, fileHash, filename string, fileSize int64, peerID string) (uuid.UUID, error) {
+// infoHash BitTorrent info-hash (hex) hai, empty ho toh store nahi hota.
+// signature peer ka SignAnnouncement wala signature hai; diya ho aur verify na ho toh ErrInvalidSignature aata hai.
+func (t *Tracker) AddFileWithPeer(ctx context.Context, fileHash, infoHash, filename string, fileSize int64, peerID string, signature []byte) (uuid.UUID, error) {
+	if len(signature) > 0 {
+		ok, err := VerifyAnnouncementSignature(fileHash, peerID, signature)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		if !ok {
+			return uuid.Nil, ErrInvalidSignature
+		}
+	}
+
 	// Pehle file ko `files` table mein insert karte hain (ya agar exist karti hai to ID get karte hain).
 	fileID, err := t.repo.InsertFile(ctx, fileHash, filename, fileSize, "")
 	if err != nil {
 		return uuid.Nil, err
 	}
+	if infoHash != "" {
+		if err := t.repo.SetFileInfoHash(ctx, fileID, infoHash); err != nil {
+			return uuid.Nil, err
+		}
+	}
 	// Fir `peer_files` table mein entry banakar file aur peer ko link karte hain.
 	_, err = t.repo.InsertPeerFile(ctx, peerID, fileID)
 	if err != nil {
 		return uuid.Nil, err
 	}
+	// dobara announce hone par purana signature bhi replace hota hai (nil ho toh hat jaata hai)
+	if err := t.repo.SetAnnouncementSignature(ctx, fileID, peerID, signature); err != nil {
+		return uuid.Nil, err
+	}
 
 	return fileID, nil
 }
 
+// SetFilePieces file ke piece hashes file_pieces table mein store karta hai. Har piece pieceLength ka hota hai, sirf last chhota ho sakta hai.
+func (t *Tracker) SetFilePieces(ctx context.Context, fileHash string, fileSize, pieceLength int64, hashes [][]byte) error {
+	pieces := make([]db.FilePiece, len(hashes))
+	for i, h := range hashes {
+		length := pieceLength
+		if rest := fileSize - int64(i)*pieceLength; rest < length {
+			length = rest
+		}
+		pieces[i] = db.FilePiece{FileHash: fileHash, PieceIndex: i, PieceHash: h, Length: length}
+	}
+	return t.repo.SetFilePieces(ctx, fileHash, pieces)
+}
+
+// GetFileByID database se file ki info uske ID se fetch karta hai.
+func (t *Tracker) GetFileByID(ctx context.Context, fileID uuid.UUID) (*db.File, error) {
+	return t.repo.GetFileByID(ctx, fileID)
+}
+
+// GetPieceLength file ka piece size return karta hai (pehle piece ki length), pieces index na ho toh 0.
+func (t *Tracker) GetPieceLength(ctx context.Context, fileHash string) int64 {
+	piece, err := t.repo.GetFilePiece(ctx, fileHash, 0)
+	if err != nil {
+		return 0
+	}
+	return piece.Length
+}
+
+// GetPieceHash file ke ek piece ka SHA-1 hash return karta hai.
+func (t *Tracker) GetPieceHash(ctx context.Context, fileHash string, pieceIndex int) ([]byte, error) {
+	return t.repo.GetPieceHash(ctx, fileHash, pieceIndex)
+}
+
+// GetMissingPieces file ke woh pieces return karta hai jo have mein nahi hai.
+func (t *Tracker) GetMissingPieces(ctx context.Context, fileHash string, have []int) ([]int, error) {
+	return t.repo.GetMissingPieces(ctx, fileHash, have)
+}
+
+// GetVerifiedPieces peer ke pehle verify kiye pieces return karta hai; file ka modTime badla ho toh cache khaali hota hai.
+func (t *Tracker) GetVerifiedPieces(ctx context.Context, peerID, fileHash string, modTime time.Time) ([]int, error) {
+	return t.repo.GetVerifiedPieces(ctx, peerID, fileHash, modTime)
+}
+
+// MarkPiecesVerified peer ke verify kiye pieces file ke modTime ke saath cache karta hai.
+func (t *Tracker) MarkPiecesVerified(ctx context.Context, peerID, fileHash string, modTime time.Time, pieces []int) error {
+	return t.repo.MarkPiecesVerified(ctx, peerID, fileHash, modTime, pieces)
+}
+
+// ek peer ke liye gossip_files mein zyada se zyada itni files rehti hai, taaki gossip bhejne wale peers database na bhar de
+const MaxGossipFilesPerPeer = 1000
+
+// AddGossipFiles peer ko gossip se pata chali files save karta hai, MaxGossipFilesPerPeer tak. Nayi saved files ki ginti return hoti hai.
+func (t *Tracker) AddGossipFiles(ctx context.Context, peerID string, files []db.GossipFile) (int, error) {
+	return t.repo.AddGossipFiles(ctx, peerID, files, MaxGossipFilesPerPeer)
+}
+
+// ListGossipFiles peer ko gossip se pata chali saari files return karta hai.
+func (t *Tracker) ListGossipFiles(ctx context.Context, peerID string) ([]db.GossipFile, error) {
+	return t.repo.ListGossipFiles(ctx, peerID)
+}
+
+// RemoveFile peer ka file announcement database se hata deta hai aur batata hai ki kitne dusre peers abhi bhi file announce kar rahe hai.
+func (t *Tracker) RemoveFile(ctx context.Context, fileHash, peerID string) (int, error) {
+	remaining, err := t.repo.RemoveFile(ctx, fileHash, peerID)
+	if err != nil {
+		return 0, err
+	}
+	t.recordEvent(ctx, peerID, db.EventFileRemoved, map[string]interface{}{"file_hash": fileHash, "remaining_peers": remaining})
+	return remaining, nil
+}
+
+// CleanupOrphanedFiles woh files hata deta hai jinka koi announcing peer online nahi hai aur grace se zyada der se
+// dikha bhi nahi, aur deleted files ki ginti return karta hai.
+func (t *Tracker) CleanupOrphanedFiles(ctx context.Context, grace time.Duration) (int64, error) {
+	return t.repo.CleanupOrphanedFiles(ctx, grace)
+}
+
+// GetFilesNeedingReseed woh files deta hai jinke saare seeders threshold se zyada der se offline hai
+func (t *Tracker) GetFilesNeedingReseed(ctx context.Context, threshold time.Duration) ([]db.AtRiskFile, error) {
+	return t.repo.GetFilesNeedingReseed(ctx, threshold)
+}
+
+// BanPeer bannedBy ki ban list mein peerID ko duration ke liye daalta hai (duration <= 0 ho toh permanent).
+func (t *Tracker) BanPeer(ctx context.Context, bannedBy, peerID, reason string, duration time.Duration) error {
+	return t.repo.BanPeer(ctx, bannedBy, peerID, reason, duration)
+}
+
+// UnbanPeer bannedBy ki ban list se peerID hata deta hai.
+func (t *Tracker) UnbanPeer(ctx context.Context, bannedBy, peerID string) error {
+	return t.repo.UnbanPeer(ctx, bannedBy, peerID)
+}
+
+// ListBans bannedBy ke saare active bans return karta hai.
+func (t *Tracker) ListBans(ctx context.Context, bannedBy string) ([]db.PeerBan, error) {
+	return t.repo.ListBans(ctx, bannedBy)
+}
+
+// AllowPeer peerID ko fileHash ki allow list mein daalta hai.
+func (t *Tracker) AllowPeer(ctx context.Context, fileHash, peerID string) error {
+	return t.repo.AllowPeer(ctx, fileHash, peerID)
+}
+
+// DenyPeer peerID ko fileHash ki allow list se hata deta hai.
+func (t *Tracker) DenyPeer(ctx context.Context, fileHash, peerID string) error {
+	return t.repo.DenyPeer(ctx, fileHash, peerID)
+}
+
+// IsAllowed batata hai ki peerID fileHash download kar sakta hai ya nahi.
+func (t *Tracker) IsAllowed(ctx context.Context, fileHash, peerID string) (bool, error) {
+	return t.repo.IsAllowed(ctx, fileHash, peerID)
+}
+
+// AddTag file par ek category tag lagata hai.
+func (t *Tracker) AddTag(ctx context.Context, fileHash, tag string) error {
+	return t.repo.AddTag(ctx, fileHash, tag)
+}
+
+// RemoveTag file se ek tag hata deta hai.
+func (t *Tracker) RemoveTag(ctx context.Context, fileHash, tag string) error {
+	return t.repo.RemoveTag(ctx, fileHash, tag)
+}
+
+// SearchByTag diye gaye tag wali saari files return karta hai.
+func (t *Tracker) SearchByTag(ctx context.Context, tag string) ([]db.File, error) {
+	return t.repo.SearchByTag(ctx, tag)
+}
+
+// RecordUpload peer ke uploaded bytes ke stats update karta hai.
+func (t *Tracker) RecordUpload(ctx context.Context, peerID string, bytes int64) error {
+	if bytes <= 0 {
+		return nil
+	}
+	return t.repo.AddBytesUploaded(ctx, peerID, bytes)
+}
+
+// StatsSnapshot network ke files, peers aur transferred bytes ke totals return karta hai.
+func (t *Tracker) StatsSnapshot(ctx context.Context) (*db.NetworkStats, error) {
+	return t.repo.StatsSnapshot(ctx)
+}
+
+// GetTopSeeders sabse zyada upload karne wale peers return karta hai.
+func (t *Tracker) GetTopSeeders(ctx context.Context, limit int) ([]db.SeederRecord, error) {
+	return t.repo.GetTopSeeders(ctx, limit)
+}
+
+// PeerHasFile batata hai ki peer is hash wali file pehle se announce kar raha hai ya nahi
+func (t *Tracker) PeerHasFile(ctx context.Context, fileHash, peerID string) (bool, error) {
+	file, err := t.repo.GetFileByHash(ctx, fileHash)
+	if err != nil {
+		return false, err
+	}
+	return t.repo.IsFileAnnouncedBy(ctx, file.ID, peerID)
+}
+
+// GetFileByName database se filename ke basis par file ki info fetch karta hai.
+func (t *Tracker) GetFileByName(ctx context.Context, filename string) (*db.File, error) {
+	return t.repo.GetFileByName(ctx, filename)
+}
+
 // GetPeerInfoByDBID database se ek peer ki info uske db ID ka use karke fetch karta hai.
 func (t *Tracker) GetPeerInfoByDBID(ctx context.Context, peerDBID uuid.UUID) (*db.Peer, error) {
 	return t.repo.GetPeerInfoByDBID(ctx, peerDBID)
 }
 
+// GetPeerByID database se ek peer ki info uske libp2p peer ID ka use karke fetch karta hai.
+func (t *Tracker) GetPeerByID(ctx context.Context, peerID string) (*db.Peer, error) {
+	return t.repo.GetPeerByID(ctx, peerID)
+}
+
+// GetPeerByIP database se ek peer ki info uske IP address ka use karke fetch karta hai.
+func (t *Tracker) GetPeerByIP(ctx context.Context, ip string) (*db.Peer, error) {
+	return t.repo.GetPeerByIP(ctx, ip)
+}
+
 // WebSocket handler wrapper methods
 
 // AnnounceFile WebSocket handler ke liye wrapper method
-func (t *Tracker) AnnounceFile(fileHash, filename string, fileSize int64, peerID string) (uuid.UUID, error) {
-	ctx := context.Background()
-	return t.AddFileWithPeer(ctx, fileHash, filename, fileSize, peerID)
+func (t *Tracker) AnnounceFile(ctx context.Context, fileHash, infoHash, filename string, fileSize int64, peerID string, signature []byte) (uuid.UUID, error) {
+	fileID, err := t.AddFileWithPeer(ctx, fileHash, infoHash, filename, fileSize, peerID, signature)
+	if err != nil {
+		return fileID, err
+	}
+	t.recordEvent(ctx, peerID, db.EventFileAdded, map[string]interface{}{"file_id": fileID, "file_hash": fileHash, "filename": filename, "size": fileSize})
+	return fileID, nil
 }
 
 // ListFiles WebSocket handler ke liye wrapper method
-func (t *Tracker) ListFiles() []db.File {
-	ctx := context.Background()
+func (t *Tracker) ListFiles(ctx context.Context) []db.File {
 	files, err := t.GetAllFiles(ctx)
 	if err != nil {
-		log.Printf("Error getting files: %v", err)
+		logger.Error("Error getting files", "error", err)
 		return []db.File{}
 	}
 	return files
 }
 
+// ListAllFiles export ke liye poora catalog (file + announcing peer rows) return karta hai
+func (t *Tracker) ListAllFiles(ctx context.Context) ([]db.CatalogEntry, error) {
+	return t.repo.ListAllFiles(ctx)
+}
+
+// ListFilesPage 1-based page number aur page size ke hisab se files ka ek page aur total count deta hai
+func (t *Tracker) ListFilesPage(ctx context.Context, page, perPage int) ([]db.File, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	return t.repo.ListFilesPage(ctx, (page-1)*perPage, perPage)
+}
+
 // GetPeersForFile WebSocket handler ke liye wrapper method
-func (t *Tracker) GetPeersForFile(fileID uuid.UUID) []db.PeerFile {
-	ctx := context.Background()
+func (t *Tracker) GetPeersForFile(ctx context.Context, fileID uuid.UUID) []db.PeerFile {
 	peers, err := t.GetOnlinePeersForFile(ctx, fileID)
 	if err != nil {
-		log.Printf("Error getting peers for file: %v", err)
+		logger.Error("Error getting peers for file", "file_id", fileID, "error", err)
 		return []db.PeerFile{}
 	}
 	return peers
 }
 
 // GetPeerInfo WebSocket handler ke liye wrapper method
-func (t *Tracker) GetPeerInfo(peerDBID uuid.UUID) *db.Peer {
-	ctx := context.Background()
+func (t *Tracker) GetPeerInfo(ctx context.Context, peerDBID uuid.UUID) *db.Peer {
 	peer, err := t.GetPeerInfoByDBID(ctx, peerDBID)
 	if err != nil {
-		log.Printf("Error getting peer info: %v", err)
+		logger.Error("Error getting peer info", "peer_db_id", peerDBID, "error", err)
 		return nil
 	}
 	return peer
 }
+
+// RecordTransfer peer ke report kiye direct (WebRTC/QUIC) transfer ka audit event likhta hai
+func (t *Tracker) RecordTransfer(ctx context.Context, peerID string, completed bool, metadata interface{}) error {
+	eventType := db.EventTransferFailed
+	if completed {
+		eventType = db.EventTransferCompleted
+	}
+	return t.repo.RecordPeerEvent(ctx, peerID, eventType, metadata)
+}
+
+// GetPeerHistory peer ke sabse naye limit audit events deta hai
+func (t *Tracker) GetPeerHistory(ctx context.Context, peerID string, limit int) ([]db.PeerHistoryRecord, error) {
+	return t.repo.GetPeerHistory(ctx, peerID, limit)
+}
+
+// audit event likhta hai; fail ho toh sirf log hota hai, asli operation nahi rukta
+func (t *Tracker) recordEvent(ctx context.Context, peerID, eventType string, metadata interface{}) {
+	if err := t.repo.RecordPeerEvent(ctx, peerID, eventType, metadata); err != nil {
+		logger.Warn("Failed to record peer event", "peer", peerID, "event", eventType, "error", err)
+	}
+}
@@ -0,0 +1,10 @@
+package webRTC
+
+// ErrorAccessDenied ERROR message ka prefix hai jab requester file ki allow list mein nahi hai
+const ErrorAccessDenied = "ACCESS_DENIED"
+
+// AccessDeniedMessage requester ko batata hai ki use filename download karne ki permission nahi hai.
+// Wire par {"error": "ACCESS_DENIED:<filename>"} jaata hai, jo receiver par ErrorCommand banta hai.
+func AccessDeniedMessage(filename string) map[string]string {
+	return map[string]string{"error": ErrorAccessDenied + ":" + WireFilename(filename)}
+}
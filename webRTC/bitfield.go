@@ -0,0 +1,34 @@
+package webRTC
+
+// EncodeBitfield pieces ki list ko BitTorrent jaise bitfield mein pack karta hai: piece 0 pehle byte ka sabse bada bit hai
+func EncodeBitfield(have []bool) []byte {
+	bits := make([]byte, (len(have)+7)/8)
+	for i, ok := range have {
+		if ok {
+			bits[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return bits
+}
+
+// DecodeBitfield EncodeBitfield ka ulta hai, pieces batata hai ki bitfield mein kitne pieces hai
+func DecodeBitfield(bits []byte, pieces int) []bool {
+	have := make([]bool, pieces)
+	for i := range have {
+		if i/8 < len(bits) {
+			have[i] = bits[i/8]&(0x80>>(i%8)) != 0
+		}
+	}
+	return have
+}
+
+// SendBitfield remote peer ko batata hai ki file ke kaunse pieces hamare paas hai.
+// Bitfield JSON mein base64 ho kar jaata hai: {"command":"BITFIELD","file_hash":...,"pieces":n,"bitfield":...}
+func (p *WebRTCPeer) SendBitfield(fileHash string, have []bool) error {
+	return p.Send(map[string]interface{}{
+		"command":   "BITFIELD",
+		"file_hash": fileHash,
+		"pieces":    len(have),
+		"bitfield":  EncodeBitfield(have),
+	})
+}
@@ -0,0 +1,223 @@
+package webRTC
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CommandType data channel (ya QUIC stream) par aaye text message ka type hai
+type CommandType string
+
+const (
+	CommandRequestFile  CommandType = "REQUEST_FILE"
+	CommandRequestRange CommandType = "REQUEST_RANGE"
+	CommandRangeEnd     CommandType = "RANGE_END"
+	CommandFileStart    CommandType = "FILE_START"
+	CommandFileEnd      CommandType = "TRANSFER_COMPLETE"
+	CommandBitfield     CommandType = "BITFIELD"
+	CommandError        CommandType = "ERROR"
+	CommandFileExists   CommandType = "FILE_EXISTS"
+	CommandCancel       CommandType = "CANCEL"
+	CommandNack         CommandType = "NACK"
+	CommandPieceData    CommandType = "PIECE_DATA"
+	CommandPieceEnd     CommandType = "PIECE_END"
+)
+
+// Command ParseCommand ka result hai; caller concrete type par type-switch karta hai
+type Command interface {
+	CommandType() CommandType
+}
+
+// RequestFileCommand remote peer humse ek shared file maang raha hai
+type RequestFileCommand struct {
+	FileID string
+}
+
+// RequestRangeCommand remote peer file ke sirf [Start, End) bytes maang raha hai
+type RequestRangeCommand struct {
+	FileID     string
+	Start, End int64
+}
+
+// FileStartCommand file ke chunks shuru hone se pehle aata hai. Compress "gzip" ho toh Size original (uncompressed) size hai.
+// ContentType ContentTypeTarGzip ho toh file nahi, poori directory ka archive aa raha hai.
+// Offset > 0 ho toh yeh range transfer hai aur bytes file mein Offset se likhne hai.
+type FileStartCommand struct {
+	Filename    string
+	Size        int64
+	Compress    string
+	ContentType string
+	Offset      int64
+}
+
+// RangeEndCommand range transfer ke saare bytes aa chuke hai
+type RangeEndCommand struct {
+	Filename   string
+	Start, End int64
+}
+
+// FileEndCommand file ke saare chunks aa chuke hai. Purane peers filename nahi bhejte, tab Filename empty hota hai.
+type FileEndCommand struct {
+	Filename string
+}
+
+// BitfieldCommand remote peer ke paas ek file ke kaunse pieces hai
+type BitfieldCommand struct {
+	FileHash string
+	Pieces   int
+	Bitfield []byte
+}
+
+// FileExistsCommand uploader ke hisab se humne yeh file pehle se announce ki hai, isliye data nahi aayega
+type FileExistsCommand struct {
+	FileHash string
+	Filename string
+}
+
+// CancelCommand receiver ne filename ka transfer Reason (jaise INSUFFICIENT_SPACE) ki wajah se cancel kar diya
+type CancelCommand struct {
+	Filename string
+	Reason   string
+}
+
+// NackCommand receiver ko FileHash ke Piece ka hash mismatch mila, woh piece dobara chahiye.
+// Piece ka offset Piece * PieceLength hai.
+type NackCommand struct {
+	FileHash    string
+	Filename    string
+	Piece       int
+	PieceLength int64
+}
+
+// PieceDataCommand NACK kiye gaye piece ke chunks shuru hone se pehle aata hai; bytes file mein Offset se likhne hai
+type PieceDataCommand struct {
+	FileHash string
+	Filename string
+	Piece    int
+	Offset   int64
+	Size     int64
+}
+
+// PieceEndCommand retransmit kiye gaye piece ke saare bytes aa chuke hai
+type PieceEndCommand struct {
+	FileHash string
+	Filename string
+	Piece    int
+}
+
+// ErrorCommand sender ne transfer ke dauraan error bheja (jaise "File not found")
+type ErrorCommand struct {
+	Message string
+}
+
+// RawCommand woh commands hai jo transfer protocol ke nahi hai (jaise GOSSIP_FILES), Data poora JSON message hai
+type RawCommand struct {
+	Name string
+	Data []byte
+}
+
+func (RequestFileCommand) CommandType() CommandType  { return CommandRequestFile }
+func (RequestRangeCommand) CommandType() CommandType { return CommandRequestRange }
+func (FileStartCommand) CommandType() CommandType    { return CommandFileStart }
+func (RangeEndCommand) CommandType() CommandType     { return CommandRangeEnd }
+func (FileEndCommand) CommandType() CommandType      { return CommandFileEnd }
+func (BitfieldCommand) CommandType() CommandType     { return CommandBitfield }
+func (FileExistsCommand) CommandType() CommandType   { return CommandFileExists }
+func (CancelCommand) CommandType() CommandType       { return CommandCancel }
+func (NackCommand) CommandType() CommandType         { return CommandNack }
+func (PieceDataCommand) CommandType() CommandType    { return CommandPieceData }
+func (PieceEndCommand) CommandType() CommandType     { return CommandPieceEnd }
+func (ErrorCommand) CommandType() CommandType        { return CommandError }
+func (c RawCommand) CommandType() CommandType        { return CommandType(c.Name) }
+
+// wire format: {"command":...} ya {"status":"TRANSFER_COMPLETE"} ya {"error":...}
+type wireCommand struct {
+	Command     string `json:"command"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	FileID      string `json:"file_id"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	Compress    string `json:"compress"`
+	Offset      int64  `json:"offset"`
+	Start       int64  `json:"start"`
+	End         int64  `json:"end"`
+	ContentType string `json:"content_type"`
+	FileHash    string `json:"file_hash"`
+	Pieces      int    `json:"pieces"`
+	Bitfield    []byte `json:"bitfield"`
+	Reason      string `json:"reason"`
+	Piece       int    `json:"piece"`
+	PieceLength int64  `json:"piece_length"`
+}
+
+// ParseCommand ek text message ko typed Command mein badalta hai.
+// File names ke separators LocalFilename se local OS ke hisab se badal diye jaate hai.
+// Required fields missing ho (jaise REQUEST_FILE bina file_id) toh error return karta hai.
+func ParseCommand(s string) (Command, error) {
+	var w wireCommand
+	if err := json.Unmarshal([]byte(s), &w); err != nil {
+		return nil, fmt.Errorf("invalid command message: %w", err)
+	}
+
+	switch {
+	case w.Command == string(CommandRequestFile):
+		if w.FileID == "" {
+			return nil, errors.New("REQUEST_FILE without file_id")
+		}
+		return RequestFileCommand{FileID: w.FileID}, nil
+	case w.Command == string(CommandFileStart):
+		if w.Name == "" {
+			return nil, errors.New("FILE_START without name")
+		}
+		if w.Size < 0 {
+			return nil, fmt.Errorf("FILE_START with invalid size %d", w.Size)
+		}
+		return FileStartCommand{Filename: LocalFilename(w.Name), Size: w.Size, Compress: w.Compress, ContentType: w.ContentType, Offset: w.Offset}, nil
+	case w.Command == string(CommandRequestRange):
+		if w.FileID == "" {
+			return nil, errors.New("REQUEST_RANGE without file_id")
+		}
+		if w.Start < 0 || w.End <= w.Start {
+			return nil, fmt.Errorf("REQUEST_RANGE with invalid range [%d, %d)", w.Start, w.End)
+		}
+		return RequestRangeCommand{FileID: w.FileID, Start: w.Start, End: w.End}, nil
+	case w.Command == string(CommandRangeEnd):
+		return RangeEndCommand{Filename: LocalFilename(w.Name), Start: w.Start, End: w.End}, nil
+	case w.Command == string(CommandFileExists):
+		if w.FileHash == "" {
+			return nil, errors.New("FILE_EXISTS without file_hash")
+		}
+		return FileExistsCommand{FileHash: w.FileHash, Filename: w.Name}, nil
+	case w.Command == string(CommandCancel):
+		return CancelCommand{Filename: w.Name, Reason: w.Reason}, nil
+	case w.Command == string(CommandNack):
+		if w.FileHash == "" {
+			return nil, errors.New("NACK without file_hash")
+		}
+		if w.Piece < 0 || w.PieceLength <= 0 {
+			return nil, fmt.Errorf("NACK with invalid piece %d (length %d)", w.Piece, w.PieceLength)
+		}
+		return NackCommand{FileHash: w.FileHash, Filename: LocalFilename(w.Name), Piece: w.Piece, PieceLength: w.PieceLength}, nil
+	case w.Command == string(CommandPieceData):
+		if w.FileHash == "" {
+			return nil, errors.New("PIECE_DATA without file_hash")
+		}
+		if w.Offset < 0 || w.Size < 0 {
+			return nil, fmt.Errorf("PIECE_DATA with invalid offset %d or size %d", w.Offset, w.Size)
+		}
+		return PieceDataCommand{FileHash: w.FileHash, Filename: LocalFilename(w.Name), Piece: w.Piece, Offset: w.Offset, Size: w.Size}, nil
+	case w.Command == string(CommandPieceEnd):
+		return PieceEndCommand{FileHash: w.FileHash, Filename: LocalFilename(w.Name), Piece: w.Piece}, nil
+	case w.Command == string(CommandBitfield):
+		return BitfieldCommand{FileHash: w.FileHash, Pieces: w.Pieces, Bitfield: w.Bitfield}, nil
+	case w.Status == string(CommandFileEnd):
+		return FileEndCommand{Filename: LocalFilename(w.Name)}, nil
+	case w.Error != "":
+		return ErrorCommand{Message: w.Error}, nil
+	case w.Command != "":
+		return RawCommand{Name: w.Command, Data: []byte(s)}, nil
+	}
+	return nil, errors.New("message has no command")
+}
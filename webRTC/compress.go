@@ -0,0 +1,88 @@
+package webRTC
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// CompressionGzip FILE_START message ka "compress" flag hai jab file gzip karke bheji jaati hai
+const CompressionGzip = "gzip"
+
+// in extensions ki files pehle se compressed hoti hai, inhe dobara gzip karna bekaar hai
+var compressedExts = map[string]bool{
+	".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".7z": true, ".rar": true,
+	".mp4": true, ".mkv": true, ".mov": true, ".webm": true, ".mp3": true, ".ogg": true,
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true,
+}
+
+// shouldCompress file ke extension aur shuru ke bytes se MIME type nikalta hai,
+// aur sirf text/*, application/json aur application/xml ke liye true return karta hai
+func shouldCompress(filename string, head []byte) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if compressedExts[ext] {
+		return false
+	}
+	mimeType := mime.TypeByExtension(ext)
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head)
+	}
+	if base, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = base
+	}
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || mimeType == "application/xml"
+}
+
+// r ka data gzip karke ek reader ki tarah deta hai, compression alag goroutine mein hota hai
+func gzipReader(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, r)
+		if cerr := gz.Close(); err == nil {
+			err = cerr
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// gunzipWriter compressed chunks leta hai aur decompress karke underlying writer mein likhta hai
+type gunzipWriter struct {
+	pw   *io.PipeWriter
+	dst  io.WriteCloser
+	done chan error
+}
+
+// NewGunzipWriter ek writer return karta hai jo gzip data ko decompress karke dst mein likhta hai.
+// Close karne par decompression poora hone ka wait hota hai aur phir dst bhi close ho jaata hai.
+func NewGunzipWriter(dst io.WriteCloser) io.WriteCloser {
+	pr, pw := io.Pipe()
+	w := &gunzipWriter{pw: pw, dst: dst, done: make(chan error, 1)}
+	go func() {
+		gz, err := gzip.NewReader(pr)
+		if err == nil {
+			_, err = io.Copy(dst, gz)
+		}
+		// error aane par writer side ko bhi bata dete hai taaki Write block na ho
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w
+}
+
+func (w *gunzipWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *gunzipWriter) Close() error {
+	w.pw.Close()
+	err := <-w.done
+	if cerr := w.dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
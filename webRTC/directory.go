@@ -0,0 +1,215 @@
+package webRTC
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContentTypeTarGzip FILE_START ka "content_type" hai jab poori directory tar.gz stream ki tarah aati hai
+const ContentTypeTarGzip = "application/x-tar+gzip"
+
+// SendDirectory directory ko tar.gz archive bana kar data channel par stream karta hai.
+// Receiver archive ko apni ReceiveDir mein extract karta hai.
+func (p *WebRTCPeer) SendDirectory(ctx context.Context, dirPath string) error {
+	send, done := p.trackSend(WireFilename(filepath.Clean(dirPath))+".tar.gz", 0, p.SendRaw)
+	defer done()
+	return SendDirectory(ctx, dirPath, p.chunkSize(DefaultPieceSize), send, p.sendInBand)
+}
+
+// SendDirectory transports ke liye common directory send hai. Archive disk par nahi banta,
+// walk karte karte hi tar.gz bytes chunks mein chale jaate hai, isliye size pehle se pata nahi hota (size 0).
+func SendDirectory(ctx context.Context, dirPath string, pieceSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	if pieceSize <= 0 {
+		pieceSize = DefaultPieceSize
+	}
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dirPath)
+	}
+
+	name := WireFilename(filepath.Clean(dirPath)) + ".tar.gz"
+	start := map[string]interface{}{"command": "FILE_START", "name": name, "size": 0, "content_type": ContentTypeTarGzip}
+	if err := sendText(start); err != nil {
+		return fmt.Errorf("failed to send FILE_START: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTarGz(pw, dirPath))
+	}()
+	defer pr.Close()
+
+	if err := streamChunks(ctx, pr, pieceSize, sendBinary, sendText); err != nil {
+		return fmt.Errorf("failed to send %s: %w", dirPath, err)
+	}
+	return sendText(map[string]string{"status": "TRANSFER_COMPLETE", "name": name})
+}
+
+// dirPath ko tar.gz format mein w mein likhta hai. Entries ke naam directory ke naam se shuru hote hai,
+// jaise "photos/2024/a.jpg". Sirf regular files aur directories jaati hai, symlinks skip hote hai.
+func writeTarGz(w io.Writer, dirPath string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	root := filepath.Clean(dirPath)
+	parent := filepath.Dir(root)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// archive entry ke naam ko dir ke andar ke path mein badalta hai. SafeReceivePath ke ulat subdirectories
+// bani rehti hai, lekin absolute paths aur ".." wale naam reject hote hai.
+func safeArchivePath(dir, name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q", ErrUnsafeFilename, name)
+		}
+	}
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || clean == "." || strings.ContainsRune(clean, 0) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafeFilename, name)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// tarExtractWriter aaye hue tar.gz bytes ko seedha dir mein extract karta hai
+type tarExtractWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+// NewTarExtractWriter ek writer return karta hai jo tar.gz stream ko dir mein extract karta hai.
+// Archive ka size pehle se pata nahi hota, isliye extract hue bytes gine jaate hai: limit (0 = koi limit nahi) ya dir ki
+// free space paar hote hi extraction ErrFileTooLarge/ErrInsufficientSpace ke saath ruk jaata hai aur agla Write wahi error deta hai.
+// Close extraction poora hone ka wait karta hai aur archive ka pehla error return karta hai.
+func NewTarExtractWriter(dir string, limit int64) io.WriteCloser {
+	pr, pw := io.Pipe()
+	w := &tarExtractWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := extractTarGz(pr, dir, limit)
+		// error aane par writer side ko bhi bata dete hai taaki Write block na ho
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w
+}
+
+func (w *tarExtractWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *tarExtractWriter) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+// gzip bomb disk na bhar de, isliye compressed nahi balki extract hue bytes budget mein gine jaate hai
+func extractTarGz(r io.Reader, dir string, limit int64) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	budget := &receiveBudget{dir: dir, limit: limit}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := safeArchivePath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			// receive directory mein pehle se padi file archive overwrite nahi karta
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if errors.Is(err, fs.ErrExist) {
+				return fmt.Errorf("%s already exists in the receive directory: %w", hdr.Name, err)
+			}
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(&budgetWriter{WriteCloser: f, budget: budget}, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				// adhoori file receive directory mein nahi chhodte
+				os.Remove(target)
+				return err
+			}
+		default:
+			// links aur devices extract nahi karte
+		}
+	}
+}
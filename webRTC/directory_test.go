@@ -0,0 +1,80 @@
+package webRTC
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dir ka tar.gz banata hai jaise sender bhejta hai
+func tarGzOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "photos")
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := writeTarGz(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// archive ko NewTarExtractWriter se dir mein extract karta hai; pehla Write ya Close error return hota hai
+func extractInto(dir string, limit int64, archive []byte) error {
+	w := NewTarExtractWriter(dir, limit)
+	_, werr := w.Write(archive)
+	cerr := w.Close()
+	if werr != nil {
+		return werr
+	}
+	return cerr
+}
+
+func TestTarExtractStopsAtSizeLimit(t *testing.T) {
+	// bahut compress hone wala content: archive chhota hai, extract hone par limit se bada
+	archive := tarGzOf(t, map[string]string{"big.bin": string(make([]byte, 1<<20))})
+	if len(archive) >= 1<<16 {
+		t.Fatalf("archive is %d bytes, want it well under the limit", len(archive))
+	}
+
+	dir := t.TempDir()
+	if err := extractInto(dir, 1<<16, archive); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("extracting past the limit: err = %v, want ErrFileTooLarge", err)
+	}
+	if files := filesUnder(t, dir); len(files) != 0 {
+		t.Fatalf("aborted extraction left %v behind", files)
+	}
+
+	if err := extractInto(t.TempDir(), 2<<20, archive); err != nil {
+		t.Fatalf("extracting under the limit: %v", err)
+	}
+}
+
+func TestTarExtractKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "photos", "a.jpg")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := tarGzOf(t, map[string]string{"a.jpg": "theirs"})
+	if err := extractInto(dir, 0, archive); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("extracting over an existing file: err = %v, want fs.ErrExist", err)
+	}
+	if got, err := os.ReadFile(existing); err != nil || string(got) != "mine" {
+		t.Fatalf("existing file = %q, %v; want it untouched", got, err)
+	}
+}
@@ -0,0 +1,116 @@
+package webRTC
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CancelReasonInsufficientSpace CANCEL message ka reason hai jab receiver ki disk par file ke liye jagah nahi hai
+const CancelReasonInsufficientSpace = "INSUFFICIENT_SPACE"
+
+// CancelReasonFileTooLarge CANCEL message ka reason hai jab file receiver ki MaxReceiveFileSize limit se badi hai
+const CancelReasonFileTooLarge = "FILE_TOO_LARGE"
+
+// ErrFileTooLarge tab aata hai jab aane wali file receiver ki size limit se badi hai
+var ErrFileTooLarge = errors.New("file exceeds receive size limit")
+
+// ErrInsufficientSpace tab aata hai jab receive directory mein aane wali file ke liye kaafi free space nahi hai
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// diskSpaceMargin file size ke upar itni extra jagah chahiye (10%), taaki disk bilkul full na ho
+const diskSpaceMargin = 1.1
+
+// CheckDiskSpace batata hai ki dir wali filesystem par size bytes (plus 10% margin) ki jagah hai ya nahi.
+// dir abhi bani na ho toh current directory check hoti hai (receive dir usi ke andar banti hai). Jahan free space pata nahi chal sakta wahan nil return hota hai.
+func CheckDiskSpace(dir string, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+	if dir == "" {
+		dir = DefaultReceiveDir
+	}
+	if _, err := os.Stat(dir); err != nil {
+		dir = "."
+	}
+	free, ok, err := freeSpace(dir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	if need := uint64(float64(size) * diskSpaceMargin); free < need {
+		return fmt.Errorf("%w: need %d bytes, %d available", ErrInsufficientSpace, need, free)
+	}
+	return nil
+}
+
+// CheckSizeLimit batata hai ki size bytes ki file limit ke andar hai ya nahi; limit <= 0 ho toh koi limit nahi
+func CheckSizeLimit(limit, size int64) error {
+	if limit > 0 && size > limit {
+		return fmt.Errorf("%w: %d bytes, limit is %d", ErrFileTooLarge, size, limit)
+	}
+	return nil
+}
+
+// size pehle se pata na ho toh itne bytes likhne ke baad free space dobara check hoti hai
+const spaceCheckInterval = 64 << 20
+
+// receiveBudget un transfers ke likhe gaye bytes ginta hai jinka size pehle se pata nahi (size 0 streams, directory
+// archives ke extract hue bytes). Limit ya dir ki free space paar karne wala write ErrFileTooLarge/ErrInsufficientSpace deta hai.
+type receiveBudget struct {
+	dir     string
+	limit   int64 // 0 ho toh koi limit nahi
+	written int64
+	checked int64 // itne bytes tak free space check ho chuki hai
+}
+
+// n aur bytes likhne ki ijazat deta hai; error aane par kuch nahi ginta
+func (b *receiveBudget) reserve(n int) error {
+	next := b.written + int64(n)
+	if err := CheckSizeLimit(b.limit, next); err != nil {
+		return err
+	}
+	if next > b.checked {
+		// free space pata na chale toh transfer nahi rokte, CheckReceiveSpace jaisa hi
+		if err := CheckDiskSpace(b.dir, max(int64(n), spaceCheckInterval)); errors.Is(err, ErrInsufficientSpace) {
+			return err
+		}
+		b.checked = next + spaceCheckInterval
+	}
+	b.written = next
+	return nil
+}
+
+// budgetWriter har write se pehle budget check karta hai. Ek budget kai writers share kar sakte hai (archive ki files).
+type budgetWriter struct {
+	io.WriteCloser
+	budget *receiveBudget
+}
+
+func (w *budgetWriter) Write(p []byte) (int, error) {
+	if err := w.budget.reserve(len(p)); err != nil {
+		return 0, err
+	}
+	return w.WriteCloser.Write(p)
+}
+
+// CheckReceiveSpace size bytes ki file ko MaxReceiveFileSize se aur ReceiveDir ki free space se check karta hai
+func (p *WebRTCPeer) CheckReceiveSpace(size int64) error {
+	if err := CheckSizeLimit(p.MaxReceiveFileSize, size); err != nil {
+		return err
+	}
+	return CheckDiskSpace(p.ReceiveDir, size)
+}
+
+// CancelMessage CANCEL data channel command hai: receiver batata hai ki woh filename nahi le sakta aur kyun
+func CancelMessage(filename, reason string) map[string]string {
+	return map[string]string{"command": "CANCEL", "name": filename, "reason": reason}
+}
+
+// SendCancel sender ko batata hai ki filename ka transfer reason ki wajah se nahi chahiye, taaki woh bhejna band kar de
+func (p *WebRTCPeer) SendCancel(filename, reason string) error {
+	return p.Send(CancelMessage(filename, reason))
+}
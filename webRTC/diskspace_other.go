@@ -0,0 +1,8 @@
+//go:build !unix
+
+package webRTC
+
+// is platform par free space check nahi hota, transfer hamesha accept hota hai
+func freeSpace(dir string) (uint64, bool, error) {
+	return 0, false, nil
+}
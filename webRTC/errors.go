@@ -0,0 +1,51 @@
+package webRTC
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// ErrNotConnected tab aata hai jab peer connection closed ya failed hai aur uspar kuch bheja nahi ja sakta
+var ErrNotConnected = errors.New("peer is not connected")
+
+// ErrChannelClosed tab aata hai jab data channel band ho raha hai ya band ho chuka hai
+var ErrChannelClosed = errors.New("data channel closed")
+
+// ErrFileNotFound tab aata hai jab bhejne wali file disk par nahi milti
+var ErrFileNotFound = errors.New("file not found")
+
+// TransferError ek file transfer ke dauraan aaya error hai. Filename file ka naam ya file ID hai,
+// Peer remote peer hai (pata na ho toh empty). Cause sentinel errors (ErrNotConnected, ...) ko wrap karta hai,
+// isliye caller errors.Is aur errors.As dono use kar sakta hai.
+type TransferError struct {
+	Filename string
+	Peer     peer.ID
+	Cause    error
+}
+
+func (e *TransferError) Error() string {
+	switch {
+	case e.Peer != "" && e.Filename != "":
+		return fmt.Sprintf("transfer of %s with %s: %v", e.Filename, e.Peer, e.Cause)
+	case e.Filename != "":
+		return fmt.Sprintf("transfer of %s: %v", e.Filename, e.Cause)
+	case e.Peer != "":
+		return fmt.Sprintf("transfer with %s: %v", e.Peer, e.Cause)
+	}
+	return fmt.Sprintf("transfer: %v", e.Cause)
+}
+
+func (e *TransferError) Unwrap() error {
+	return e.Cause
+}
+
+// bhejne wali file na khule toh error; file exist na kare toh ErrFileNotFound bhi wrap hota hai
+func openError(filename string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to open %s: %w", filename, errors.Join(ErrFileNotFound, err))
+	}
+	return fmt.Errorf("failed to open %s: %w", filename, err)
+}
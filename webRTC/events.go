@@ -0,0 +1,76 @@
+package webRTC
+
+import "time"
+
+// TransferEvent ke types
+const (
+	TransferStart    = "start"
+	TransferProgress = "progress"
+	TransferComplete = "complete"
+	TransferFailed   = "error"
+	TransferCorrupt  = "corrupt" // download ka SHA-256 tracker ke record se match nahi hua, file delete ho chuki hai
+)
+
+// TransferEvent ek file transfer ki progress ko describe karta hai.
+// TotalBytes 0 ho toh file ka size pata nahi hai. PeerID remote peer ka libp2p ID hai, pata na ho toh empty.
+// Cause "error" events mein failure ki wajah hai (jaise ErrReceiveTimeout), pata na ho toh nil.
+type TransferEvent struct {
+	Type       string `json:"type"`
+	Filename   string `json:"filename"`
+	BytesDone  int64  `json:"bytes_done"`
+	TotalBytes int64  `json:"total_bytes"`
+	PeerID     string `json:"peer_id,omitempty"`
+	Cause      error  `json:"-"`
+}
+
+// Events channel return karta hai jispe is peer ke transfer events aate hain
+func (p *WebRTCPeer) Events() <-chan TransferEvent {
+	return p.events
+}
+
+// SetTransferInfo aane wali file ka naam aur size set karta hai aur "start" event bhejta hai
+func (p *WebRTCPeer) SetTransferInfo(filename string, totalBytes int64) {
+	p.mu.Lock()
+	p.transferName = filename
+	p.transferTotal = totalBytes
+	p.transferDone = 0
+	p.transferStarted = time.Now()
+	p.receiving = true
+	p.resetReceiveDeadline()
+	p.mu.Unlock()
+
+	p.emit(TransferEvent{Type: TransferStart, Filename: filename, TotalBytes: totalBytes})
+}
+
+// CompleteTransfer current transfer ko complete mark karke "complete" event bhejta hai
+func (p *WebRTCPeer) CompleteTransfer() {
+	p.mu.Lock()
+	ev := TransferEvent{Type: TransferComplete, Filename: p.transferName, BytesDone: p.transferDone, TotalBytes: p.transferTotal}
+	p.receiving = false
+	p.mu.Unlock()
+
+	p.emit(ev)
+}
+
+// har binary chunk ke baad received bytes update karke "progress" event bhejta hai
+func (p *WebRTCPeer) recordReceived(n int) {
+	p.mu.Lock()
+	p.transferDone += int64(n)
+	p.resetReceiveDeadline()
+	ev := TransferEvent{Type: TransferProgress, Filename: p.transferName, BytesDone: p.transferDone, TotalBytes: p.transferTotal}
+	p.mu.Unlock()
+
+	p.emit(ev)
+}
+
+// event ko non-blocking tarike se bhejta hai, agar koi sun nahi raha aur buffer full hai toh drop kar deta hai.
+// Event mein remote peer ka ID bhar diya jaata hai taaki logs aur UI errors ko peer se jod sake.
+func (p *WebRTCPeer) emit(ev TransferEvent) {
+	if id := p.RemotePeerID(); id != "" {
+		ev.PeerID = id.String()
+	}
+	select {
+	case p.events <- ev:
+	default:
+	}
+}
@@ -0,0 +1,89 @@
+package webRTC
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// HMACSize har binary frame ke shuru mein lage HMAC-SHA256 tag ka size hai
+const HMACSize = sha256.Size
+
+// ErrHMACMismatch tab aata hai jab aaye chunk ka HMAC match nahi karta, yaani raste mein chunk badla gaya
+var ErrHMACMismatch = errors.New("chunk HMAC mismatch")
+
+// shared secret se HMAC key nikalte waqt yeh label use hota hai, taaki secret kisi aur kaam ki key na ban jaye
+const hmacKeyLabel = "torrentium/transfer-hmac/v1"
+
+// EnableHMAC is connection ke binary chunks par HMAC-SHA256 lagata hai. sharedSecret dono peers ke paas same hona chahiye
+// (jaise libp2p identity keys ka ECDH secret) aur dono taraf EnableHMAC hona chahiye, warna chunks reject honge.
+// Har frame ke pehle HMACSize bytes mein HMAC(key, sequence || chunk) hota hai; sequence number se chunks ko
+// replay ya reorder bhi nahi kiya ja sakta. Koi bhi binary data bhejne se pehle call karna chahiye.
+func (p *WebRTCPeer) EnableHMAC(sharedSecret []byte) {
+	mac := hmac.New(sha256.New, sharedSecret)
+	mac.Write([]byte(hmacKeyLabel))
+	key := mac.Sum(nil)
+
+	p.hmacMu.Lock()
+	defer p.hmacMu.Unlock()
+	p.hmacKey = key
+	p.sendSeq, p.recvSeq = 0, 0
+}
+
+// HMACEnabled batata hai ki binary chunks par HMAC lag raha hai ya nahi
+func (p *WebRTCPeer) HMACEnabled() bool {
+	p.hmacMu.Lock()
+	defer p.hmacMu.Unlock()
+	return p.hmacKey != nil
+}
+
+// binary frame bhejta hai; HMAC on ho toh seal karke. Seal aur send ek hi lock mein hote hai taaki
+// sequence numbers usi order mein jaye jismein frames channel par jaate hai.
+func (p *WebRTCPeer) sendBinary(data []byte, send func(outboxMessage) error) error {
+	p.hmacMu.Lock()
+	if p.hmacKey == nil {
+		p.hmacMu.Unlock()
+		return send(outboxMessage{data: data})
+	}
+	defer p.hmacMu.Unlock()
+
+	frame := make([]byte, HMACSize, HMACSize+len(data))
+	copy(frame, chunkMAC(p.hmacKey, p.sendSeq, data))
+	frame = append(frame, data...)
+	if err := send(outboxMessage{data: frame}); err != nil {
+		return err
+	}
+	p.sendSeq++
+	return nil
+}
+
+// aaye binary frame ka HMAC check karke chunk return karta hai. HMAC off ho toh frame jaisa hai waisa lautata hai.
+func (p *WebRTCPeer) openChunk(frame []byte) ([]byte, error) {
+	p.hmacMu.Lock()
+	defer p.hmacMu.Unlock()
+	if p.hmacKey == nil {
+		return frame, nil
+	}
+	seq := p.recvSeq
+	// frame reject ho tab bhi sequence aage badhta hai, kyunki sender ne woh number use kar liya hai
+	p.recvSeq++
+	if len(frame) < HMACSize {
+		return nil, ErrHMACMismatch
+	}
+	tag, data := frame[:HMACSize], frame[HMACSize:]
+	if !hmac.Equal(tag, chunkMAC(p.hmacKey, seq, data)) {
+		return nil, ErrHMACMismatch
+	}
+	return data, nil
+}
+
+// HMAC(key, big-endian sequence || data)
+func chunkMAC(key []byte, seq uint64, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], seq)
+	mac.Write(b[:])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
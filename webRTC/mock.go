@@ -0,0 +1,187 @@
+package webRTC
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// MockWebRTCPeer WebRTCPeer jaisi hi API deta hai bina kisi asli peer connection ke. Bheje gaye text messages
+// aur binary chunks memory mein record hote hai, taaki message handlers aur sendFile ko bina WebRTC stack ke
+// chalaya ja sake. Receive wale methods asli peer ki tarah ReceiveDir mein likhte hai.
+type MockWebRTCPeer struct {
+	ReceiveDir         string // default ./downloads
+	MaxReceiveFileSize int64  // 0 ho toh koi limit nahi
+
+	mu         sync.Mutex
+	text       []json.RawMessage // SendTextData se bheje gaye messages, JSON form mein
+	binary     bytes.Buffer      // SendBinaryData se bheje gaye saare bytes
+	sendErr    error             // set ho toh har send yehi error deta hai
+	fileWriter io.WriteCloser
+	closed     bool
+	events     chan TransferEvent
+
+	transferName  string
+	transferTotal int64
+}
+
+// NewMockWebRTCPeer ek khaali mock peer banata hai
+func NewMockWebRTCPeer() *MockWebRTCPeer {
+	return &MockWebRTCPeer{ReceiveDir: DefaultReceiveDir, events: make(chan TransferEvent, 64)}
+}
+
+// FailSends ke baad har send err deta hai (nil dene par send phir se kaam karte hai), broken channel simulate karne ke liye
+func (m *MockWebRTCPeer) FailSends(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sendErr = err
+}
+
+// SentText ab tak bheje gaye text messages ki copy hai
+func (m *MockWebRTCPeer) SentText() []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]json.RawMessage(nil), m.text...)
+}
+
+// SentBinary ab tak bheje gaye saare binary bytes ki copy hai
+func (m *MockWebRTCPeer) SentBinary() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return bytes.Clone(m.binary.Bytes())
+}
+
+// IsConnected Close hone tak true hai
+func (m *MockWebRTCPeer) IsConnected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.closed
+}
+
+func (m *MockWebRTCPeer) SendTextData(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.text = append(m.text, raw)
+	return nil
+}
+
+func (m *MockWebRTCPeer) SendBinaryData(data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.binary.Write(data)
+	return nil
+}
+
+func (m *MockWebRTCPeer) RequestFile(fileID string) error {
+	return m.SendTextData(map[string]string{"command": "REQUEST_FILE", "file_id": fileID})
+}
+
+func (m *MockWebRTCPeer) RequestFileRange(fileID string, start, end int64) error {
+	return m.SendTextData(RangeRequest(fileID, start, end))
+}
+
+func (m *MockWebRTCPeer) SendFileWithContext(ctx context.Context, filename string, pieceSize int) error {
+	return SendFile(ctx, filename, pieceSize, m.SendBinaryData, m.SendTextData)
+}
+
+func (m *MockWebRTCPeer) SendFileRange(ctx context.Context, filename string, start, end int64) error {
+	return SendFileRange(ctx, filename, start, end, DefaultPieceSize, m.SendBinaryData, m.SendTextData)
+}
+
+func (m *MockWebRTCPeer) SendDirectory(ctx context.Context, dirPath string) error {
+	return SendDirectory(ctx, dirPath, DefaultPieceSize, m.SendBinaryData, m.SendTextData)
+}
+
+func (m *MockWebRTCPeer) SendStream(ctx context.Context, r io.Reader, name string) error {
+	return SendStream(ctx, r, name, DefaultPieceSize, m.SendBinaryData, m.SendTextData)
+}
+
+func (m *MockWebRTCPeer) ReceiveFile(filename string) (io.WriteCloser, error) {
+	return CreateReceiveFile(m.ReceiveDir, filename)
+}
+
+func (m *MockWebRTCPeer) ReceiveStream(filename string) (io.WriteCloser, error) {
+	return CreateReceiveStream(m.ReceiveDir, filename, m.MaxReceiveFileSize)
+}
+
+func (m *MockWebRTCPeer) ReceiveFileAt(filename string, offset int64) (io.WriteCloser, error) {
+	return OpenReceiveFileAt(m.ReceiveDir, filename, offset)
+}
+
+func (m *MockWebRTCPeer) ReceiveDirectory() (io.WriteCloser, error) {
+	return CreateReceiveDirectory(m.ReceiveDir, m.MaxReceiveFileSize)
+}
+
+func (m *MockWebRTCPeer) CheckReceiveSpace(size int64) error {
+	return CheckSizeLimit(m.MaxReceiveFileSize, size)
+}
+
+func (m *MockWebRTCPeer) SendCancel(filename, reason string) error {
+	return m.SendTextData(CancelMessage(filename, reason))
+}
+
+func (m *MockWebRTCPeer) SendNack(fileHash, filename string, piece int, pieceLength int64) error {
+	return m.SendTextData(NackMessage(fileHash, filename, piece, pieceLength))
+}
+
+func (m *MockWebRTCPeer) SendPiece(ctx context.Context, filename, fileHash string, piece int, pieceLength int64) error {
+	return SendPiece(ctx, filename, fileHash, piece, pieceLength, DefaultPieceSize, m.SendBinaryData, m.SendTextData)
+}
+
+func (m *MockWebRTCPeer) SetFileWriter(writer io.WriteCloser) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fileWriter = writer
+}
+
+func (m *MockWebRTCPeer) GetFileWriter() io.WriteCloser {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fileWriter
+}
+
+func (m *MockWebRTCPeer) SetTransferInfo(filename string, totalBytes int64) {
+	m.mu.Lock()
+	m.transferName, m.transferTotal = filename, totalBytes
+	m.mu.Unlock()
+	m.emit(TransferEvent{Type: TransferStart, Filename: filename, TotalBytes: totalBytes})
+}
+
+func (m *MockWebRTCPeer) CompleteTransfer() {
+	m.mu.Lock()
+	ev := TransferEvent{Type: TransferComplete, Filename: m.transferName, TotalBytes: m.transferTotal}
+	m.mu.Unlock()
+	m.emit(ev)
+}
+
+// Events channel par mock ke start/complete events aate hai
+func (m *MockWebRTCPeer) Events() <-chan TransferEvent {
+	return m.events
+}
+
+// Close ke baad IsConnected false hai; events channel band nahi hota taaki der se aaye emit panic na kare
+func (m *MockWebRTCPeer) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *MockWebRTCPeer) emit(ev TransferEvent) {
+	select {
+	case m.events <- ev:
+	default:
+	}
+}
@@ -0,0 +1,84 @@
+package webRTC
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// TransferInfo.Direction ki values
+const (
+	DirectionSend    = "send"
+	DirectionReceive = "receive"
+)
+
+// TransferInfo ek chalte hue transfer ka snapshot hai. TotalBytes 0 ho toh size pata nahi hai (directory, pipe).
+// Send ke liye BytesDone data channel par gaye bytes hai, isliye gzip wali file mein yeh TotalBytes tak nahi pahunchta.
+type TransferInfo struct {
+	Filename   string    `json:"filename"`
+	Direction  string    `json:"direction"`
+	BytesDone  int64     `json:"bytes_done"`
+	TotalBytes int64     `json:"total_bytes"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+// PendingTransfers is peer ke saare chalte hue send aur receive transfers return karta hai, sabse purana pehle
+func (p *WebRTCPeer) PendingTransfers() []TransferInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var out []TransferInfo
+	if p.receiving {
+		out = append(out, TransferInfo{
+			Filename:   p.transferName,
+			Direction:  DirectionReceive,
+			BytesDone:  p.transferDone,
+			TotalBytes: p.transferTotal,
+			StartedAt:  p.transferStarted,
+		})
+	}
+	for _, t := range p.sends {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+// trackSend ek outgoing transfer register karta hai. Lautaya gaya send sendBinary ko wrap karke bheje gaye bytes
+// ginta hai, aur done transfer ko list se hata deta hai (defer ke saath call karna hai).
+func (p *WebRTCPeer) trackSend(name string, total int64, sendBinary func([]byte) error) (send func([]byte) error, done func()) {
+	t := &TransferInfo{Filename: name, Direction: DirectionSend, TotalBytes: total, StartedAt: time.Now()}
+	p.mu.Lock()
+	if p.sends == nil {
+		p.sends = make(map[uint64]*TransferInfo)
+	}
+	p.nextSendID++
+	id := p.nextSendID
+	p.sends[id] = t
+	p.mu.Unlock()
+
+	send = func(data []byte) error {
+		if err := sendBinary(data); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		t.BytesDone += int64(len(data))
+		p.mu.Unlock()
+		return nil
+	}
+	done = func() {
+		p.mu.Lock()
+		delete(p.sends, id)
+		p.mu.Unlock()
+	}
+	return send, done
+}
+
+// file ka size, stat fail ho toh 0 (size pata nahi); asli error send function khud return karega
+func fileSizeOrZero(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,212 @@
+package webRTC
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// PoolChannelPrefix pool ke data channels ke labels ka prefix hai: "data-0" se "data-N-1" tak
+const PoolChannelPrefix = "data-"
+
+// MaxDataChannelPoolSize ek connection par pool channels ki upper limit hai, isse zyada aaye channels ignore hote hai
+const MaxDataChannelPoolSize = 16
+
+// ErrPoolEmpty tab aata hai jab connection par koi pool channel nahi hai (remote ne pool nahi khola)
+var ErrPoolEmpty = errors.New("data channel pool is empty")
+
+// ErrPoolClosed tab aata hai jab peer band hone ke baad Acquire call hota hai
+var ErrPoolClosed = errors.New("data channel pool is closed")
+
+// DataChannelPool connection ke pre-opened data channels rakhta hai, taaki kai pieces alag alag channels par
+// ek saath chal sake aur ek channel ka head-of-line blocking dusre pieces ko na roke.
+// Har channel ek time par ek hi piece le jaata hai: Acquire se lo, piece bhejo, Release se wapas do.
+type DataChannelPool struct {
+	channels []*webrtc.DataChannel
+	idle     chan *webrtc.DataChannel
+
+	mu     sync.Mutex
+	closed chan struct{}
+}
+
+// NewDataChannelPool ek khaali pool banata hai; channels open hone par add hote hai
+func NewDataChannelPool() *DataChannelPool {
+	return &DataChannelPool{
+		idle:   make(chan *webrtc.DataChannel, MaxDataChannelPoolSize),
+		closed: make(chan struct{}),
+	}
+}
+
+// PoolChannelLabel pool ke i-th channel ka label hai
+func PoolChannelLabel(i int) string {
+	return fmt.Sprintf("%s%d", PoolChannelPrefix, i)
+}
+
+// IsPoolChannelLabel batata hai ki label pool channel ka hai ya nahi
+func IsPoolChannelLabel(label string) bool {
+	return strings.HasPrefix(label, PoolChannelPrefix)
+}
+
+// Size pool mein kitne channels hai (open ho chuke)
+func (dp *DataChannelPool) Size() int {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+	return len(dp.channels)
+}
+
+// Acquire ek idle channel deta hai, sab busy ho toh kisi ke Release hone tak rukta hai.
+// Pool khaali ho toh ErrPoolEmpty, peer band ho jaye toh ErrPoolClosed.
+func (dp *DataChannelPool) Acquire() (*webrtc.DataChannel, error) {
+	if dp.Size() == 0 {
+		return nil, ErrPoolEmpty
+	}
+	select {
+	case dc := <-dp.idle:
+		return dc, nil
+	case <-dp.closed:
+		return nil, ErrPoolClosed
+	}
+}
+
+// Release Acquire se liya channel wapas pool mein daalta hai
+func (dp *DataChannelPool) Release(dc *webrtc.DataChannel) {
+	select {
+	case dp.idle <- dc:
+	default:
+	}
+}
+
+// open hua channel pool mein jodta hai; limit se zyada channels false return karte hai
+func (dp *DataChannelPool) add(dc *webrtc.DataChannel) bool {
+	dp.mu.Lock()
+	if len(dp.channels) >= MaxDataChannelPoolSize {
+		dp.mu.Unlock()
+		return false
+	}
+	dp.channels = append(dp.channels, dc)
+	dp.mu.Unlock()
+	dp.Release(dc)
+	return true
+}
+
+// close ke baad rukhe hue aur naye Acquire ErrPoolClosed dete hai
+func (dp *DataChannelPool) close() {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+	select {
+	case <-dp.closed:
+	default:
+		close(dp.closed)
+	}
+}
+
+// PieceHandlers pool channels par aaye pieces receive karte hai. Open PIECE_DATA par piece ka writer deta hai
+// (error par piece drop hota hai), Done PIECE_END par writer band hone ke baad call hota hai; err piece likhne ki galti hai.
+type PieceHandlers struct {
+	Open func(cmd PieceDataCommand) (io.WriteCloser, error)
+	Done func(cmd PieceEndCommand, err error)
+}
+
+// SetPieceHandlers pool channels par aane wale pieces ke handlers set karta hai. Set na ho toh aise pieces drop hote hai.
+func (p *WebRTCPeer) SetPieceHandlers(h PieceHandlers) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pieceHandlers = h
+}
+
+// PoolSize peer ke pool mein open channels ki ginti hai; 0 ho toh pieces control/data channel par ek-ek karke jaate hai
+func (p *WebRTCPeer) PoolSize() int {
+	return p.pool.Size()
+}
+
+// pool channel ka receive side. Ek channel par messages order mein aate hai, isliye PIECE_DATA, chunks aur PIECE_END
+// ek hi channel par hone se kisi aur state ki zaroorat nahi: writer sirf is channel ka hai.
+func (p *WebRTCPeer) handlePoolChannel(dc *webrtc.DataChannel) {
+	var writer io.WriteCloser
+	var writeErr error
+	dc.OnOpen(func() {
+		if !p.pool.add(dc) {
+			logger.Warn("Too many pool channels, ignoring", "label", dc.Label())
+		}
+	})
+	dc.OnMessage(func(msg webrtc.DataChannelMessage) {
+		if !msg.IsString {
+			if p.HMACEnabled() {
+				// pool channels par HMAC nahi hota; HMAC on ho toh aise chunks par bharosa nahi karte
+				writeErr = ErrHMACMismatch
+				return
+			}
+			if writer == nil {
+				logger.Warn("Received pool chunk outside a piece", "label", dc.Label())
+				return
+			}
+			if writeErr == nil {
+				_, writeErr = writer.Write(msg.Data)
+			}
+			return
+		}
+		cmd, err := ParseCommand(string(msg.Data))
+		if err != nil {
+			logger.Warn("Invalid pool channel message", "label", dc.Label(), "error", err)
+			return
+		}
+		p.mu.RLock()
+		h := p.pieceHandlers
+		p.mu.RUnlock()
+		switch cmd := cmd.(type) {
+		case PieceDataCommand:
+			if h.Open == nil {
+				logger.Warn("No piece handler, dropping piece", "piece", cmd.Piece)
+				return
+			}
+			writer, writeErr = h.Open(cmd)
+			if writeErr != nil {
+				logger.Warn("Refused pooled piece", "file", cmd.Filename, "piece", cmd.Piece, "error", writeErr)
+				writer = nil
+			}
+		case PieceEndCommand:
+			if writer == nil {
+				return
+			}
+			if err := writer.Close(); err != nil && writeErr == nil {
+				writeErr = err
+			}
+			writer = nil
+			if h.Done != nil {
+				h.Done(cmd, writeErr)
+			}
+		default:
+			// errors waghera control channel jaise hi handle hote hai
+			p.onMessage(msg, p)
+		}
+	})
+}
+
+// piece bhejne ke liye pool se ek channel leta hai. Pool channels par HMAC nahi lagta, isliye HMAC on ho
+// ya pool khaali ho toh ok false hota hai aur piece purane raaste (control + data channel) se jaata hai.
+func (p *WebRTCPeer) acquirePoolChannel() (*webrtc.DataChannel, bool) {
+	if p.HMACEnabled() || p.pool.Size() == 0 {
+		return nil, false
+	}
+	dc, err := p.pool.Acquire()
+	if err != nil {
+		return nil, false
+	}
+	return dc, true
+}
+
+// dc par JSON text message bhejne wala sendText
+func poolSendText(dc *webrtc.DataChannel) func(interface{}) error {
+	return func(v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return dc.SendText(string(data))
+	}
+}
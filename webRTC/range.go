@@ -0,0 +1,95 @@
+package webRTC
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RangeRequest REQUEST_RANGE data channel command hai: file ke [start, end) bytes maangta hai
+func RangeRequest(fileID string, start, end int64) map[string]interface{} {
+	return map[string]interface{}{"command": "REQUEST_RANGE", "file_id": fileID, "start": start, "end": end}
+}
+
+// RequestFileRange remote peer se file ke sirf [start, end) bytes maangta hai, jaise adhoore download ko resume karne ke liye.
+// Data FILE_START (offset = start) ke baad binary chunks mein aata hai aur end mein RANGE_END aata hai.
+func (p *WebRTCPeer) RequestFileRange(fileID string, start, end int64) error {
+	return p.Send(RangeRequest(fileID, start, end))
+}
+
+// SendFileRange file ke [start, end) bytes data channel par bhejta hai
+func (p *WebRTCPeer) SendFileRange(ctx context.Context, filename string, start, end int64) error {
+	total := min(end, fileSizeOrZero(filename)) - start
+	send, done := p.trackSend(WireFilename(filename), max(total, 0), p.SendRaw)
+	defer done()
+	return SendFileRange(ctx, filename, start, end, p.chunkSize(DefaultPieceSize), send, p.sendInBand)
+}
+
+// SendFileRange transports ke liye common range send hai. end file size se zyada ho toh file ke end tak bhejta hai.
+// Range mein compression nahi hota, kyunki receiver bytes seedha offset par likhta hai.
+func SendFileRange(ctx context.Context, filename string, start, end int64, pieceSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	if pieceSize <= 0 {
+		pieceSize = DefaultPieceSize
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		sendText(map[string]string{"error": "Could not open file"})
+		return openError(filename, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", filename, err)
+	}
+	if end > info.Size() {
+		end = info.Size()
+	}
+	if start < 0 || start >= end {
+		sendText(map[string]string{"error": "Invalid range"})
+		return fmt.Errorf("invalid range [%d, %d) for %s (%d bytes)", start, end, filename, info.Size())
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek %s: %w", filename, err)
+	}
+
+	name := WireFilename(filename)
+	startMsg := map[string]interface{}{"command": "FILE_START", "name": name, "size": end - start, "offset": start}
+	if err := sendText(startMsg); err != nil {
+		return fmt.Errorf("failed to send FILE_START: %w", err)
+	}
+	if err := streamChunks(ctx, io.LimitReader(file, end-start), pieceSize, sendBinary, sendText); err != nil {
+		return fmt.Errorf("failed to send %s: %w", filename, err)
+	}
+	return sendText(map[string]interface{}{"command": "RANGE_END", "name": name, "start": start, "end": end})
+}
+
+// OpenReceiveFileAt dir ke andar filename ko bina truncate kiye kholta hai aur offset par seek karta hai,
+// taaki range ke bytes file ke sahi hisse mein likhe jaye
+func OpenReceiveFileAt(dir, filename string, offset int64) (*os.File, error) {
+	if offset <= 0 {
+		return CreateReceiveFile(dir, filename)
+	}
+	path, err := SafeReceivePath(dir, filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// ReceiveFileAt OpenReceiveFileAt jaisa hai, ReceiveDir ke andar
+func (p *WebRTCPeer) ReceiveFileAt(filename string, offset int64) (io.WriteCloser, error) {
+	return OpenReceiveFileAt(p.ReceiveDir, filename, offset)
+}
@@ -0,0 +1,97 @@
+package webRTC
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultReceiveDir woh directory hai jismein peers se aayi files save hoti hai
+const DefaultReceiveDir = "./downloads"
+
+// ErrUnsafeFilename tab aata hai jab remote peer ka bheja filename receive directory ke bahar likhne ki koshish karta hai
+var ErrUnsafeFilename = errors.New("unsafe filename")
+
+// SafeReceivePath remote peer ke diye filename ko dir ke andar ke path mein badalta hai.
+// ".." wale names reject hote hai aur baaki mein se sirf last component (filepath.Base) use hota hai,
+// taaki "../../etc/cron.d/evil" jaisa naam dir ke bahar file na bana sake.
+func SafeReceivePath(dir, filename string) (string, error) {
+	// dono separators check karte hai, kyunki sender kisi bhi OS se ho sakta hai
+	for _, part := range strings.FieldsFunc(filename, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q", ErrUnsafeFilename, filename)
+		}
+	}
+	base := filepath.Base(filepath.Clean(strings.ReplaceAll(filename, "\\", "/")))
+	if base == "." || base == ".." || base == string(filepath.Separator) || strings.ContainsRune(base, 0) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafeFilename, filename)
+	}
+	return filepath.Join(dir, base), nil
+}
+
+// WireFilename local path ka woh naam hai jo FILE_START/TRANSFER_COMPLETE mein jaata hai: sirf last component,
+// aur separators hamesha "/" hote hai taaki receiver kisi bhi OS par ho, naam ek jaisa padhe.
+func WireFilename(path string) string {
+	return filepath.ToSlash(filepath.Base(path))
+}
+
+// LocalFilename wire par aaye naam ke separators local OS ke separator mein badalta hai.
+// Windows peers backslash bhej sakte hai, isliye dono separators "/" maan kar phir filepath.FromSlash lagate hai.
+// Directory components yahan nahi hatte; receive directory mein likhne se pehle SafeReceivePath unhe hatata hai.
+func LocalFilename(name string) string {
+	return filepath.FromSlash(strings.ReplaceAll(name, "\\", "/"))
+}
+
+// CreateReceiveFile dir ke andar filename ke liye nayi file banata hai (dir na ho toh bana deta hai)
+func CreateReceiveFile(dir, filename string) (*os.File, error) {
+	path, err := SafeReceivePath(dir, filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+}
+
+// CreateReceiveStream size 0 wale transfer (pipe se aaya stream) ke liye dir mein file banata hai. Size pehle se pata
+// nahi hota, isliye likhe gaye bytes gine jaate hai: limit (0 = koi limit nahi) ya free space paar hote hi Write
+// ErrFileTooLarge/ErrInsufficientSpace deta hai.
+func CreateReceiveStream(dir, filename string, limit int64) (io.WriteCloser, error) {
+	f, err := CreateReceiveFile(dir, filename)
+	if err != nil {
+		return nil, err
+	}
+	return &budgetWriter{WriteCloser: f, budget: &receiveBudget{dir: filepath.Dir(f.Name()), limit: limit}}, nil
+}
+
+// ReceiveFile FILE_START mein aaye filename ke liye ReceiveDir ke andar output file kholta hai
+func (p *WebRTCPeer) ReceiveFile(filename string) (io.WriteCloser, error) {
+	return CreateReceiveFile(p.ReceiveDir, filename)
+}
+
+// ReceiveStream size 0 wale FILE_START ke liye ReceiveDir mein file kholta hai, MaxReceiveFileSize ke budget ke saath
+func (p *WebRTCPeer) ReceiveStream(filename string) (io.WriteCloser, error) {
+	return CreateReceiveStream(p.ReceiveDir, filename, p.MaxReceiveFileSize)
+}
+
+// ReceiveDirectory tar.gz directory stream ke liye writer deta hai jo ReceiveDir mein extract karta hai.
+// Extract hue bytes MaxReceiveFileSize aur free space se check hote hai.
+func (p *WebRTCPeer) ReceiveDirectory() (io.WriteCloser, error) {
+	return CreateReceiveDirectory(p.ReceiveDir, p.MaxReceiveFileSize)
+}
+
+// CreateReceiveDirectory dir bana kar (na ho toh) usmein extract karne wala tar.gz writer deta hai.
+// limit archive se extract hone wale kul bytes ki hadd hai, 0 ho toh koi limit nahi.
+func CreateReceiveDirectory(dir string, limit int64) (io.WriteCloser, error) {
+	if dir == "" {
+		dir = DefaultReceiveDir
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return NewTarExtractWriter(dir, limit), nil
+}
@@ -0,0 +1,128 @@
+package webRTC
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// receiveFileStart remote peer ka FILE_START message handler ki tarah parse karke mock peer ki ReceiveDir
+// mein file banata hai aur content likhta hai
+func receiveFileStart(t *testing.T, p *MockWebRTCPeer, name, content string) error {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"command": "FILE_START", "name": name, "size": len(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	command, err := ParseCommand(string(raw))
+	if err != nil {
+		return err
+	}
+	start, ok := command.(FileStartCommand)
+	if !ok {
+		t.Fatalf("ParseCommand returned %T, want FileStartCommand", command)
+	}
+	w, err := p.ReceiveFile(start.Filename)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	return w.Close()
+}
+
+// root ke andar bani saari files, root se relative aur "/" separators ke saath
+func filesUnder(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestReceiveFileStaysInReceiveDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantFile string // "" ho toh file reject honi chahiye
+	}{
+		{name: "plain", filename: "movie.mkv", wantFile: "recv/movie.mkv"},
+		{name: "parent traversal", filename: "../../../etc/cron.d/evil"},
+		{name: "traversal after dir", filename: "subdir/../../evil"},
+		{name: "windows traversal", filename: `..\..\evil.bat`},
+		{name: "absolute path", filename: "/etc/passwd", wantFile: "recv/passwd"},
+		{name: "nested path", filename: "a/b/c.txt", wantFile: "recv/c.txt"},
+		{name: "dot", filename: ".."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			p := NewMockWebRTCPeer()
+			p.ReceiveDir = filepath.Join(root, "recv")
+
+			err := receiveFileStart(t, p, tt.filename, "payload")
+			if tt.wantFile == "" {
+				if !errors.Is(err, ErrUnsafeFilename) {
+					t.Fatalf("receiving %q: err = %v, want ErrUnsafeFilename", tt.filename, err)
+				}
+			} else if err != nil {
+				t.Fatalf("receiving %q: %v", tt.filename, err)
+			}
+
+			files := filesUnder(t, root)
+			for _, f := range files {
+				if !strings.HasPrefix(f, "recv/") {
+					t.Fatalf("receiving %q wrote %s outside ReceiveDir", tt.filename, f)
+				}
+			}
+			if tt.wantFile == "" {
+				if len(files) != 0 {
+					t.Fatalf("rejected %q still wrote %v", tt.filename, files)
+				}
+				return
+			}
+			if len(files) != 1 || files[0] != tt.wantFile {
+				t.Fatalf("receiving %q wrote %v, want [%s]", tt.filename, files, tt.wantFile)
+			}
+			got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(tt.wantFile)))
+			if err != nil || string(got) != "payload" {
+				t.Fatalf("received content = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestReceiveWindowsPathWritesBaseName(t *testing.T) {
+	root := t.TempDir()
+	p := NewMockWebRTCPeer()
+	p.ReceiveDir = filepath.Join(root, "recv")
+
+	if err := receiveFileStart(t, p, `subdir\file.txt`, "hello from windows"); err != nil {
+		t.Fatal(err)
+	}
+	if files := filesUnder(t, root); len(files) != 1 || files[0] != "recv/file.txt" {
+		t.Fatalf(`receiving subdir\file.txt wrote %v, want only recv/file.txt`, files)
+	}
+
+	// sender side: local path ka sirf naam "/" separators ke saath jaata hai
+	if got := WireFilename(filepath.Join("subdir", "file.txt")); got != "file.txt" {
+		t.Fatalf("WireFilename = %q, want %q", got, "file.txt")
+	}
+	if got, want := LocalFilename(`subdir\file.txt`), filepath.Join("subdir", "file.txt"); got != want {
+		t.Fatalf("LocalFilename = %q, want %q", got, want)
+	}
+}
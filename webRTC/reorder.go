@@ -0,0 +1,106 @@
+package webRTC
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// Unordered data channel (DataChannelOptions.Ordered false) par SCTP messages kisi bhi order mein de sakta hai, jabki
+// receiver chunks ko aane ke order mein file ke end par likhta hai aur FILE_START/TRANSFER_COMPLETE markers bhi chunks ke
+// beech isi channel par jaate hai. Isliye aise channel par har message ek frame mein jaata hai:
+//
+//	[8 byte big-endian sequence][1 byte kind][payload]
+//
+// aur receiver frames ko sequence ke order mein lagakar hi aage deta hai. Ordered channel par kuch nahi badalta.
+// Dono peers ko yeh framing samajhni chahiye, isliye --unordered dono taraf same version par hi chalta hai.
+
+const (
+	frameHeaderSize = 9
+
+	frameKindBinary byte = 0
+	frameKindText   byte = 1
+
+	// itne frames aage ke ruk jaye aur beech wala na aaye toh use khoya hua maan kar aage badhte hai. Reliable channel par
+	// aisa nahi hota; MaxRetransmits/MaxPacketLifeTime wali partial reliability mein SCTP message chhod deta hai.
+	maxReorderFrames = 1024
+)
+
+// errShortFrame tab aata hai jab unordered channel par aaya message frame header se bhi chhota ho
+var errShortFrame = errors.New("unordered data channel frame shorter than its header")
+
+// sequencer ek unordered data channel ke frames number karta hai aur aaye frames ko wapas order mein lagata hai.
+// Har naye data channel ke saath naya sequencer banta hai, isliye Reset ke baad dono taraf numbering 0 se shuru hoti hai.
+type sequencer struct {
+	sendMu  sync.Mutex
+	sendSeq uint64
+
+	recvMu  sync.Mutex
+	next    uint64
+	pending map[uint64]webrtc.DataChannelMessage
+}
+
+func newSequencer() *sequencer {
+	return &sequencer{pending: make(map[uint64]webrtc.DataChannelMessage)}
+}
+
+// msg ko agle sequence number ke frame mein send se bhejta hai. Number sirf safal send par aage badhta hai,
+// warna receiver us number ka intezaar karta reh jaata.
+func (s *sequencer) send(msg outboxMessage, send func([]byte) error) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	kind := frameKindBinary
+	if msg.isString {
+		kind = frameKindText
+	}
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(msg.data))
+	binary.BigEndian.PutUint64(frame, s.sendSeq)
+	frame[8] = kind
+	frame = append(frame, msg.data...)
+	if err := send(frame); err != nil {
+		return err
+	}
+	s.sendSeq++
+	return nil
+}
+
+// receive ek aaya frame leta hai aur ab order mein diye ja sakne wale saare messages return karta hai (shayad koi nahi).
+// skipped batata hai ki buffer bharne par kitne khoye frames chhode gaye.
+func (s *sequencer) receive(frame []byte) (ready []webrtc.DataChannelMessage, skipped uint64, err error) {
+	if len(frame) < frameHeaderSize {
+		return nil, 0, errShortFrame
+	}
+	seq := binary.BigEndian.Uint64(frame)
+	msg := webrtc.DataChannelMessage{IsString: frame[8] == frameKindText, Data: frame[frameHeaderSize:]}
+
+	s.recvMu.Lock()
+	defer s.recvMu.Unlock()
+	if seq < s.next {
+		// khoya maan kar chhoda gaya frame der se aaya, ab uski jagah nikal chuki hai
+		return nil, 0, nil
+	}
+	s.pending[seq] = msg
+
+	if len(s.pending) > maxReorderFrames {
+		if _, ok := s.pending[s.next]; !ok {
+			lowest := seq
+			for n := range s.pending {
+				lowest = min(lowest, n)
+			}
+			skipped = lowest - s.next
+			s.next = lowest
+		}
+	}
+	for {
+		msg, ok := s.pending[s.next]
+		if !ok {
+			return ready, skipped, nil
+		}
+		delete(s.pending, s.next)
+		ready = append(ready, msg)
+		s.next++
+	}
+}
@@ -0,0 +1,76 @@
+package webRTC
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// frames ulte-seedhe order mein aaye tab bhi receive unhe bheje gaye order mein deta hai, text aur binary dono
+func TestSequencerReordersFrames(t *testing.T) {
+	sender, receiver := newSequencer(), newSequencer()
+	var frames [][]byte
+	var want []string
+	for i := 0; i < 200; i++ {
+		msg := outboxMessage{data: []byte(fmt.Sprintf("chunk-%d", i)), isString: i%50 == 0}
+		if err := sender.send(msg, func(frame []byte) error {
+			frames = append(frames, frame)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, string(msg.data))
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(frames), func(i, j int) { frames[i], frames[j] = frames[j], frames[i] })
+
+	var got []string
+	for _, frame := range frames {
+		ready, skipped, err := receiver.receive(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if skipped != 0 {
+			t.Fatalf("skipped %d frames on a lossless channel", skipped)
+		}
+		for _, m := range ready {
+			if wantText := len(got)%50 == 0; m.IsString != wantText {
+				t.Fatalf("message %d: IsString = %v, want %v", len(got), m.IsString, wantText)
+			}
+			got = append(got, string(m.Data))
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+}
+
+// khoya frame buffer bharne tak hi roka jaata hai, uske baad baaki frames aage jaate hai
+func TestSequencerSkipsLostFrame(t *testing.T) {
+	sender, receiver := newSequencer(), newSequencer()
+	var frames [][]byte
+	for i := 0; i < maxReorderFrames+2; i++ {
+		sender.send(outboxMessage{data: []byte{byte(i)}}, func(frame []byte) error {
+			frames = append(frames, frame)
+			return nil
+		})
+	}
+
+	delivered := 0
+	var skipped uint64
+	// frame 0 kabhi nahi aata
+	for _, frame := range frames[1:] {
+		ready, n, err := receiver.receive(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		delivered += len(ready)
+		skipped += n
+	}
+	if skipped != 1 || delivered != maxReorderFrames+1 {
+		t.Fatalf("skipped %d and delivered %d frames, want 1 and %d", skipped, delivered, maxReorderFrames+1)
+	}
+	// der se aaya khoya frame dobara nahi diya jaata
+	if ready, _, _ := receiver.receive(frames[0]); len(ready) != 0 {
+		t.Fatalf("late frame delivered after it was skipped")
+	}
+}
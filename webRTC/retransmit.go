@@ -0,0 +1,76 @@
+package webRTC
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MaxPieceRetransmits ek piece ke liye kitni baar NACK bhej kar dobara maanga ja sakta hai, uske baad poora transfer fail hai
+const MaxPieceRetransmits = 3
+
+// NackMessage NACK command hai: receiver ko fileHash ka piece hash mismatch mila, sender use dobara bheje.
+// pieceLength tracker ke piece index se aata hai taaki dono taraf piece ka offset same nikle.
+func NackMessage(fileHash, filename string, piece int, pieceLength int64) map[string]interface{} {
+	return map[string]interface{}{"command": "NACK", "file_hash": fileHash, "name": WireFilename(filename), "piece": piece, "piece_length": pieceLength}
+}
+
+// SendNack remote peer se ek kharab piece dobara maangta hai
+func (p *WebRTCPeer) SendNack(fileHash, filename string, piece int, pieceLength int64) error {
+	return p.Send(NackMessage(fileHash, filename, piece, pieceLength))
+}
+
+// SendPiece NACK ke jawab mein file ka ek piece data channel par dobara bhejta hai
+func (p *WebRTCPeer) SendPiece(ctx context.Context, filename, fileHash string, piece int, pieceLength int64) error {
+	// pool ho toh poora piece ek pool channel par jaata hai, taaki kai pieces ek saath chal sake
+	sendBinary, sendText := p.SendRaw, p.sendInBand
+	if dc, ok := p.acquirePoolChannel(); ok {
+		defer p.pool.Release(dc)
+		sendBinary, sendText = dc.Send, poolSendText(dc)
+	}
+	// last piece chhota ho sakta hai
+	total := min(pieceLength, fileSizeOrZero(filename)-int64(piece)*pieceLength)
+	send, done := p.trackSend(WireFilename(filename), max(total, 0), sendBinary)
+	defer done()
+	return SendPiece(ctx, filename, fileHash, piece, pieceLength, p.chunkSize(DefaultPieceSize), send, sendText)
+}
+
+// SendPiece transports ke liye common piece retransmission hai. PIECE_DATA header (offset aur size ke saath) ke baad
+// piece ke bytes binary chunks mein jaate hai aur end mein PIECE_END aata hai. Last piece pieceLength se chhota ho sakta hai.
+func SendPiece(ctx context.Context, filename, fileHash string, piece int, pieceLength int64, chunkSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultPieceSize
+	}
+	if piece < 0 || pieceLength <= 0 {
+		sendText(map[string]string{"error": "Invalid piece"})
+		return fmt.Errorf("invalid piece %d (length %d) for %s", piece, pieceLength, filename)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		sendText(map[string]string{"error": "Could not open file"})
+		return openError(filename, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", filename, err)
+	}
+	offset := int64(piece) * pieceLength
+	if offset >= info.Size() {
+		sendText(map[string]string{"error": "Invalid piece"})
+		return fmt.Errorf("piece %d is past the end of %s (%d bytes)", piece, filename, info.Size())
+	}
+	size := min(pieceLength, info.Size()-offset)
+
+	name := WireFilename(filename)
+	header := map[string]interface{}{"command": "PIECE_DATA", "name": name, "file_hash": fileHash, "piece": piece, "offset": offset, "size": size}
+	if err := sendText(header); err != nil {
+		return fmt.Errorf("failed to send PIECE_DATA: %w", err)
+	}
+	if err := streamChunks(ctx, io.NewSectionReader(file, offset, size), chunkSize, sendBinary, sendText); err != nil {
+		return fmt.Errorf("failed to send piece %d of %s: %w", piece, filename, err)
+	}
+	return sendText(map[string]interface{}{"command": "PIECE_END", "name": name, "file_hash": fileHash, "piece": piece})
+}
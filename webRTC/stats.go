@@ -0,0 +1,147 @@
+package webRTC
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/pion/webrtc/v3"
+)
+
+// ErrNoHostCandidate tab aata hai jab ICE gathering ne abhi tak koi local host candidate nahi banaya
+var ErrNoHostCandidate = errors.New("no local host ICE candidate gathered")
+
+// ConnectionStats ek peer connection ke live stats hai, pion ke GetStats report se nikale gaye
+type ConnectionStats struct {
+	RTTMs            float64 `json:"rtt_ms"`             // selected ICE candidate pair ka current round trip time
+	BytesSent        uint64  `json:"bytes_sent"`         // saare data channels par bheje gaye bytes
+	BytesReceived    uint64  `json:"bytes_received"`     // saare data channels par aaye bytes
+	PacketsLost      uint32  `json:"packets_lost"`       // inbound RTP streams ke lost packets (sirf data channels ho toh 0)
+	DataChannelState string  `json:"data_channel_state"` // binary data channel ka ready state
+}
+
+// Stats peer connection ke current stats return karta hai
+func (p *WebRTCPeer) Stats() (ConnectionStats, error) {
+	p.mu.RLock()
+	pc, dc := p.pc, p.dataChannel
+	p.mu.RUnlock()
+
+	var stats ConnectionStats
+	if pc == nil {
+		return stats, errors.New("peer connection not initialized")
+	}
+
+	stats.DataChannelState = "none"
+	if dc != nil {
+		stats.DataChannelState = dc.ReadyState().String()
+	}
+
+	nominated := false
+	for _, s := range pc.GetStats() {
+		switch s := s.(type) {
+		case webrtc.ICECandidatePairStats:
+			// nominated pair hi actual mein use ho raha hai, woh na mile toh koi bhi succeeded pair chalega
+			if s.Nominated || (!nominated && s.State == webrtc.StatsICECandidatePairStateSucceeded) {
+				stats.RTTMs = s.CurrentRoundTripTime * 1000
+				nominated = s.Nominated
+			}
+		case webrtc.DataChannelStats:
+			stats.BytesSent += s.BytesSent
+			stats.BytesReceived += s.BytesReceived
+		case webrtc.InboundRTPStreamStats:
+			if s.PacketsLost > 0 {
+				stats.PacketsLost += uint32(s.PacketsLost)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// DataChannelStats binary data channel (file chunks wala) ke counters hai, GetStats report aur
+// webrtc.DataChannel getters se nikale gaye
+type DataChannelStats struct {
+	Label            string                  `json:"label"`
+	State            webrtc.DataChannelState `json:"state"`
+	MessagesSent     uint32                  `json:"messages_sent"`
+	MessagesReceived uint32                  `json:"messages_received"`
+	BytesSent        uint64                  `json:"bytes_sent"`
+	BytesReceived    uint64                  `json:"bytes_received"`
+	BufferedAmount   uint64                  `json:"buffered_amount"` // send queue mein pade bytes, zyada ho toh receiver dheere padh raha hai
+}
+
+// DataChannelStats binary data channel ke current stats return karta hai. Channel abhi bana na ho (ya Reset ke
+// baad naya na khula ho) toh zero value milti hai; uska Label empty hota hai aur State "unknown".
+func (p *WebRTCPeer) DataChannelStats() DataChannelStats {
+	p.mu.RLock()
+	pc, dc := p.pc, p.dataChannel
+	p.mu.RUnlock()
+
+	var stats DataChannelStats
+	if dc == nil {
+		return stats
+	}
+	stats.Label = dc.Label()
+	stats.State = dc.ReadyState()
+	stats.BufferedAmount = dc.BufferedAmount()
+	if pc == nil || dc.ID() == nil {
+		return stats
+	}
+
+	// message aur byte counters DataChannel par getter ki tarah nahi milte, sirf GetStats report mein hai;
+	// report mein saare channels hote hai, isliye SCTP stream ID se apna channel chunte hai
+	for _, s := range pc.GetStats() {
+		if s, ok := s.(webrtc.DataChannelStats); ok && s.DataChannelIdentifier == int32(*dc.ID()) {
+			stats.MessagesSent, stats.MessagesReceived = s.MessagesSent, s.MessagesReceived
+			stats.BytesSent, stats.BytesReceived = s.BytesSent, s.BytesReceived
+			break
+		}
+	}
+	return stats
+}
+
+// ListenAddr woh local address (IP aur UDP/TCP port) hai jis par yeh connection ICE ke liye sun raha hai, GetStats
+// ke local host candidates se nikala gaya. Firewall rules ke liye kaam aata hai. Selected candidate pair ka local
+// candidate host ho toh wahi, warna sabse zyada priority wala host candidate. pion har PeerConnection ke liye naya
+// ephemeral port leta hai, isliye yeh address har peer (aur Reset ke baad) alag hota hai.
+func (p *WebRTCPeer) ListenAddr() (net.Addr, error) {
+	p.mu.RLock()
+	pc := p.pc
+	p.mu.RUnlock()
+	if pc == nil {
+		return nil, errors.New("peer connection not initialized")
+	}
+
+	report := pc.GetStats()
+	selected := ""
+	for _, s := range report {
+		if pair, ok := s.(webrtc.ICECandidatePairStats); ok && pair.Nominated {
+			selected = pair.LocalCandidateID
+		}
+	}
+	var best *webrtc.ICECandidateStats
+	for _, s := range report {
+		c, ok := s.(webrtc.ICECandidateStats)
+		if !ok || c.Type != webrtc.StatsTypeLocalCandidate || c.CandidateType != webrtc.ICECandidateTypeHost {
+			continue
+		}
+		if c.ID == selected {
+			best = &c
+			break
+		}
+		// report map hai, isliye barabar priority par ID se chunte hai taaki har baar wahi address aaye
+		if best == nil || c.Priority > best.Priority || (c.Priority == best.Priority && c.ID < best.ID) {
+			best = &c
+		}
+	}
+	if best == nil {
+		return nil, ErrNoHostCandidate
+	}
+	ip := net.ParseIP(best.IP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid host candidate address %q", best.IP)
+	}
+	if best.Protocol == "tcp" {
+		return &net.TCPAddr{IP: ip, Port: int(best.Port)}, nil
+	}
+	return &net.UDPAddr{IP: ip, Port: int(best.Port)}, nil
+}
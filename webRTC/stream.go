@@ -0,0 +1,37 @@
+package webRTC
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SendStream r ke bytes EOF tak name naam ki file ki tarah data channel par bhejta hai (jaise stdin ka pipe).
+// Size pehle se pata nahi hota, isliye FILE_START mein size 0 jaata hai.
+func (p *WebRTCPeer) SendStream(ctx context.Context, r io.Reader, name string) error {
+	send, done := p.trackSend(name, 0, p.SendRaw)
+	defer done()
+	return SendStream(ctx, r, name, p.chunkSize(DefaultPieceSize), send, p.sendInBand)
+}
+
+// SendStream transports ke liye common stream send hai: FILE_START (size 0), r ke chunks EOF tak,
+// aur end mein TRANSFER_COMPLETE. Receiver size 0 par bina space check ke file banata hai aur jo aaye likhta jaata hai.
+func SendStream(ctx context.Context, r io.Reader, name string, pieceSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	if pieceSize <= 0 {
+		pieceSize = DefaultPieceSize
+	}
+	name = WireFilename(name)
+	if name == "." || name == "/" {
+		return errors.New("stream needs a file name")
+	}
+
+	start := map[string]interface{}{"command": "FILE_START", "name": name, "size": 0}
+	if err := sendText(start); err != nil {
+		return fmt.Errorf("failed to send FILE_START: %w", err)
+	}
+	if err := streamChunks(ctx, r, pieceSize, sendBinary, sendText); err != nil {
+		return fmt.Errorf("failed to stream %s: %w", name, err)
+	}
+	return sendText(map[string]string{"status": "TRANSFER_COMPLETE", "name": name})
+}
@@ -0,0 +1,156 @@
+package webRTC
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/stun"
+	"github.com/pion/webrtc/v3"
+)
+
+// stunProbeTimeout tak binding response na aaye toh STUN server unreachable maana jaata hai
+const stunProbeTimeout = 2 * time.Second
+
+// stunProbeTTL tak ek server ka probe result reuse hota hai, taaki har naye peer par 2 second ka wait na lage
+// aur down server thodi der baad phir se try ho
+const stunProbeTTL = 5 * time.Minute
+
+type stunProbeResult struct {
+	ok bool
+	at time.Time
+}
+
+var stunProbes = struct {
+	mu      sync.Mutex
+	results map[string]stunProbeResult
+}{results: make(map[string]stunProbeResult)}
+
+// probeSTUNServer url ("stun:host:port") par STUN binding request bhejta hai aur stunProbeTimeout ke andar
+// success response aaye toh true return karta hai. "?transport=tcp" wale URLs TCP par probe hote hai.
+func probeSTUNServer(ctx context.Context, url string) bool {
+	uri, err := stun.ParseURI(url)
+	if err != nil || uri.Scheme != stun.SchemeTypeSTUN {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, stunProbeTimeout)
+	defer cancel()
+
+	network := "udp"
+	if uri.Proto == stun.ProtoTypeTCP {
+		network = "tcp"
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, net.JoinHostPort(uri.Host, strconv.Itoa(uri.Port)))
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
+	if _, err := conn.Write(req.Raw); err != nil {
+		return false
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return false
+		}
+		var res stun.Message
+		if err := stun.Decode(buf[:n], &res); err != nil {
+			continue
+		}
+		// kisi purane request ka der se aaya response ignore karte hai
+		if res.TransactionID == req.TransactionID {
+			return res.Type == stun.BindingSuccess
+		}
+	}
+}
+
+// cache mein result na ho ya purana ho toh server ko probe karta hai
+func probeSTUNServerCached(ctx context.Context, url string) bool {
+	stunProbes.mu.Lock()
+	r, ok := stunProbes.results[url]
+	stunProbes.mu.Unlock()
+	if ok && time.Since(r.at) < stunProbeTTL {
+		return r.ok
+	}
+
+	reachable := probeSTUNServer(ctx, url)
+	if !reachable {
+		logger.Warn("STUN server did not respond, skipping it", "url", url, "timeout", stunProbeTimeout)
+	}
+	stunProbes.mu.Lock()
+	stunProbes.results[url] = stunProbeResult{ok: reachable, at: time.Now()}
+	stunProbes.mu.Unlock()
+	return reachable
+}
+
+// responsiveICEServers servers mein se woh "stun:" URLs hata deta hai jo binding request ka jawab nahi dete, kyunki
+// pion har unreachable STUN server par ICE gathering ke timeout tak rukta hai. Saare STUN servers ek saath probe hote
+// hai; TURN aur STUNS URLs bina probe ke rehte hai. Koi STUN server jawab na de toh warning ke saath sirf host aur
+// relay candidates bante hai.
+func responsiveICEServers(ctx context.Context, servers []webrtc.ICEServer) []webrtc.ICEServer {
+	reachable := make(map[string]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		for _, u := range s.URLs {
+			if !isSTUNURL(u) {
+				continue
+			}
+			wg.Add(1)
+			go func(u string) {
+				defer wg.Done()
+				ok := probeSTUNServerCached(ctx, u)
+				mu.Lock()
+				reachable[u] = ok
+				mu.Unlock()
+			}(u)
+		}
+	}
+	wg.Wait()
+	if len(reachable) == 0 {
+		return servers
+	}
+
+	anyReachable := false
+	filtered := make([]webrtc.ICEServer, 0, len(servers))
+	for _, s := range servers {
+		var urls []string
+		for _, u := range s.URLs {
+			ok, probed := reachable[u]
+			if !probed || ok {
+				urls = append(urls, u)
+			}
+			anyReachable = anyReachable || ok
+		}
+		if len(urls) > 0 {
+			s.URLs = urls
+			filtered = append(filtered, s)
+		}
+	}
+	if !anyReachable {
+		logger.Warn("None of the configured STUN servers responded, connections may fail behind NAT without a TURN relay")
+	}
+	return filtered
+}
+
+// sirf plain "stun:" URLs probe hote hai, "stuns:" ke liye TLS handshake chahiye
+func isSTUNURL(u string) bool {
+	uri, err := stun.ParseURI(u)
+	return err == nil && uri.Scheme == stun.SchemeTypeSTUN
+}
+
+// config ke responsive ICE servers ke saath naya PeerConnection banata hai
+func newPeerConnection(cfg Config) (*webrtc.PeerConnection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), stunProbeTimeout)
+	defer cancel()
+	return webrtc.NewPeerConnection(webrtc.Configuration{ICEServers: responsiveICEServers(ctx, cfg.ICEServers)})
+}
@@ -0,0 +1,75 @@
+package webRTC
+
+import (
+	"errors"
+	"time"
+)
+
+// CancelReasonReceiveTimeout CANCEL message ka reason hai jab sender ne ReceiveTimeout tak koi chunk nahi bheja
+const CancelReasonReceiveTimeout = "RECEIVE_TIMEOUT"
+
+// DefaultReceiveTimeout itni der tak ek bhi chunk na aaye toh download atka hua maana jaata hai
+const DefaultReceiveTimeout = 2 * time.Minute
+
+// ErrReceiveTimeout stalled download ke "error" event ka Cause hai
+var ErrReceiveTimeout = errors.New("receive timed out")
+
+// receive deadline itne interval par check hoti hai
+const receiveCheckInterval = time.Second
+
+// SetReceiveTimeout set karta hai ki download ke dauraan kitni der tak chunk na aane par transfer abort ho.
+// Abort par sender ko RECEIVE_TIMEOUT ka CANCEL jaata hai, file writer band hota hai aur ErrReceiveTimeout wala
+// "error" event aata hai. 0 ho toh timeout band.
+func (p *WebRTCPeer) SetReceiveTimeout(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.receiveTimeout = d
+	p.resetReceiveDeadline()
+}
+
+// deadline ko abhi se receiveTimeout aage karta hai; p.mu pakad kar call karna hai
+func (p *WebRTCPeer) resetReceiveDeadline() {
+	if p.receiveTimeout > 0 {
+		p.receiveDeadline = time.Now().Add(p.receiveTimeout)
+	}
+}
+
+// NewWebRTCPeer aur Reset se start hota hai; failed close hone par (connection fail/close ya Reset) ruk jaata hai
+func (p *WebRTCPeer) watchReceiveDeadline(failed <-chan struct{}) {
+	ticker := time.NewTicker(receiveCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-failed:
+			return
+		case now := <-ticker.C:
+			p.checkReceiveDeadline(now)
+		}
+	}
+}
+
+// chalte hue download ki deadline nikal gayi ho toh use abort karta hai
+func (p *WebRTCPeer) checkReceiveDeadline(now time.Time) {
+	p.mu.Lock()
+	if !p.receiving || p.receiveTimeout <= 0 || now.Before(p.receiveDeadline) {
+		p.mu.Unlock()
+		return
+	}
+	timeout := p.receiveTimeout
+	writer := p.fileWriter
+	p.fileWriter = nil
+	p.receiving = false
+	ev := TransferEvent{Type: TransferFailed, Filename: p.transferName, BytesDone: p.transferDone, TotalBytes: p.transferTotal, Cause: ErrReceiveTimeout}
+	p.mu.Unlock()
+
+	logger.Warn("No data from peer, aborting download", "peer", p.RemotePeerID(), "file", ev.Filename, "timeout", timeout)
+	if err := p.SendCancel(ev.Filename, CancelReasonReceiveTimeout); err != nil {
+		logger.Debug("Failed to send cancel", "file", ev.Filename, "error", err)
+	}
+	if writer != nil {
+		if err := writer.Close(); err != nil {
+			logger.Warn("Failed to close stalled file", "file", ev.Filename, "error", err)
+		}
+	}
+	p.emit(ev)
+}
@@ -2,32 +2,81 @@ package webRTC
 
 import (
 	"fmt"
+	"io"
 )
 
-// yeh function, file size ko human-readable format mein convert karta hai
+// yeh function, file size ko human-readable format mein convert karta hai (IEC units, FormatFileSizeIEC jaisa)
 func FormatFileSize(bytes int64) string {
-	const unit = 1024
+	return FormatFileSizeIEC(bytes)
+}
+
+// FormatFileSizeIEC 1024 ki powers use karta hai: KiB, MiB, GiB, ...
+func FormatFileSizeIEC(bytes int64) string {
+	return formatSize(bytes, 1024, []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"})
+}
+
+// FormatFileSizeSI 1000 ki powers use karta hai: kB, MB, GB, ...
+func FormatFileSizeSI(bytes int64) string {
+	return formatSize(bytes, 1000, []string{"kB", "MB", "GB", "TB", "PB", "EB"})
+}
+
+func formatSize(bytes, unit int64, units []string) string {
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
 	}
 
 	//div - divisor. Decide karega ki bytes ko kis value se divide karna hai
-	//exp - exponent. "KMGTPE" string se sahi unit decide karega
-	div, exp := int64(unit), 0
+	//exp - exponent. units slice se sahi unit decide karega
+	div, exp := unit, 0
 	for n := bytes / unit; n >= unit; n /= unit {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	value := float64(bytes) / float64(div)
+	// 1 GiB - 1 jaise values round hokar "1024.0 MiB" na dikhe, isliye agli unit mein le jaate hai
+	if value >= float64(unit)-0.05 && exp < len(units)-1 {
+		value /= float64(unit)
+		exp++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[exp])
 }
 
-func PrintClientInstructions() {
-	fmt.Println(`
+// PrintClientInstructions client ke saare commands ki help w par likhta hai
+func PrintClientInstructions(w io.Writer) {
+	fmt.Fprintln(w, `
 📖 Torrentium Client Commands:
   help          - Show this help message.
   add <path>    - Announce a local file to the tracker.
+  add --dry-run <path> - Print the hash, size and pieces without announcing.
+  info <file>   - Show the .torrent metadata (pieces, sizes, hashes) of a local file.
   list          - List all files available on the tracker.
+  list-local    - List the files this node is seeding.
   listpeers     - List all currently online peers.
+  connect <addr> - Connect to a peer directly and open a WebRTC channel (falls back to QUIC).
+  connect-all [--filter-tag <tag>] - Connect to every online peer (optionally only peers sharing files with a tag).
+  disconnect <peer_id> - Close the WebRTC/QUIC and libp2p connections to a peer.
+  peers         - Show known libp2p peers and their WebRTC state.
+  peers import <file> - Add peer addresses from a JSON file ([{"peer_id": ..., "multiaddr": ...}]) to the peerstore.
+  peers export <file> - Write the peerstore's peer addresses to a JSON file in the same format.
+  status        - Show active WebRTC connections with tracker metadata and in-flight transfers.
   get <file_id> - Find and download a file from a peer.
+  get-range <file_id> <start> <end> [peer_id] - Download only bytes [start, end) from a connected peer.
+  sync <peer_id> - Download every file a connected peer shares that this node does not have.
+  send-dir <dir> [peer_id] - Send a directory as a tar.gz archive to a connected peer.
+  pipe <peer_id> <remote_filename> - Stream data to a peer (only via the torrentium CLI, e.g. tar cf - . | torrentium pipe <peer> backup.tar).
+  verify <file> - Check a shared file on disk against its announced hash.
+  tag <file> <tag>   - Add a category tag (video, audio, document, ...) to a file.
+  untag <file> <tag> - Remove a tag from a file.
+  stats         - Show network totals from the tracker: files, total size, online/offline peers, bytes transferred.
+  top-seeders [limit] - Show the peers that uploaded the most bytes (default 10).
+  announce      - Re-send this node's announced files to every connected peer via gossip.
+  remove <file> - Retract this node's announcement of a file from the tracker.
+  ban <peer_id> <duration|permanent> [reason] - Refuse connections from a peer (e.g. ban <id> 24h).
+  unban <peer_id> - Lift a ban.
+  allow <filename> <peer_id> - Only listed peers may download this file (first allow restricts it).
+  deny <filename> <peer_id> - Remove a peer from the file's allow list (empty list = public).
+  export [json|csv] <file> - Write the tracker's full file catalog to a JSON or CSV file.
+  at-risk [threshold] - List files whose seeders have all been offline longer than threshold (default 24h).
+  history <peer_id> [limit] - Show a peer's recent tracker events (connects, announces, transfers).
   exit          - Shutdown the client.`)
 }
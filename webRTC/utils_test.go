@@ -0,0 +1,32 @@
+package webRTC
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatFileSize(t *testing.T) {
+	tests := []struct {
+		bytes   int64
+		wantIEC string
+		wantSI  string
+	}{
+		{bytes: 0, wantIEC: "0 B", wantSI: "0 B"},
+		{bytes: 1023, wantIEC: "1023 B", wantSI: "1.0 kB"},
+		{bytes: 1024, wantIEC: "1.0 KiB", wantSI: "1.0 kB"},
+		{bytes: 1<<30 - 1, wantIEC: "1.0 GiB", wantSI: "1.1 GB"},
+		{bytes: math.MaxInt64, wantIEC: "8.0 EiB", wantSI: "9.2 EB"},
+	}
+	for _, tt := range tests {
+		if got := FormatFileSizeIEC(tt.bytes); got != tt.wantIEC {
+			t.Errorf("FormatFileSizeIEC(%d) = %q, want %q", tt.bytes, got, tt.wantIEC)
+		}
+		if got := FormatFileSizeSI(tt.bytes); got != tt.wantSI {
+			t.Errorf("FormatFileSizeSI(%d) = %q, want %q", tt.bytes, got, tt.wantSI)
+		}
+	}
+	// FormatFileSize default IEC hai
+	if got := FormatFileSize(1024); got != "1.0 KiB" {
+		t.Errorf("FormatFileSize(1024) = %q, want IEC units", got)
+	}
+}
@@ -3,34 +3,84 @@ package webRTC
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pion/webrtc/v3"
+
+	"torrentium/logging"
+)
+
+// webRTC package ka logger
+var logger = logging.For("webRTC")
+
+// data channels ke labels: text commands "control" par aur binary file chunks "data" par jaate hai
+const (
+	ControlChannelLabel = "control"
+	DataChannelLabel    = "data"
 )
 
+// outbox mein kitne messages queue ho sakte hai
+const outboxSize = 256
+
+// channel abhi open nahi hua, message queue kiya ja sakta hai
+var errChannelNotOpen = errors.New("data channel not open yet")
+
+// ErrTimeout tab aata hai jab WaitForConnection ke timeout tak connection nahi banta
+var ErrTimeout = errors.New("timed out waiting for connection")
+
+// ErrConnectionFailed tab aata hai jab wait karte hue hi connection failed/closed ho jaata hai
+var ErrConnectionFailed = errors.New("peer connection failed")
+
+// outbox mein rakha ek message
+type outboxMessage struct {
+	data     []byte
+	isString bool
+	inBand   bool // text jo control ke bajaye data channel par, file chunks ke order mein jaata hai
+}
+
+// DefaultPieceSize file bhejte waqt ek data channel message ka default size hai (16 KiB, SCTP ke liye safe)
+const DefaultPieceSize = 16 * 1024
+
+// DefaultMaxMessageSize ek binary data channel message ki default upper limit hai
+const DefaultMaxMessageSize = 16 * 1024
+
+// pion v3 ka SCTP transport 64 KiB se bade messages nahi bhej sakta (pion/webrtc#758), aur yeh limit
+// pion se set nahi hoti. Isliye SetMaxMessageSize isse upar ki value ko yahin clamp kar deta hai.
+const sctpMaxMessageSize = 64 * 1024
+
 // data channel pe aane wale messages ko handle karta hai
 type DataChannelMessageHandler func(webrtc.DataChannelMessage, *WebRTCPeer)
 
-// yeh struct ek webRTC connection aur related state ko show karta hai
-type WebRTCPeer struct {
-	pc              *webrtc.PeerConnection
-	dataChannel     *webrtc.DataChannel
-	onMessage       DataChannelMessageHandler
-	fileWriter      io.WriteCloser
-	state           webrtc.PeerConnectionState
-	connectedSignal chan struct{} // Jab connection successfully ban jata hai to yeh channel close ho jata hai
-	mu              sync.RWMutex  //concurrent access se protect karne ke liye
-	signalingStream network.Stream
+// DataChannelOptions file transfer wale data channel ki reliability aur ordering decide karta hai.
+// MaxRetransmits ya MaxPacketLifeTime set karne par SCTP partial reliability use hoti hai.
+type DataChannelOptions struct {
+	Ordered           bool
+	MaxRetransmits    *uint16
+	MaxPacketLifeTime *uint16
 }
 
-// ek naya webRTC peer bnata hai
-func NewWebRTCPeer(onMessage DataChannelMessageHandler) (*WebRTCPeer, error) {
-	config := webrtc.Configuration{
+// Config WebRTC peer banane ke saare tunable options rakhta hai.
+// DataChannelPoolSize offer karne wala peer itne extra "data-i" channels kholta hai jin par retransmit kiye pieces
+// ek saath chalte hai; 0 ho toh pool nahi banta. Purane peers in channels ko nahi samajhte, isliye default 0 hai.
+// ReceiveTimeout har naye peer par SetReceiveTimeout se lagta hai.
+type Config struct {
+	ICEServers          []webrtc.ICEServer
+	DataChannel         DataChannelOptions
+	DataChannelPoolSize int
+	ReceiveTimeout      time.Duration
+}
+
+// DefaultConfig default ICE servers aur reliable, ordered data channel ke saath config return karta hai
+func DefaultConfig() Config {
+	return Config{
 		ICEServers: []webrtc.ICEServer{
 			// Cloudflare STUN (free)
 			{URLs: []string{"stun:stun.cloudflare.com:3478"}},
@@ -49,77 +99,349 @@ func NewWebRTCPeer(onMessage DataChannelMessageHandler) (*WebRTCPeer, error) {
 			// Backup STUN servers
 			{URLs: []string{"stun:stun.l.google.com:19302"}},
 		},
+		DataChannel:    DataChannelOptions{Ordered: true},
+		ReceiveTimeout: DefaultReceiveTimeout,
+	}
+}
+
+// SetTURNServers config ke saare TURN/TURNS servers ko diye gaye servers se replace karta hai, STUN servers waise hi rehte hai
+func (c *Config) SetTURNServers(servers []webrtc.ICEServer) {
+	kept := c.ICEServers[:0:0]
+	for _, s := range c.ICEServers {
+		isTURN := false
+		for _, u := range s.URLs {
+			if strings.HasPrefix(u, "turn:") || strings.HasPrefix(u, "turns:") {
+				isTURN = true
+				break
+			}
+		}
+		if !isTURN {
+			kept = append(kept, s)
+		}
 	}
+	c.ICEServers = append(kept, servers...)
+}
+
+// options se pion ka DataChannelInit banata hai
+func (o DataChannelOptions) init() *webrtc.DataChannelInit {
+	ordered := o.Ordered
+	return &webrtc.DataChannelInit{
+		Ordered:           &ordered,
+		MaxRetransmits:    o.MaxRetransmits,
+		MaxPacketLifeTime: o.MaxPacketLifeTime,
+	}
+}
+
+// yeh struct ek webRTC connection aur related state ko show karta hai
+type WebRTCPeer struct {
+	ReceiveDir         string // peer se aayi files sirf is directory mein likhi jaati hai (default ./downloads)
+	MaxReceiveFileSize int64  // isse badi aane wali files CANCEL ho jaati hai, 0 ho toh koi limit nahi
+	config             Config
+	pc                 *webrtc.PeerConnection
+	controlChannel     *webrtc.DataChannel // text commands (REQUEST_FILE, GOSSIP_FILES, ...) ke liye
+	dataChannel        *webrtc.DataChannel // binary file chunks ke liye
+	dataSeq            *sequencer          // dataChannel unordered ho toh uske frames ka order, warna nil
+	onMessage          DataChannelMessageHandler
+	fileWriter         io.WriteCloser
+	fileWriterAt       *os.File // SetFileWriterAt wali pre-allocated file, WriteAt se likhi jaati hai
+	fileWriterAtSize   int64
+	state              webrtc.PeerConnectionState
+	connectedSignal    chan struct{} // Jab connection successfully ban jata hai to yeh channel close ho jata hai
+	failedSignal       chan struct{} // connection failed ya closed hone par close hota hai, taaki waiters turant laut sake
+	mu                 sync.RWMutex  //concurrent access se protect karne ke liye
+	signalingStream    network.Stream
+	remotePeerID       peer.ID                            // reconnect ke liye, empty ho toh pata nahi hai
+	maxMessageSize     int                                // file chunks isse bade nahi bheje jaate, SetMaxMessageSize se badalta hai
+	stateCallbacks     []func(webrtc.PeerConnectionState) // OnConnectionStateChange se register hote hai
+	transitionMu       sync.Mutex                         // state changes (aur unke callbacks) ek-ek karke, aane ke order mein chalte hai
+
+	hmacMu           sync.Mutex // hmacKey aur sequence numbers ko protect karta hai
+	hmacKey          []byte     // EnableHMAC ke baad set hota hai, nil ho toh chunks bina HMAC jaate hai
+	sendSeq, recvSeq uint64     // bheje aur aaye binary frames ke sequence numbers (HMAC mein shaamil)
+
+	outbox   chan outboxMessage // channel open hone se pehle bheje gaye messages yahan ruk jaate hai
+	flushMu  sync.Mutex         // outbox ek time par ek hi goroutine drain kare
+	dataOpen chan struct{}      // data channel open hone par close hota hai
+
+	events          chan TransferEvent // transfer progress ke events
+	transferName    string
+	transferTotal   int64
+	transferDone    int64
+	transferStarted time.Time
+	receiving       bool          // SetTransferInfo se CompleteTransfer tak true
+	receiveTimeout  time.Duration // 0 ho toh stalled downloads abort nahi hote
+	receiveDeadline time.Time     // har aaye chunk par receiveTimeout aage badhti hai
+
+	sends      map[uint64]*TransferInfo // chalte hue outgoing transfers, trackSend se register hote hai
+	nextSendID uint64
+
+	pool          *DataChannelPool // pieces pipeline karne ke liye "data-i" channels
+	pieceHandlers PieceHandlers    // pool channels par aaye pieces kahan likhne hai
+}
 
-	// Naya peer connection banate hain.
-	pc, err := webrtc.NewPeerConnection(config)
+// ek naya webRTC peer bnata hai
+func NewWebRTCPeer(onMessage DataChannelMessageHandler, cfg Config) (*WebRTCPeer, error) {
+	// Naya peer connection banate hain. pion ICE servers connection bante waqt hi fix kar deta hai, isliye
+	// unreachable STUN servers CreateOffer/CreateAnswer se pehle yahin hat jaate hai.
+	pc, err := newPeerConnection(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create peer connection: %w", err)
 	}
 
 	peer := &WebRTCPeer{
+		ReceiveDir:      DefaultReceiveDir,
+		config:          cfg,
 		pc:              pc,
 		onMessage:       onMessage,
+		state:           webrtc.PeerConnectionStateNew,
 		connectedSignal: make(chan struct{}),
+		failedSignal:    make(chan struct{}),
+		events:          make(chan TransferEvent, 64),
+		outbox:          make(chan outboxMessage, outboxSize),
+		dataOpen:        make(chan struct{}),
+		maxMessageSize:  DefaultMaxMessageSize,
+		pool:            NewDataChannelPool(),
+		receiveTimeout:  cfg.ReceiveTimeout,
 	}
 
+	peer.watchPeerConnection(pc)
+	// atke hue downloads abort karne ke liye
+	go peer.watchReceiveDeadline(peer.failedSignal)
+
+	return peer, nil
+}
+
+// pc ke state changes aur remote ke khole data channels is peer tak laata hai. Reset ke baad purane pc ke
+// der se aaye events ignore hote hai, taaki woh naye connection ko band na kar de.
+func (p *WebRTCPeer) watchPeerConnection(pc *webrtc.PeerConnection) {
 	//this handles change in connection states
-	pc.OnConnectionStateChange(peer.handleConnectionStateChange)
+	pc.OnConnectionStateChange(func(s webrtc.PeerConnectionState) {
+		p.handleConnectionStateChange(pc, s)
+	})
 	// Jab remote peer ek data channel kholta hai
-	pc.OnDataChannel(peer.handleDataChannel)
+	pc.OnDataChannel(p.handleDataChannel)
+}
 
-	return peer, nil
+// pc abhi bhi is peer ka connection hai ya Reset se badal chuka hai
+func (p *WebRTCPeer) isCurrent(pc *webrtc.PeerConnection) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pc == pc
 }
 
-func (p *WebRTCPeer) handleConnectionStateChange(s webrtc.PeerConnectionState) {
+// Reset failed ya closed connection ki jagah usi Config se naya PeerConnection banata hai aur handlers dobara
+// register karta hai. Peer object, uske events channel aur OnConnectionStateChange callbacks waise hi rehte hai,
+// isliye caller ko map mein peer replace nahi karna padta: Reset ke baad bas CreateOffer/CreateAnswer se dobara signaling.
+// Beech mein ruka download "error" event (ErrConnectionFailed) ke saath band hota hai.
+func (p *WebRTCPeer) Reset() error {
+	pc, err := newPeerConnection(p.config)
+	if err != nil {
+		return fmt.Errorf("failed to create peer connection: %w", err)
+	}
+
 	p.mu.Lock()
+	old := p.pc
+	p.pc = pc
+	p.state = webrtc.PeerConnectionStateNew
+	p.controlChannel, p.dataChannel, p.dataSeq = nil, nil, nil
+	// purane waiters aur deadline watcher chhod dete hai
+	closeSignal(p.failedSignal)
+	p.connectedSignal = make(chan struct{})
+	p.failedSignal = make(chan struct{})
+	p.dataOpen = make(chan struct{})
+	p.pool.close()
+	p.pool = NewDataChannelPool()
+	signaling := p.signalingStream
+	p.signalingStream = nil
+	writer := p.fileWriter
+	p.fileWriter = nil
+	writerAt := p.fileWriterAt
+	p.fileWriterAt, p.fileWriterAtSize = nil, 0
+	var failedEv *TransferEvent
+	if p.receiving {
+		failedEv = &TransferEvent{Type: TransferFailed, Filename: p.transferName, BytesDone: p.transferDone, TotalBytes: p.transferTotal, Cause: ErrConnectionFailed}
+		p.receiving = false
+	}
+	failed := p.failedSignal
+	p.mu.Unlock()
+
+	// naye connection par dono side sequence numbers 0 se shuru karte hai
+	p.hmacMu.Lock()
+	p.sendSeq, p.recvSeq = 0, 0
+	p.hmacMu.Unlock()
+
+	if signaling != nil {
+		signaling.Close()
+	}
+	if old != nil {
+		old.Close()
+	}
+	if writer != nil {
+		writer.Close()
+	}
+	if writerAt != nil {
+		writerAt.Close()
+	}
+	if failedEv != nil {
+		p.emit(*failedEv)
+	}
+
+	p.watchPeerConnection(pc)
+	go p.watchReceiveDeadline(failed)
+	logger.Debug("Peer connection reset", "peer", p.RemotePeerID())
+	return nil
+}
+
+// pc ka state change peer par lagata hai. pc Reset se badal chuka ho toh (purane connection ka der se aaya event) ignore hota hai.
+func (p *WebRTCPeer) handleConnectionStateChange(pc *webrtc.PeerConnection, s webrtc.PeerConnectionState) {
+	// pion har state change alag goroutine se deta hai; bina lock ke Failed ke callbacks aur uske baad aaya Closed aapas mein race karte
+	p.transitionMu.Lock()
+	defer p.transitionMu.Unlock()
+	if !p.isCurrent(pc) {
+		return
+	}
+
+	p.mu.Lock()
+	if p.state == webrtc.PeerConnectionStateClosed {
+		// closed peer dobara kisi state mein nahi jaata, baad mein aaye ICE events ignore karte hai
+		p.mu.Unlock()
+		return
+	}
+	changed := p.state != s
 	p.state = s // this line updates the state change
+	callbacks := p.stateCallbacks
+	connected, failed := p.connectedSignal, p.failedSignal
 	p.mu.Unlock()
 
-	log.Printf("Peer Connection State has changed: %s\n", s.String())
+	logger.Info("Peer connection state has changed", "state", s.String())
+	if s == webrtc.PeerConnectionStateFailed || s == webrtc.PeerConnectionStateClosed {
+		// purana connection callbacks se pehle band karte hai: callback reconnect shuru kar sakta hai, aur uske Reset
+		// ke baad yeh Close naye PeerConnection ko band kar deta
+		closeSignal(failed)
+		p.Close()
+	}
+
+	// callbacks p.mu ke bahar chalate hai taaki woh peer ke methods call kar sake
+	if changed {
+		for _, fn := range callbacks {
+			fn(s)
+		}
+	}
 
 	if s == webrtc.PeerConnectionStateConnected {
 		//Jab connection ban jata hai, `connectedSignal` channel ko close karte hain
 		// Yeh `WaitForConnection` mein waiting goroutine ko signal dega
-		select {
-		case <-p.connectedSignal:
-		default:
-			close(p.connectedSignal)
-		}
-	} else if s == webrtc.PeerConnectionStateFailed || s == webrtc.PeerConnectionStateClosed {
-		p.Close()
+		closeSignal(connected)
 	}
 }
 
 // handleDataChannel tab call hota hai jab remote peer ek data channel banata hai.
 func (p *WebRTCPeer) handleDataChannel(dc *webrtc.DataChannel) {
-	log.Printf("New DataChannel %q (ID: %d) received!", dc.Label(), dc.ID())
+	logger.Debug("New data channel received", "label", dc.Label())
+	if IsPoolChannelLabel(dc.Label()) {
+		p.handlePoolChannel(dc)
+		return
+	}
+	isControl := dc.Label() == ControlChannelLabel
+	var seq *sequencer
+	if !isControl && !dc.Ordered() {
+		seq = newSequencer()
+	}
 	p.mu.Lock()
-	p.dataChannel = dc
+	if isControl {
+		p.controlChannel = dc
+	} else {
+		p.dataChannel, p.dataSeq = dc, seq
+	}
+	pc := p.pc
 	p.mu.Unlock()
 
 	dc.OnOpen(func() {
-		log.Printf("Data channel '%s' (ID: %d) opened!", dc.Label(), dc.ID())
+		if !p.isCurrent(pc) {
+			return
+		}
+		logger.Info("Data channel opened", "label", dc.Label())
+		if !isControl {
+			p.mu.Lock()
+			select {
+			case <-p.dataOpen:
+			default:
+				close(p.dataOpen)
+			}
+			p.mu.Unlock()
+		}
 		// The connection is now fully established
-		p.handleConnectionStateChange(webrtc.PeerConnectionStateConnected)
+		p.handleConnectionStateChange(pc, webrtc.PeerConnectionStateConnected)
+		// channel khulne se pehle queue hue messages ab bhej dete hai
+		p.flushOutbox()
 	})
-	dc.OnMessage(func(msg webrtc.DataChannelMessage) {
+	deliver := func(msg webrtc.DataChannelMessage) {
+		// label ke hisab se route karte hai: control channel par sirf commands, data channel par file chunks
+		if isControl {
+			msg.IsString = true
+		} else if !msg.IsString {
+			data, err := p.openChunk(msg.Data)
+			if err != nil {
+				// badla hua chunk file mein nahi likhte; file ka hash check baad mein kharab piece pakad leta hai
+				logger.Warn("Dropping file chunk", "peer", p.RemotePeerID(), "file", p.currentTransferName(), "error", err)
+				p.emit(TransferEvent{Type: TransferFailed, Filename: p.currentTransferName()})
+				return
+			}
+			msg.Data = data
+			p.recordReceived(len(msg.Data))
+		}
 		p.onMessage(msg, p)
+	}
+	dc.OnMessage(func(msg webrtc.DataChannelMessage) {
+		if seq == nil {
+			deliver(msg)
+			return
+		}
+		// unordered channel: frames sequence ke order mein hi aage jaate hai, HMAC check bhi usi order mein hota hai
+		ready, skipped, err := seq.receive(msg.Data)
+		if err != nil {
+			logger.Warn("Dropping data channel message", "peer", p.RemotePeerID(), "error", err)
+			return
+		}
+		if skipped > 0 {
+			logger.Warn("Data channel messages lost", "peer", p.RemotePeerID(), "file", p.currentTransferName(), "count", skipped)
+			p.emit(TransferEvent{Type: TransferFailed, Filename: p.currentTransferName()})
+		}
+		for _, m := range ready {
+			deliver(m)
+		}
 	})
 	dc.OnClose(func() {
-		log.Printf("Data channel '%s' (ID: %d) closed!", dc.Label(), dc.ID())
-		p.handleConnectionStateChange(webrtc.PeerConnectionStateClosed)
+		logger.Info("Data channel closed", "label", dc.Label())
+		// Reset ke baad purane connection ke channels band hone se naya connection band nahi hota
+		p.handleConnectionStateChange(pc, webrtc.PeerConnectionStateClosed)
 	})
 }
 
 // CreateOffer ek offer SDP generate karta hai
 func (p *WebRTCPeer) CreateOffer() (string, error) {
-	dc, err := p.pc.CreateDataChannel("data", nil)
+	// control channel hamesha reliable aur ordered rehta hai, commands kabhi drop nahi hone chahiye
+	control, err := p.pc.CreateDataChannel(ControlChannelLabel, nil)
+	if err != nil {
+		return "", err
+	}
+	p.handleDataChannel(control)
+
+	dc, err := p.pc.CreateDataChannel(DataChannelLabel, p.config.DataChannel.init())
 	if err != nil {
 		return "", err
 	}
 	p.handleDataChannel(dc)
 
+	// pool channels reliable aur ordered hote hai, kyunki piece ke bytes file mein seedha offset se likhe jaate hai
+	for i := 0; i < min(p.config.DataChannelPoolSize, MaxDataChannelPoolSize); i++ {
+		pc, err := p.pc.CreateDataChannel(PoolChannelLabel(i), nil)
+		if err != nil {
+			return "", err
+		}
+		p.handleDataChannel(pc)
+	}
+
 	// Offer create karte hain
 	offer, err := p.pc.CreateOffer(nil)
 	if err != nil {
@@ -172,16 +494,39 @@ func (p *WebRTCPeer) SetAnswer(answerSDP string) error {
 	return p.pc.SetRemoteDescription(answer)
 }
 
+// signal channel ko ek hi baar close karta hai (state callbacks ek ke baad ek aate hai)
+func closeSignal(ch chan struct{}) {
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
 // yeh function, specific timeout tak connection establish hone ka wait karta hai
 func (p *WebRTCPeer) WaitForConnection(timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	return p.WaitForConnectionContext(context.Background(), timeout)
+}
 
+// WaitForConnectionContext WaitForConnection jaisa hai, bas ctx cancel hone par bhi ruk jaata hai.
+// Timeout ya failure par *TransferError milta hai jo ErrTimeout ya ErrConnectionFailed wrap karta hai.
+// Koi polling nahi hoti: state callback signal channels close karta hai aur yeh unpar select karta hai.
+func (p *WebRTCPeer) WaitForConnectionContext(ctx context.Context, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	p.mu.RLock()
+	connected, failed := p.connectedSignal, p.failedSignal
+	p.mu.RUnlock()
 	select {
-	case <-p.connectedSignal:
+	case <-connected:
 		return nil
+	case <-failed:
+		return &TransferError{Peer: p.RemotePeerID(), Cause: ErrConnectionFailed}
+	case <-timer.C:
+		return &TransferError{Peer: p.RemotePeerID(), Cause: ErrTimeout}
 	case <-ctx.Done():
-		return fmt.Errorf("timed out waiting for connection: %w", ctx.Err())
+		return ctx.Err()
 	}
 }
 
@@ -192,6 +537,92 @@ func (p *WebRTCPeer) IsConnected() bool {
 	return p.state == webrtc.PeerConnectionStateConnected
 }
 
+// OnConnectionStateChange ek callback register karta hai jo har connection state change par call hota hai,
+// taaki caller disconnect hone par react kar sake (jaise reconnect karna)
+func (p *WebRTCPeer) OnConnectionStateChange(fn func(webrtc.PeerConnectionState)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stateCallbacks = append(p.stateCallbacks, fn)
+}
+
+// remote peer ka libp2p ID set karta hai, iske bina reconnect nahi ho sakta
+func (p *WebRTCPeer) SetRemotePeerID(id peer.ID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remotePeerID = id
+}
+
+func (p *WebRTCPeer) RemotePeerID() peer.ID {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.remotePeerID
+}
+
+// peer ka current connection state return karta hai (new, connecting, connected, ...)
+func (p *WebRTCPeer) State() webrtc.PeerConnectionState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.state
+}
+
+// ICEConnectionState underlying PeerConnection ka ICE state hai (checking, connected, disconnected, failed, ...).
+// State() se zyada detail deta hai, jaise ICE checking mein atka hai ya candidates fail ho gaye, NAT traversal debug karne ke liye.
+func (p *WebRTCPeer) ICEConnectionState() webrtc.ICEConnectionState {
+	p.mu.RLock()
+	pc := p.pc
+	p.mu.RUnlock()
+	return pc.ICEConnectionState()
+}
+
+// ICEGatheringState batata hai ki local ICE candidates (host, STUN, TURN) ikatthe ho rahe hai ya ho chuke hai
+func (p *WebRTCPeer) ICEGatheringState() webrtc.ICEGatheringState {
+	p.mu.RLock()
+	pc := p.pc
+	p.mu.RUnlock()
+	return pc.ICEGatheringState()
+}
+
+// SetMaxMessageSize file chunks ka maximum size set karta hai, taaki bade binary sends SCTP layer par drop na ho.
+// bytes <= 0 ho toh DefaultMaxMessageSize lagta hai, aur pion ki 64 KiB limit se upar ki value clamp ho jaati hai.
+func (p *WebRTCPeer) SetMaxMessageSize(bytes int) {
+	if bytes <= 0 {
+		bytes = DefaultMaxMessageSize
+	}
+	if bytes > sctpMaxMessageSize {
+		bytes = sctpMaxMessageSize
+	}
+	p.mu.Lock()
+	p.maxMessageSize = bytes
+	p.mu.Unlock()
+}
+
+// MaxMessageSize ek binary message (file chunk) ka current maximum size hai
+func (p *WebRTCPeer) MaxMessageSize() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.maxMessageSize
+}
+
+// pieceSize ko MaxMessageSize tak cap karta hai; pieceSize <= 0 ho toh DefaultPieceSize se shuru hota hai
+func (p *WebRTCPeer) chunkSize(pieceSize int) int {
+	if pieceSize <= 0 {
+		pieceSize = DefaultPieceSize
+	}
+	size := p.MaxMessageSize()
+	if p.HMACEnabled() {
+		// HMAC tag bhi usi message mein jaata hai
+		size -= HMACSize
+	}
+	p.mu.RLock()
+	sequenced := p.dataSeq != nil
+	p.mu.RUnlock()
+	if sequenced {
+		// unordered channel ka frame header bhi
+		size -= frameHeaderSize
+	}
+	return min(pieceSize, size)
+}
+
 func (p *WebRTCPeer) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -200,6 +631,10 @@ func (p *WebRTCPeer) Close() error {
 		p.signalingStream.Close()
 		p.signalingStream = nil
 	}
+	// pool ka intezaar kar rahe senders ko chhod dete hai
+	p.pool.close()
+	// deadline watcher band
+	closeSignal(p.failedSignal)
 
 	if p.pc != nil && p.state != webrtc.PeerConnectionStateClosed {
 		return p.pc.Close()
@@ -214,29 +649,256 @@ func (p *WebRTCPeer) SetSignalingStream(s network.Stream) {
 	p.signalingStream = s
 }
 
-// data ko JSON mein serialize kare data channels pe bhjeta hai
+// data ko JSON mein serialize kare data channels pe bhjeta hai.
+// Channel abhi open nahi hua ho toh message outbox mein queue ho jaata hai aur open hone par chala jaata hai.
 func (p *WebRTCPeer) Send(data interface{}) error {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	if !p.IsConnected() {
-		return fmt.Errorf("data channel not open")
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
 	}
+	return p.sendOrQueue(outboxMessage{data: bytes, isString: true})
+}
 
+// sendInBand data ko JSON text ki tarah file chunks wale data channel par bhejta hai. Control aur data alag SCTP
+// streams hai jinke beech order ki koi guarantee nahi, isliye FILE_START/TRANSFER_COMPLETE jaise transfer markers
+// chunks ke saath isi channel par jaate hai; warna TRANSFER_COMPLETE aakhri chunks se pehle pahunch kar file band kar deta hai.
+func (p *WebRTCPeer) sendInBand(data interface{}) error {
 	bytes, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	return p.dataChannel.SendText(string(bytes))
+	return p.sendOrQueue(outboxMessage{data: bytes, isString: true, inBand: true})
 }
 
-// file data ko bytes mein data channel par bhejta hai.
+// file data ko bytes mein data channel par bhejta hai, channel open na ho toh queue karta hai.
 func (p *WebRTCPeer) SendRaw(data []byte) error {
+	return p.sendBinary(data, p.sendOrQueue)
+}
+
+// SendWithTimeout data channel open hone ka zyada se zyada timeout tak wait karta hai aur phir data bhejta hai.
+// Queue karne ke bajaye channel time par na khule toh error return karta hai.
+func (p *WebRTCPeer) SendWithTimeout(data []byte, timeout time.Duration) error {
+	p.mu.RLock()
+	dataOpen := p.dataOpen
+	p.mu.RUnlock()
+	select {
+	case <-dataOpen:
+	case <-time.After(timeout):
+		return fmt.Errorf("data channel did not open within %s", timeout)
+	}
+	return p.sendBinary(data, p.sendNow)
+}
+
+// text messages control channel par jaate hai, purane peers jo control channel nahi kholte unke liye data channel par.
+// In-band text (transfer markers) hamesha data channel par jaata hai.
+func (p *WebRTCPeer) channelFor(msg outboxMessage) *webrtc.DataChannel {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if msg.isString && !msg.inBand && p.controlChannel != nil {
+		return p.controlChannel
+	}
+	return p.dataChannel
+}
+
+// message ko turant bhejta hai, channel abhi bana/khula na ho toh errChannelNotOpen
+func (p *WebRTCPeer) sendNow(msg outboxMessage) error {
+	dc := p.channelFor(msg)
+	if dc == nil {
+		return errChannelNotOpen
+	}
+	switch state := dc.ReadyState(); state {
+	case webrtc.DataChannelStateOpen:
+	case webrtc.DataChannelStateConnecting:
+		return errChannelNotOpen
+	default:
+		return fmt.Errorf("%w: %s is %s", ErrChannelClosed, dc.Label(), state)
+	}
+	if seq := p.sequencerFor(dc); seq != nil {
+		return seq.send(msg, dc.Send)
+	}
+	if msg.isString {
+		return dc.SendText(string(msg.data))
+	}
+	return dc.Send(msg.data)
+}
+
+// dc unordered data channel ho toh uska sequencer, warna nil
+func (p *WebRTCPeer) sequencerFor(dc *webrtc.DataChannel) *sequencer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if dc != p.dataChannel {
+		return nil
+	}
+	return p.dataSeq
+}
+
+func (p *WebRTCPeer) sendOrQueue(msg outboxMessage) error {
+	err := p.sendNow(msg)
+	if !errors.Is(err, errChannelNotOpen) {
+		return err
+	}
+	// caller buffer reuse kar sakta hai (jaise SendFile), isliye copy queue karte hai
+	msg.data = append([]byte(nil), msg.data...)
+	select {
+	case p.outbox <- msg:
+	default:
+		return fmt.Errorf("outbox full (%d messages), data channel not open", outboxSize)
+	}
+	// queue karte waqt channel khul gaya ho toh OnOpen ka flush miss ho sakta hai
+	if dc := p.channelFor(msg); dc != nil && dc.ReadyState() == webrtc.DataChannelStateOpen {
+		p.flushOutbox()
+	}
+	return nil
+}
+
+// outbox ke messages bhejta hai, jinka channel abhi bhi open nahi hai woh wapas queue ho jaate hai
+func (p *WebRTCPeer) flushOutbox() {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	for n := len(p.outbox); n > 0; n-- {
+		msg := <-p.outbox
+		err := p.sendNow(msg)
+		if errors.Is(err, errChannelNotOpen) {
+			select {
+			case p.outbox <- msg:
+			default:
+				logger.Warn("Outbox full, dropping queued message")
+			}
+			continue
+		}
+		if err != nil {
+			logger.Warn("Failed to send queued message", "error", err)
+		}
+	}
+}
+
+// SendTextData Send jaisa hi hai, FileTransport interface ke naam se (QUIC transport bhi yahi API deta hai)
+func (p *WebRTCPeer) SendTextData(data interface{}) error {
+	return p.Send(data)
+}
+
+// SendBinaryData SendRaw jaisa hi hai, FileTransport interface ke naam se.
+// Fail hone par *TransferError milta hai jismein chal rahe transfer ka naam hota hai.
+func (p *WebRTCPeer) SendBinaryData(data []byte) error {
+	if err := p.checkConnected(); err != nil {
+		return p.transferError(p.currentTransferName(), err)
+	}
+	if err := p.SendRaw(data); err != nil {
+		return p.transferError(p.currentTransferName(), err)
+	}
+	return nil
+}
+
+// remote peer se ek file maangta hai, file data channel par binary chunks mein aati hai.
+// Connection band ho toh *TransferError (ErrNotConnected) milta hai.
+func (p *WebRTCPeer) RequestFile(fileID string) error {
+	if err := p.checkConnected(); err != nil {
+		return p.transferError(fileID, err)
+	}
+	if err := p.Send(map[string]string{"command": "REQUEST_FILE", "file_id": fileID}); err != nil {
+		return p.transferError(fileID, err)
+	}
+	return nil
+}
+
+// closed ya failed connection par ErrNotConnected; connecting peers ke messages outbox mein queue ho sakte hai
+func (p *WebRTCPeer) checkConnected() error {
+	switch p.State() {
+	case webrtc.PeerConnectionStateClosed, webrtc.PeerConnectionStateFailed:
+		return ErrNotConnected
+	}
+	return nil
+}
+
+func (p *WebRTCPeer) transferError(filename string, err error) *TransferError {
+	return &TransferError{Filename: filename, Peer: p.RemotePeerID(), Cause: err}
+}
+
+// abhi chal rahe transfer ka naam (SetTransferInfo se), koi transfer na ho toh empty
+func (p *WebRTCPeer) currentTransferName() string {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	if !p.IsConnected() {
-		return fmt.Errorf("data channel not open")
+	return p.transferName
+}
+
+// SendFileWithContext file ko pieceSize ke chunks mein data channel par bhejta hai aur end mein
+// TRANSFER_COMPLETE status bhejta hai. Chunks MaxMessageSize se bade nahi hote.
+// Har chunk ke beech ctx check hota hai, cancel hone par transfer ruk jaata hai.
+func (p *WebRTCPeer) SendFileWithContext(ctx context.Context, filename string, pieceSize int) error {
+	send, done := p.trackSend(WireFilename(filename), fileSizeOrZero(filename), p.SendBinaryData)
+	defer done()
+	return SendFile(ctx, filename, p.chunkSize(pieceSize), send, p.sendInBand)
+}
+
+// SendFile transports ke liye common chunked send loop hai, taaki QUIC fallback bhi yahi logic use kare.
+// Chunks se pehle FILE_START bhejta hai; text/JSON/XML files gzip hokar jaati hai aur tab "compress" flag set hota hai.
+func SendFile(ctx context.Context, filename string, pieceSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	if pieceSize <= 0 {
+		pieceSize = DefaultPieceSize
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		sendText(map[string]string{"error": "Could not open file"})
+		return openError(filename, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", filename, err)
+	}
+
+	// MIME detection ke liye shuru ke 512 bytes padh kar wapas start par aate hai
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", filename, err)
+	}
+
+	var src io.Reader = file
+	start := map[string]interface{}{"command": "FILE_START", "name": WireFilename(filename), "size": info.Size()}
+	if shouldCompress(filename, head[:n]) {
+		gz := gzipReader(file)
+		defer gz.Close()
+		src = gz
+		start["compress"] = CompressionGzip
+	}
+	if err := sendText(start); err != nil {
+		return fmt.Errorf("failed to send FILE_START: %w", err)
+	}
+
+	if err := streamChunks(ctx, src, pieceSize, sendBinary, sendText); err != nil {
+		return fmt.Errorf("failed to send %s: %w", filename, err)
+	}
+	// Send a "transfer complete" message so the receiver can clean up.
+	return sendText(map[string]string{"status": "TRANSFER_COMPLETE", "name": WireFilename(filename)})
+}
+
+// src ko EOF tak pieceSize ke binary chunks mein bhejta hai, har chunk ke beech ctx check hota hai
+func streamChunks(ctx context.Context, src io.Reader, pieceSize int, sendBinary func([]byte) error, sendText func(interface{}) error) error {
+	buffer := make([]byte, pieceSize)
+	for {
+		select {
+		case <-ctx.Done():
+			sendText(map[string]string{"error": "Transfer cancelled"})
+			return ctx.Err()
+		default:
+		}
+
+		bytesRead, err := src.Read(buffer)
+		if bytesRead > 0 {
+			if err := sendBinary(buffer[:bytesRead]); err != nil {
+				return fmt.Errorf("failed to send chunk: %w", err)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil // End of file
+			}
+			return fmt.Errorf("read failed: %w", err)
+		}
 	}
-	return p.dataChannel.Send(data)
 }
 
 // Creates an empty file on your computer  (only called once)
@@ -0,0 +1,293 @@
+package webRTC
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pion/webrtc/v3"
+
+	"torrentium/p2p"
+)
+
+// integration tests ka poora budget: hosts, signaling, ICE aur transfer isi ke andar khatam hone chahiye
+const integrationTimeout = 30 * time.Second
+
+// localhost par sunne wala libp2p host, test khatam hone par band
+func newTestHost(t *testing.T) host.Host {
+	t.Helper()
+	h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+// receivedFile receiver side ka chhota handler hai: FILE_START par ReceiveDir mein file kholta hai, chunks likhta hai
+// aur TRANSFER_COMPLETE par file band karke done close karta hai. Markers chunks ke saath data channel par aate hai,
+// isliye TRANSFER_COMPLETE ke baad koi chunk nahi aana chahiye; aaye toh band file par Write fail hota hai.
+type receivedFile struct {
+	t        *testing.T
+	done     chan struct{}
+	once     sync.Once
+	path     string
+	maxChunk int // sabse bada aaya binary message
+}
+
+func newReceivedFile(t *testing.T) *receivedFile {
+	return &receivedFile{t: t, done: make(chan struct{})}
+}
+
+func (r *receivedFile) onMessage(msg webrtc.DataChannelMessage, p *WebRTCPeer) {
+	if !msg.IsString {
+		r.maxChunk = max(r.maxChunk, len(msg.Data))
+		if w := p.GetFileWriter(); w != nil {
+			if _, err := w.Write(msg.Data); err != nil {
+				r.t.Errorf("writing chunk: %v", err)
+			}
+		} else {
+			r.t.Errorf("received %d bytes before FILE_START", len(msg.Data))
+		}
+		return
+	}
+	command, err := ParseCommand(string(msg.Data))
+	if err != nil {
+		r.t.Errorf("un-parseable message %q: %v", msg.Data, err)
+		return
+	}
+	switch cmd := command.(type) {
+	case FileStartCommand:
+		f, err := p.ReceiveFile(cmd.Filename)
+		if err != nil {
+			r.t.Errorf("opening received file: %v", err)
+			return
+		}
+		r.path = f.(*os.File).Name()
+		p.SetFileWriter(f)
+	case FileEndCommand:
+		if w := p.GetFileWriter(); w != nil {
+			w.Close()
+		}
+		r.once.Do(func() { close(r.done) })
+	}
+}
+
+// wait transfer poora hone tak rukta hai aur aayi file ke bytes deta hai
+func (r *receivedFile) wait(ctx context.Context) []byte {
+	r.t.Helper()
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		r.t.Fatal("timed out waiting for the transfer to complete")
+	}
+	got, err := os.ReadFile(r.path)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return got
+}
+
+// connectPeers do libp2p hosts banata hai, dono par signaling handler register karta hai aur client ki tarah
+// offer/answer exchange karke do connected WebRTC peers deta hai. Answer dene wala peer (receiver) onMessage use karta hai
+// aur uski files receiveDir mein aati hai.
+func connectPeers(ctx context.Context, t *testing.T, onMessage DataChannelMessageHandler, receiveDir string) (offerer, answerer *WebRTCPeer) {
+	t.Helper()
+	// localhost par ICE ke liye STUN/TURN ki zarurat nahi
+	return connectPeersWithConfig(ctx, t, Config{DataChannel: DataChannelOptions{Ordered: true}}, onMessage, receiveDir)
+}
+
+// connectPeersWithConfig connectPeers jaisa hai, bas offer karne wala peer cfg se data channel banata hai
+func connectPeersWithConfig(ctx context.Context, t *testing.T, cfg Config, onMessage DataChannelMessageHandler, receiveDir string) (offerer, answerer *WebRTCPeer) {
+	t.Helper()
+	hostA, hostB := newTestHost(t), newTestHost(t)
+	answered := make(chan *WebRTCPeer, 1)
+	noOffers := func(string, string, network.Stream) (string, error) {
+		t.Error("sender received an unexpected offer")
+		return "", io.EOF
+	}
+	p2p.RegisterSignalingProtocol(hostA, p2p.NewPeerManager(), noOffers)
+	p2p.RegisterSignalingProtocol(hostB, p2p.NewPeerManager(), func(offer, remotePeerID string, s network.Stream) (string, error) {
+		p, err := NewWebRTCPeer(onMessage, cfg)
+		if err != nil {
+			return "", err
+		}
+		p.ReceiveDir = receiveDir
+		answer, err := p.CreateAnswer(offer)
+		if err != nil {
+			p.Close()
+			return "", err
+		}
+		answered <- p
+		return answer, nil
+	})
+	if err := hostA.Connect(ctx, peer.AddrInfo{ID: hostB.ID(), Addrs: hostB.Addrs()}); err != nil {
+		t.Fatal(err)
+	}
+
+	offerer, err := NewWebRTCPeer(func(webrtc.DataChannelMessage, *WebRTCPeer) {}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { offerer.Close() })
+
+	// ek goroutine offer bhejti hai, doosri (yeh test) answer ka intezaar karti hai
+	answers := make(chan string, 1)
+	errs := make(chan error, 1)
+	go func() {
+		s, err := hostA.NewStream(ctx, hostB.ID(), p2p.SignalingProtocolID)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer s.Close()
+		enc, dec := json.NewEncoder(s), json.NewDecoder(s)
+		if err := p2p.NegotiateSignalingVersion(enc, dec); err != nil {
+			errs <- err
+			return
+		}
+		offer, err := offerer.CreateOffer()
+		if err != nil {
+			errs <- err
+			return
+		}
+		if err := enc.Encode(offer); err != nil {
+			errs <- err
+			return
+		}
+		var answer string
+		if err := dec.Decode(&answer); err != nil {
+			errs <- err
+			return
+		}
+		answers <- answer
+	}()
+
+	select {
+	case answer := <-answers:
+		if err := offerer.SetAnswer(answer); err != nil {
+			t.Fatal(err)
+		}
+	case err := <-errs:
+		t.Fatalf("signaling failed: %v", err)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the answer")
+	}
+	answerer = <-answered
+	t.Cleanup(func() { answerer.Close() })
+
+	for _, p := range []*WebRTCPeer{offerer, answerer} {
+		if err := p.WaitForConnectionContext(ctx, integrationTimeout); err != nil {
+			t.Fatalf("WebRTC connection did not come up: %v", err)
+		}
+	}
+	return offerer, answerer
+}
+
+func TestWebRTCFileTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts two libp2p hosts and a real WebRTC connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	recv := newReceivedFile(t)
+	sender, _ := connectPeers(ctx, t, recv.onMessage, t.TempDir())
+
+	want := make([]byte, 1<<20)
+	rand.Read(want)
+	if err := sender.SendStream(ctx, bytes.NewReader(want), "payload.bin"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := recv.wait(ctx)
+	if filepath.Base(recv.path) != "payload.bin" {
+		t.Errorf("received file saved as %s, want payload.bin", recv.path)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("received %d bytes that differ from the %d bytes sent", len(got), len(want))
+	}
+}
+
+func TestSmallMaxMessageSizeDeliversFileIntact(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts two libp2p hosts and a real WebRTC connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	recv := newReceivedFile(t)
+	sender, _ := connectPeers(ctx, t, recv.onMessage, t.TempDir())
+	sender.SetMaxMessageSize(256)
+
+	// random bytes gzip nahi hote, isliye har chunk file ka hi hissa hai
+	want := make([]byte, 10*1024)
+	rand.Read(want)
+	src := filepath.Join(t.TempDir(), "small-chunks.bin")
+	if err := os.WriteFile(src, want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.SendFileWithContext(ctx, src, DefaultPieceSize); err != nil {
+		t.Fatal(err)
+	}
+
+	got := recv.wait(ctx)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("received %d bytes that differ from the %d bytes sent", len(got), len(want))
+	}
+	if recv.maxChunk > 256 {
+		t.Fatalf("largest chunk was %d bytes, want at most 256", recv.maxChunk)
+	}
+}
+
+func TestUnorderedDataChannelDeliversFileIntact(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts two libp2p hosts and a real WebRTC connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	recv := newReceivedFile(t)
+	sender, receiver := connectPeersWithConfig(ctx, t, Config{DataChannel: DataChannelOptions{Ordered: false}}, recv.onMessage, t.TempDir())
+	secret := []byte("unordered test secret")
+	sender.EnableHMAC(secret)
+	receiver.EnableHMAC(secret)
+	for _, p := range []*WebRTCPeer{sender, receiver} {
+		p.mu.RLock()
+		dataOpen := p.dataOpen
+		p.mu.RUnlock()
+		select {
+		case <-dataOpen:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the data channel")
+		}
+		p.mu.RLock()
+		sequenced := p.dataSeq != nil
+		p.mu.RUnlock()
+		if !sequenced {
+			t.Fatal("unordered data channel is not sequenced")
+		}
+	}
+
+	want := make([]byte, 1<<20)
+	rand.Read(want)
+	if err := sender.SendStream(ctx, bytes.NewReader(want), "unordered.bin"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := recv.wait(ctx)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("received %d bytes that differ from the %d bytes sent", len(got), len(want))
+	}
+}
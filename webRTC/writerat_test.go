@@ -0,0 +1,78 @@
+package webRTC
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteAtConcurrentPieces(t *testing.T) {
+	const (
+		workers   = 8
+		pieceSize = 16 * 1024
+		pieces    = 64
+		size      = pieces*pieceSize - 1000 // aakhri piece chhota hai
+	)
+	want := make([]byte, size)
+	rand.Read(want)
+
+	p := &WebRTCPeer{ReceiveDir: t.TempDir()}
+	if err := p.SetFileWriterAt("assembled.bin", size); err != nil {
+		t.Fatal(err)
+	}
+
+	// har goroutine apne hisse ke pieces ulte order mein likhti hai, taaki writes sequential na ho
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := pieces - 1 - w; i >= 0; i -= workers {
+				start := int64(i) * pieceSize
+				end := min(start+pieceSize, size)
+				if _, err := p.WriteAt(want[start:end], start); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if err := p.CloseFileWriterAt(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(p.ReceiveDir, "assembled.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("assembled file differs from the source (%d bytes, want %d)", len(got), len(want))
+	}
+}
+
+func TestWriteAtBounds(t *testing.T) {
+	p := &WebRTCPeer{ReceiveDir: t.TempDir()}
+	if _, err := p.WriteAt([]byte("x"), 0); !errors.Is(err, ErrNoFileWriterAt) {
+		t.Fatalf("WriteAt before SetFileWriterAt: err = %v, want ErrNoFileWriterAt", err)
+	}
+	if err := p.SetFileWriterAt("small.bin", 4); err != nil {
+		t.Fatal(err)
+	}
+	defer p.CloseFileWriterAt()
+	if _, err := p.WriteAt([]byte("abc"), 2); err == nil {
+		t.Fatal("write past the end of the file succeeded")
+	}
+	if _, err := p.WriteAt([]byte("a"), -1); err == nil {
+		t.Fatal("write at a negative offset succeeded")
+	}
+}